@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+)
+
+// metrics compared between results, lower is better for all of them.
+// Optional metrics are only compared when both results measured them.
+var metrics = []struct {
+	name     string
+	value    func(r Result) float64
+	optional bool
+}{
+	{"ns/op", func(r Result) float64 { return r.NsPerOp }, false},
+	{"B/op", func(r Result) float64 { return float64(r.BytesPerOp) }, false},
+	{"allocs/op", func(r Result) float64 { return float64(r.AllocsPerOp) }, false},
+	{"live-B", func(r Result) float64 { return float64(r.LiveHeapBytes) }, true},
+}
+
+// Comparison compares a metric of one benchmark between two sets of results.
+type Comparison struct {
+	Name       string
+	Metric     string
+	Old, New   float64 // Medians.
+	Delta      float64 // Change of the median in percent.
+	P          float64 // p-value of the Mann-Whitney U test.
+	Regression bool    // Significantly worse by more than the threshold.
+}
+
+// compare compares the benchmarks present in both old and cur. A benchmark
+// regressed when its median got worse by more than threshold percent and the
+// difference is significant at level alpha.
+func compare(old, cur *Results, threshold, alpha float64) []Comparison {
+	oldRuns, curRuns := groupResults(old), groupResults(cur)
+	var names []string
+	for name := range curRuns {
+		if _, ok := oldRuns[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var cmp []Comparison
+	for _, name := range names {
+		for _, m := range metrics {
+			var x, y []float64
+			for _, r := range oldRuns[name] {
+				x = append(x, m.value(r))
+			}
+			for _, r := range curRuns[name] {
+				y = append(y, m.value(r))
+			}
+			if m.optional && (allZero(x) || allZero(y)) {
+				continue
+			}
+			c := Comparison{Name: name, Metric: m.name, Old: median(x), New: median(y)}
+			_, c.P = mannWhitneyU(x, y)
+			switch {
+			case c.Old != 0:
+				c.Delta = (c.New - c.Old) / c.Old * 100
+			case c.New != 0:
+				c.Delta = math.Inf(1)
+			}
+			c.Regression = c.Delta > threshold && c.P < alpha
+			cmp = append(cmp, c)
+		}
+	}
+	return cmp
+}
+
+func groupResults(results *Results) map[string][]Result {
+	groups := make(map[string][]Result)
+	for _, r := range results.Results {
+		groups[r.Name()] = append(groups[r.Name()], r)
+	}
+	return groups
+}
+
+func allZero(x []float64) bool {
+	for _, v := range x {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func median(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), x...)
+	sort.Float64s(s)
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
+
+func writeComparison(w io.Writer, cmp []Comparison) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tmetric\told\tnew\tdelta\tp\t")
+	for _, c := range cmp {
+		mark := ""
+		if c.Regression {
+			mark = "REGRESSION"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.4g\t%.4g\t%+.2f%%\t%.3f\t%s\n", c.Name, c.Metric, c.Old, c.New, c.Delta, c.P, mark)
+	}
+	return tw.Flush()
+}
+
+// mannWhitneyU returns the U statistic of x and the two-sided p-value of
+// the Mann-Whitney U test that x and y come from the same distribution.
+// The p-value is exact for small samples without ties and uses the normal
+// approximation with tie correction otherwise.
+func mannWhitneyU(x, y []float64) (u, p float64) {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+	type sample struct {
+		v     float64
+		first bool
+	}
+	all := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Tied values share the average of their ranks.
+	var rankSum, tieTerm float64
+	ties := false
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				rankSum += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	u = rankSum - float64(n1*(n1+1))/2
+
+	if !ties && n1 <= 50 && n2 <= 50 {
+		return u, exactMannWhitneyP(n1, n2, u)
+	}
+	n := float64(n1 + n2)
+	mu := float64(n1*n2) / 2
+	sigma := math.Sqrt(float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return u, math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactMannWhitneyP returns the two-sided p-value of U statistic u for
+// samples of n1 and n2 values without ties.
+func exactMannWhitneyP(n1, n2 int, u float64) float64 {
+	// counts[j][v] is the number of orderings of n1' values of x and j values
+	// of y with U = v, built up one value of x at a time.
+	max := n1 * n2
+	counts := make([][]float64, n2+1)
+	for j := range counts {
+		counts[j] = make([]float64, max+1)
+		counts[j][0] = 1
+	}
+	for i := 1; i <= n1; i++ {
+		next := make([][]float64, n2+1)
+		for j := range next {
+			next[j] = make([]float64, max+1)
+			for v := 0; v <= max; v++ {
+				// The largest value is either from x, exceeding the j values
+				// of y, or from y.
+				if v >= j {
+					next[j][v] += counts[j][v-j]
+				}
+				if j > 0 {
+					next[j][v] += next[j-1][v]
+				}
+			}
+		}
+		counts = next
+	}
+	var total, below float64
+	k := int(math.Min(u, float64(max)-u))
+	for v, c := range counts[n2] {
+		total += c
+		if v <= k {
+			below += c
+		}
+	}
+	return math.Min(1, 2*below/total)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	testCases := []struct {
+		x, y []float64
+		u, p float64
+	}{
+		// Exact: 2 of the C(10,5) = 252 orderings are as extreme.
+		{[]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 0, 2.0 / 252},
+		{[]float64{6, 7, 8, 9, 10}, []float64{1, 2, 3, 4, 5}, 25, 2.0 / 252},
+		{[]float64{1, 3, 5}, []float64{2, 4, 6}, 3, 0.7},
+		{[]float64{1}, []float64{2}, 0, 1},
+		{[]float64{5, 5, 5}, []float64{5, 5, 5}, 4.5, 1},
+		{nil, []float64{1}, 0, 1},
+	}
+	for _, tc := range testCases {
+		u, p := mannWhitneyU(tc.x, tc.y)
+		if u != tc.u || math.Abs(p-tc.p) > 1e-9 {
+			t.Errorf("%v, %v: want U=%v p=%v, got U=%v p=%v", tc.x, tc.y, tc.u, tc.p, u, p)
+		}
+	}
+
+	// Ties use the normal approximation.
+	_, p := mannWhitneyU([]float64{100, 100, 100, 100, 100}, []float64{200, 200, 200, 200, 200})
+	if p > 0.01 {
+		t.Errorf("constant samples that differ: p=%v", p)
+	}
+	_, p = mannWhitneyU([]float64{1, 2, 2, 3, 4}, []float64{2, 3, 3, 4, 5})
+	if p < 0.1 {
+		t.Errorf("overlapping samples: p=%v", p)
+	}
+}
+
+func testResults(name string, parts int, ns ...float64) []Result {
+	var results []Result
+	for i, v := range ns {
+		results = append(results, Result{Codec: name, Parts: parts, Versions: 1, Run: i, NsPerOp: v, BytesPerOp: 100, AllocsPerOp: 2})
+	}
+	return results
+}
+
+func TestCompare(t *testing.T) {
+	old := &Results{}
+	cur := &Results{}
+	old.Results = append(old.Results, testResults("msgpack-fast", 1, 100, 101, 99, 100, 102)...)
+	cur.Results = append(cur.Results, testResults("msgpack-fast", 1, 120, 121, 119, 122, 120)...)
+	old.Results = append(old.Results, testResults("msgpack-fast", 50, 100, 101, 99, 100, 102)...)
+	cur.Results = append(cur.Results, testResults("msgpack-fast", 50, 103, 104, 102, 103, 105)...)
+	old.Results = append(old.Results, testResults("msgpack-last", 1, 100, 130, 90, 110, 95)...)
+	cur.Results = append(cur.Results, testResults("msgpack-last", 1, 125, 85, 120, 100, 115)...)
+	old.Results = append(old.Results, testResults("jsoniter-fast", 1, 100)...)
+
+	// The live heap is not measured and not compared.
+	cmp := compare(old, cur, 5, 0.05)
+	if len(cmp) != 3*(len(metrics)-1) {
+		t.Fatalf("want %d comparisons, got %d", 3*(len(metrics)-1), len(cmp))
+	}
+	regressions := make(map[string]bool)
+	for _, c := range cmp {
+		if c.Regression {
+			regressions[c.Name+" "+c.Metric] = true
+		}
+	}
+	// 50 parts got 3% slower, under the threshold; msgpack-last is noise.
+	if len(regressions) != 1 || !regressions["msgpack-fast-1x1 ns/op"] {
+		t.Errorf("unexpected regressions %v", regressions)
+	}
+
+	var buf bytes.Buffer
+	if err := writeComparison(&buf, cmp); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "REGRESSION") != 1 {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestCompareLiveHeap(t *testing.T) {
+	old := &Results{Results: testResults("msgpack-fast", 50, 100, 101, 99, 100, 102)}
+	cur := &Results{Results: testResults("msgpack-fast", 50, 100, 101, 99, 100, 102)}
+	for i := range old.Results {
+		old.Results[i].LiveHeapBytes = 1000 + int64(i)
+		cur.Results[i].LiveHeapBytes = 2000 + int64(i)
+	}
+	cmp := compare(old, cur, 5, 0.05)
+	if len(cmp) != len(metrics) {
+		t.Fatalf("want %d comparisons, got %d", len(metrics), len(cmp))
+	}
+	for _, c := range cmp {
+		if c.Regression != (c.Metric == "live-B") {
+			t.Errorf("%s %s: regression %v", c.Name, c.Metric, c.Regression)
+		}
+	}
+
+	// Results of older runs lack the live heap.
+	for i := range old.Results {
+		old.Results[i].LiveHeapBytes = 0
+	}
+	if cmp := compare(old, cur, 5, 0.05); len(cmp) != len(metrics)-1 {
+		t.Errorf("want %d comparisons without the live heap, got %d", len(metrics)-1, len(cmp))
+	}
+}
@@ -0,0 +1,129 @@
+// Command xl-meta-bench runs the metadata decoding benchmarks and compares
+// their results.
+//
+//	xl-meta-bench run [-codecs list] [-ms list] [-ns list] [-count n] [-profile dir] [-o file]
+//	xl-meta-bench compare [-threshold percent] [-alpha p] old new
+//
+// Results are written as JSON, or as CSV when the output file ends in .csv.
+// Besides the usual benchmark metrics they hold the growth of the live heap
+// while one decoded value is alive.
+// compare exits with status 1 when a benchmark regressed significantly.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: xl-meta-bench run [flags]")
+	fmt.Fprintln(os.Stderr, "       xl-meta-bench compare [flags] old new")
+	os.Exit(2)
+}
+
+func main() {
+	// testing.Benchmark reads the -test.benchtime flag.
+	testing.Init()
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCmd(os.Args[2:])
+	case "compare":
+		var regressed bool
+		regressed, err = compareCmd(os.Args[2:])
+		if err == nil && regressed {
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "xl-meta-bench:", err)
+		os.Exit(2)
+	}
+}
+
+func runCmd(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	codecs := fs.String("codecs", strings.Join(codecNames(), ","), "comma separated codecs to run")
+	ms := fs.String("ms", "1,50,1000,10000", "comma separated part counts")
+	ns := fs.String("ns", "1,50,1000,10000", "comma separated version counts")
+	count := fs.Int("count", 5, "runs of each benchmark")
+	benchtime := fs.Duration("benchtime", time.Second, "minimum time of each run")
+	out := fs.String("o", "", "output file, stdout if empty")
+	profile := fs.String("profile", "", "directory to write CPU, heap and allocs profiles of each benchmark to")
+	fs.Parse(args)
+
+	cfg := runConfig{Codecs: strings.Split(*codecs, ","), Count: *count, ProfileDir: *profile}
+	var err error
+	if cfg.Parts, err = parseInts(*ms); err != nil {
+		return err
+	}
+	if cfg.Versions, err = parseInts(*ns); err != nil {
+		return err
+	}
+	if err := flagSetBenchtime(benchtime.String()); err != nil {
+		return err
+	}
+	results, err := run(cfg, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		return writeJSON(os.Stdout, results)
+	}
+	return writeResultsFile(*out, results)
+}
+
+func compareCmd(args []string) (bool, error) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 5, "regression threshold in percent")
+	alpha := fs.Float64("alpha", 0.05, "significance level")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		usage()
+	}
+	old, err := readResultsFile(fs.Arg(0))
+	if err != nil {
+		return false, err
+	}
+	cur, err := readResultsFile(fs.Arg(1))
+	if err != nil {
+		return false, err
+	}
+	cmp := compare(old, cur, *threshold, *alpha)
+	if err := writeComparison(os.Stdout, cmp); err != nil {
+		return false, err
+	}
+	for _, c := range cmp {
+		if c.Regression {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// flagSetBenchtime sets the minimum time of each testing.Benchmark run.
+func flagSetBenchtime(d string) error {
+	return flag.Set("test.benchtime", d)
+}
+
+func parseInts(s string) ([]int, error) {
+	var ints []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid count %q", f)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
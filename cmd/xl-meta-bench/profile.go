@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+)
+
+// profiler writes the profiles of one benchmark into dir, as
+// <name>.cpu.pprof, <name>.heap.pprof and <name>.allocs.pprof.
+type profiler struct {
+	dir, name string
+	cpu       *os.File
+}
+
+func (p *profiler) create(kind string) (*os.File, error) {
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(p.dir, p.name+"."+kind+".pprof"))
+}
+
+// startCPU starts profiling the CPU for all runs of the benchmark.
+func (p *profiler) startCPU() error {
+	f, err := p.create("cpu")
+	if err != nil {
+		return err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	p.cpu = f
+	return nil
+}
+
+// stop stops the CPU profile and writes the heap profile while buf decoded
+// with c is alive, then the allocs profile. Allocations are counted since
+// the start of the process: compare consecutive benchmarks with -base.
+func (p *profiler) stop(c codec, buf []byte) error {
+	pprof.StopCPUProfile()
+	if err := p.cpu.Close(); err != nil {
+		return err
+	}
+	if _, err := c.liveHeap(buf, func() error { return p.write("heap") }); err != nil {
+		return err
+	}
+	return p.write("allocs")
+}
+
+// abort stops the CPU profile of a benchmark that failed.
+func (p *profiler) abort() {
+	pprof.StopCPUProfile()
+	p.cpu.Close()
+}
+
+func (p *profiler) write(kind string) error {
+	f, err := p.create(kind)
+	if err != nil {
+		return err
+	}
+	err = pprof.Lookup(kind).WriteTo(f, 0)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is one run of one benchmark.
+type Result struct {
+	Codec       string  `json:"codec"`
+	Parts       int     `json:"parts"`
+	Versions    int     `json:"versions"`
+	Size        int     `json:"size"` // Encoded size in bytes.
+	Run         int     `json:"run"`
+	N           int     `json:"n"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	MBPerSec    float64 `json:"mb_per_sec"`
+	// LiveHeapBytes is the growth of the live heap while one decoded value
+	// is alive. Results of older runs may lack it.
+	LiveHeapBytes int64 `json:"live_heap_bytes,omitempty"`
+}
+
+// Name identifies the benchmark of r, like the package benchmarks do.
+func (r Result) Name() string {
+	return fmt.Sprintf("%s-%dx%d", r.Codec, r.Parts, r.Versions)
+}
+
+// Results are the results of a run with the environment they were measured in.
+type Results struct {
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	CPUs      int       `json:"cpus"`
+	Date      time.Time `json:"date"`
+	Results   []Result  `json:"results"`
+}
+
+var csvHeader = []string{"codec", "parts", "versions", "size", "run", "n", "ns_per_op", "bytes_per_op", "allocs_per_op", "mb_per_sec", "live_heap_bytes"}
+
+func writeJSON(w io.Writer, results *Results) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// writeCSV writes one line per result. The environment is not written.
+func writeCSV(w io.Writer, results *Results) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, r := range results.Results {
+		cw.Write([]string{
+			r.Codec,
+			strconv.Itoa(r.Parts),
+			strconv.Itoa(r.Versions),
+			strconv.Itoa(r.Size),
+			strconv.Itoa(r.Run),
+			strconv.Itoa(r.N),
+			strconv.FormatFloat(r.NsPerOp, 'f', -1, 64),
+			strconv.FormatInt(r.BytesPerOp, 10),
+			strconv.FormatInt(r.AllocsPerOp, 10),
+			strconv.FormatFloat(r.MBPerSec, 'f', -1, 64),
+			strconv.FormatInt(r.LiveHeapBytes, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func readCSV(r io.Reader) (*Results, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	// Files of older runs lack the live heap column.
+	header := csvHeader
+	if len(records) > 0 && len(records[0]) == len(csvHeader)-1 {
+		header = csvHeader[:len(csvHeader)-1]
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(header, ",") {
+		return nil, fmt.Errorf("missing CSV header %s", strings.Join(csvHeader, ","))
+	}
+	results := &Results{}
+	for i, rec := range records[1:] {
+		if len(rec) != len(header) {
+			return nil, fmt.Errorf("line %d: %d fields, want %d", i+2, len(rec), len(header))
+		}
+		var r Result
+		var errs [10]error
+		r.Codec = rec[0]
+		r.Parts, errs[0] = strconv.Atoi(rec[1])
+		r.Versions, errs[1] = strconv.Atoi(rec[2])
+		r.Size, errs[2] = strconv.Atoi(rec[3])
+		r.Run, errs[3] = strconv.Atoi(rec[4])
+		r.N, errs[4] = strconv.Atoi(rec[5])
+		r.NsPerOp, errs[5] = strconv.ParseFloat(rec[6], 64)
+		r.BytesPerOp, errs[6] = strconv.ParseInt(rec[7], 10, 64)
+		r.AllocsPerOp, errs[7] = strconv.ParseInt(rec[8], 10, 64)
+		r.MBPerSec, errs[8] = strconv.ParseFloat(rec[9], 64)
+		if len(rec) > 10 {
+			r.LiveHeapBytes, errs[9] = strconv.ParseInt(rec[10], 10, 64)
+		}
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+2, err)
+			}
+		}
+		results.Results = append(results.Results, r)
+	}
+	return results, nil
+}
+
+func isCSV(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".csv")
+}
+
+func writeResultsFile(path string, results *Results) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if isCSV(path) {
+		err = writeCSV(f, results)
+	} else {
+		err = writeJSON(f, results)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func readResultsFile(path string) (*Results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if isCSV(path) {
+		return readCSV(f)
+	}
+	var results Results
+	if err := json.NewDecoder(f).Decode(&results); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &results, nil
+}
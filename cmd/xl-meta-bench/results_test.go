@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResultsFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xl-meta-bench")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A tiny matrix keeps the run short.
+	if err := flagSetBenchtime("10ms"); err != nil {
+		t.Fatal(err)
+	}
+	results, err := run(runConfig{Codecs: codecNames(), Parts: []int{1, 3}, Versions: []int{2}, Count: 2}, ioutil.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(codecs) * 2 * 2; len(results.Results) != want {
+		t.Fatalf("want %d results, got %d", want, len(results.Results))
+	}
+	for _, r := range results.Results {
+		if r.N == 0 || r.NsPerOp <= 0 || r.Size == 0 || r.MBPerSec <= 0 || r.LiveHeapBytes <= 0 {
+			t.Fatalf("incomplete result %+v", r)
+		}
+	}
+	results.Date = results.Date.Truncate(time.Second)
+
+	for _, name := range []string{"results.json", "results.csv"} {
+		path := filepath.Join(dir, name)
+		if err := writeResultsFile(path, results); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readResultsFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := results
+		if isCSV(path) {
+			want = &Results{Results: results.Results}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: results differ after reading them back", name)
+		}
+	}
+
+	// Older CSV files lack the live heap.
+	old := strings.Join(csvHeader[:len(csvHeader)-1], ",") + "\nmsgpack,1,1,100,0,10,5.5,64,2,18.2\n"
+	got, err := readCSV(strings.NewReader(old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 || got.Results[0].AllocsPerOp != 2 || got.Results[0].LiveHeapBytes != 0 {
+		t.Errorf("unexpected results of an older CSV file %+v", got.Results)
+	}
+
+	if _, err := run(runConfig{Codecs: []string{"gob"}, Parts: []int{1}, Versions: []int{1}, Count: 1}, ioutil.Discard); err == nil {
+		t.Error("unknown codec accepted")
+	}
+}
+
+func TestRunProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xl-meta-bench")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := flagSetBenchtime("10ms"); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"msgpack-fast", "msgpack-pool"}
+	profiles := filepath.Join(dir, "profiles")
+	if _, err := run(runConfig{Codecs: names, Parts: []int{2}, Versions: []int{3}, Count: 1, ProfileDir: profiles}, ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		for _, kind := range []string{"cpu", "heap", "allocs"} {
+			path := filepath.Join(profiles, name+"-2x3."+kind+".pprof")
+			if fi, err := os.Stat(path); err != nil || fi.Size() == 0 {
+				t.Errorf("missing profile %s: %v", path, err)
+			}
+		}
+	}
+}
+
+func TestRunFailedBenchmark(t *testing.T) {
+	if err := flagSetBenchtime("10ms"); err != nil {
+		t.Fatal(err)
+	}
+	// The codec decodes once before the benchmark, then fails.
+	var calls int32
+	msgpack := codecs["msgpack-fast"]
+	codecs["failing"] = codec{
+		encode: msgpack.encode,
+		decode: func(buf []byte) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) > 1 {
+				return nil, errors.New("decode failed")
+			}
+			return msgpack.decode(buf)
+		},
+	}
+	defer delete(codecs, "failing")
+
+	_, err := run(runConfig{Codecs: []string{"failing"}, Parts: []int{1}, Versions: []int{1}, Count: 1}, ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "failing-1x1") || !strings.Contains(err.Error(), "decode failed") {
+		t.Fatalf("want the failed benchmark named, got %v", err)
+	}
+}
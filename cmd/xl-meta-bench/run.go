@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	xlmeta "github.com/harshavardhana/xl-meta-bench"
+)
+
+// codec encodes sample metadata and decodes it.
+type codec struct {
+	encode func(z *xlmeta.ObjectMetaV2) ([]byte, error)
+	// decode decodes buf once and returns the decoded value.
+	decode func(buf []byte) (interface{}, error)
+	// release, if set, releases decoded values once done.
+	release func(v interface{})
+}
+
+// codecs holds every registered codec under its name, plus the "-pool" and
+// "-last" variants named by xlmeta.VariantName for the pooled and partial
+// decoding of the codecs supporting them, like the package benchmarks.
+var codecs = registeredCodecs()
+
+func registeredCodecs() map[string]codec {
+	m := make(map[string]codec)
+	for _, c := range xlmeta.Codecs() {
+		c := c
+		m[c.Name()] = codec{
+			encode: c.Marshal,
+			decode: func(buf []byte) (interface{}, error) {
+				z := new(xlmeta.ObjectMetaV2)
+				return z, c.Unmarshal(buf, z)
+			},
+		}
+		if pc, ok := c.(xlmeta.PooledCodec); ok {
+			m[xlmeta.VariantName(c.Name(), "-pool")] = codec{
+				encode: c.Marshal,
+				decode: func(buf []byte) (interface{}, error) {
+					return pc.UnmarshalPooled(buf)
+				},
+				release: func(v interface{}) {
+					xlmeta.PutObjectMetaV2(v.(*xlmeta.ObjectMetaV2))
+				},
+			}
+		}
+		if pc, ok := c.(xlmeta.PartialCodec); ok {
+			m[xlmeta.VariantName(c.Name(), "-last")] = codec{
+				encode: c.Marshal,
+				decode: func(buf []byte) (interface{}, error) {
+					var z xlmeta.ObjectMetaV2
+					return pc.UnmarshalEntry(buf, -1, &z, nil)
+				},
+			}
+		}
+	}
+	return m
+}
+
+// decodeOnce decodes buf and releases the result.
+func (c codec) decodeOnce(buf []byte) error {
+	v, err := c.decode(buf)
+	if err == nil && c.release != nil {
+		c.release(v)
+	}
+	return err
+}
+
+// liveHeap decodes buf and returns the growth of the live heap while the
+// decoded value is alive, garbage collecting before reading the heap size.
+// The encoded buffer is not counted. If heap is not nil it is called while
+// the value is alive, to write a heap profile.
+func (c codec) liveHeap(buf []byte, heap func() error) (int64, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v, err := c.decode(buf)
+	if err != nil {
+		return 0, err
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	if heap != nil {
+		err = heap()
+	}
+	runtime.KeepAlive(v)
+	if c.release != nil {
+		c.release(v)
+	}
+	live := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+	if live < 0 {
+		live = 0
+	}
+	return live, err
+}
+
+// benchmark benchmarks decoding buf with c, in parallel on every CPU like the
+// package benchmarks. testing.Benchmark reports a failed benchmark with zero
+// iterations, returned as an error.
+func (c codec) benchmark(buf []byte) (testing.BenchmarkResult, error) {
+	var (
+		mu      sync.Mutex
+		failure error
+	)
+	r := testing.Benchmark(func(b *testing.B) {
+		b.SetBytes(int64(len(buf)))
+		b.ReportAllocs()
+		b.SetParallelism(runtime.NumCPU())
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := c.decodeOnce(buf); err != nil {
+					mu.Lock()
+					failure = err
+					mu.Unlock()
+					return
+				}
+			}
+		})
+		b.StopTimer()
+		if failure != nil {
+			b.FailNow()
+		}
+		live, err := c.liveHeap(buf, nil)
+		if err != nil {
+			failure = err
+			b.FailNow()
+		}
+		b.ReportMetric(float64(live), "live-B")
+	})
+	if r.N == 0 {
+		if failure == nil {
+			failure = errors.New("no iterations run")
+		}
+		return r, failure
+	}
+	return r, nil
+}
+
+func codecNames() []string {
+	var names []string
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runConfig is the benchmark matrix.
+type runConfig struct {
+	Codecs   []string
+	Parts    []int
+	Versions []int
+	Count    int
+	// ProfileDir, if set, receives CPU, heap and allocs profiles
+	// of every benchmark.
+	ProfileDir string
+}
+
+// run benchmarks every codec on metadata of every number of parts and versions,
+// Count times each. Progress is written to log.
+func run(cfg runConfig, log io.Writer) (*Results, error) {
+	for _, name := range cfg.Codecs {
+		if _, ok := codecs[name]; !ok {
+			return nil, fmt.Errorf("unknown codec %q", name)
+		}
+	}
+	results := &Results{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		Date:      time.Now().UTC(),
+	}
+	for _, parts := range cfg.Parts {
+		for _, versions := range cfg.Versions {
+			z := xlmeta.SampleWorkload(parts).Generate(versions)
+			for _, name := range cfg.Codecs {
+				c := codecs[name]
+				buf, err := c.encode(&z)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.decodeOnce(buf); err != nil {
+					return nil, fmt.Errorf("%s: %v", name, err)
+				}
+				bench := Result{Codec: name, Parts: parts, Versions: versions}.Name()
+				var prof *profiler
+				if cfg.ProfileDir != "" {
+					prof = &profiler{dir: cfg.ProfileDir, name: bench}
+					if err := prof.startCPU(); err != nil {
+						return nil, err
+					}
+				}
+				for i := 0; i < cfg.Count; i++ {
+					r, err := c.benchmark(buf)
+					if err != nil {
+						if prof != nil {
+							prof.abort()
+						}
+						return nil, fmt.Errorf("benchmark %s failed: %v", bench, err)
+					}
+					res := Result{
+						Codec:         name,
+						Parts:         parts,
+						Versions:      versions,
+						Size:          len(buf),
+						Run:           i,
+						N:             r.N,
+						NsPerOp:       float64(r.T.Nanoseconds()) / float64(r.N),
+						BytesPerOp:    r.AllocedBytesPerOp(),
+						AllocsPerOp:   r.AllocsPerOp(),
+						LiveHeapBytes: int64(r.Extra["live-B"]),
+					}
+					if r.T > 0 {
+						res.MBPerSec = float64(r.Bytes) * float64(r.N) / 1e6 / r.T.Seconds()
+					}
+					fmt.Fprintf(log, "%s\t%d\t%.0f ns/op\t%d live-B\n", res.Name(), r.N, res.NsPerOp, res.LiveHeapBytes)
+					results.Results = append(results.Results, res)
+				}
+				if prof != nil {
+					if err := prof.stop(c, buf); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return results, nil
+}
@@ -1,12 +1,15 @@
 module github.com/harshavardhana/xl-meta-bench
 
-go 1.13
+go 1.18
 
 require (
 	github.com/dustin/go-humanize v1.0.0
 	github.com/json-iterator/go v1.1.7
-	github.com/philhofer/fwd v1.0.0 // indirect
 	github.com/tinylib/msgp v1.1.1
-	github.com/vmihailenco/msgpack/v4 v4.3.5
-	gopkg.in/mgo.v2 v2.0.0-20190816093944-a6b53ec6cb22
+)
+
+require (
+	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+	github.com/modern-go/reflect2 v1.0.3-0.20250322232337-35a7c28c31ee // indirect
+	github.com/philhofer/fwd v1.0.0 // indirect
 )
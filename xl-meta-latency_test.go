@@ -0,0 +1,128 @@
+package xlmeta
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"testing"
+	"time"
+)
+
+// latencyBits is the log2 of the buckets per power of two of a
+// latencyHistogram, which bounds the error of its quantiles to about 3%.
+const latencyBits = 5
+
+// latencyHistogram counts durations in log-linear buckets so that recording
+// one neither allocates nor grows with the number of operations. Durations
+// have 63 bits, so the last bucket starts at 63<<57 ns.
+type latencyHistogram [(64 - latencyBits) << latencyBits]uint64
+
+func (h *latencyHistogram) record(d time.Duration) {
+	n := uint64(d)
+	if d < 0 {
+		n = 0
+	}
+	if n < 2<<latencyBits {
+		h[n]++
+		return
+	}
+	shift := bits.Len64(n) - latencyBits - 1
+	h[shift<<latencyBits+int(n>>shift)]++
+}
+
+func (h *latencyHistogram) merge(o *latencyHistogram) {
+	for i, n := range o {
+		h[i] += n
+	}
+}
+
+// quantile returns the lower bound of the bucket holding the q quantile by
+// the nearest-rank method, and false if h is empty.
+func (h *latencyHistogram) quantile(q float64) (time.Duration, bool) {
+	var total uint64
+	for _, n := range h {
+		total += n
+	}
+	if total == 0 {
+		return 0, false
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range h {
+		if seen += n; seen >= rank {
+			return latencyBucket(i), true
+		}
+	}
+	return latencyBucket(len(h) - 1), true
+}
+
+// latencyBucket returns the lower bound of bucket i.
+func latencyBucket(i int) time.Duration {
+	if i < 2<<latencyBits {
+		return time.Duration(i)
+	}
+	shift := i>>latencyBits - 1
+	return time.Duration(uint64(i&(1<<latencyBits-1)|1<<latencyBits) << shift)
+}
+
+// latencies collects the durations of operations of all goroutines.
+type latencies struct {
+	mu            sync.Mutex
+	reads, writes latencyHistogram
+}
+
+func (l *latencies) add(reads, writes *latencyHistogram) {
+	l.mu.Lock()
+	l.reads.merge(reads)
+	l.writes.merge(writes)
+	l.mu.Unlock()
+}
+
+// report reports the p50, p99 and p999 latencies of reads and writes.
+func (l *latencies) report(b *testing.B) {
+	for _, op := range []struct {
+		name string
+		h    *latencyHistogram
+	}{{"read", &l.reads}, {"write", &l.writes}} {
+		for _, p := range []struct {
+			name string
+			q    float64
+		}{{"p50", 0.5}, {"p99", 0.99}, {"p999", 0.999}} {
+			d, ok := op.h.quantile(p.q)
+			if !ok {
+				break
+			}
+			b.ReportMetric(float64(d.Nanoseconds()), op.name+"-"+p.name+"-ns")
+		}
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	if _, ok := h.quantile(0.5); ok {
+		t.Fatal("quantile of an empty histogram")
+	}
+	for d := time.Duration(1); d <= 1000; d++ {
+		h.record(d * time.Microsecond)
+	}
+	for _, q := range []float64{0.5, 0.99, 0.999} {
+		got, _ := h.quantile(q)
+		want := time.Duration(q*1000) * time.Microsecond
+		if got > want || float64(want-got) > 0.04*float64(want) {
+			t.Errorf("quantile(%v) = %v, want %v within 4%%", q, got, want)
+		}
+	}
+	for i := range h {
+		if d := latencyBucket(i); i < len(h)-1 && latencyBucket(i+1) <= d {
+			t.Fatalf("bucket %d starts at %v, not after bucket %d at %v", i+1, latencyBucket(i+1), i, d)
+		}
+	}
+	var max latencyHistogram
+	max.record(math.MaxInt64)
+	if max[len(max)-1] != 1 {
+		t.Error("longest duration not in the last bucket")
+	}
+}
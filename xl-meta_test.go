@@ -1,66 +1,126 @@
-package main
+package xlmeta
 
 import (
 	"fmt"
+	"math/rand"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dustin/go-humanize"
-	jsoniter "github.com/json-iterator/go"
 )
 
-func benchmarkParseUnmarshalN(b *testing.B, ObjectMetaBuf []byte, parser string, elems int) {
+// Decoding modes of the benchmarks, appended to the codec name.
+const (
+	decodeFull   = ""
+	decodePooled = "-pool"
+	decodeLast   = "-last"
+)
+
+// decodeModes returns the decoding modes codec c supports.
+func decodeModes(c Codec) []string {
+	modes := []string{decodeFull}
+	if _, ok := c.(PooledCodec); ok {
+		modes = append(modes, decodePooled)
+	}
+	if _, ok := c.(PartialCodec); ok {
+		modes = append(modes, decodeLast)
+	}
+	return modes
+}
+
+// decodeCheck validates decoded metadata in benchmarks.
+// Checks must be cheap compared to decoding.
+type decodeCheck interface {
+	metadata(z *ObjectMetaV2) error
+	entry(e *ObjectMetaV2JournalEntry) error
+}
+
+// sampleCheck checks metadata from getSampleObjectMetaV2(nparts, nversions)
+// by its first and last versions.
+type sampleCheck struct {
+	nparts, nversions int
+}
+
+func (s sampleCheck) metadata(z *ObjectMetaV2) error {
+	if len(z.ObjectJournals) != s.nversions {
+		return fmt.Errorf("decoded %d versions, want %d", len(z.ObjectJournals), s.nversions)
+	}
+	if err := s.entry(&z.ObjectJournals[0]); err != nil {
+		return err
+	}
+	return s.entry(&z.ObjectJournals[s.nversions-1])
+}
+
+func (s sampleCheck) entry(e *ObjectMetaV2JournalEntry) error {
+	if e.Type != Object || e.Object == nil {
+		return fmt.Errorf("decoded journal type %d, want an object", e.Type)
+	}
+	if e.Object.DataErasureM != 8 || len(e.Object.DataPartInfoNumbers) != s.nparts {
+		return fmt.Errorf("decoded %d parts with %d data shards, want %d with 8", len(e.Object.DataPartInfoNumbers), e.Object.DataErasureM, s.nparts)
+	}
+	return nil
+}
+
+// versionsCheck checks metadata of nversions versions of any type.
+type versionsCheck int
+
+func (n versionsCheck) metadata(z *ObjectMetaV2) error {
+	if len(z.ObjectJournals) != int(n) {
+		return fmt.Errorf("decoded %d versions, want %d", len(z.ObjectJournals), n)
+	}
+	return nil
+}
+
+func (versionsCheck) entry(e *ObjectMetaV2JournalEntry) error {
+	if e.Object == nil && e.DeleteMarker == nil {
+		return fmt.Errorf("decoded journal type %d without content", e.Type)
+	}
+	return nil
+}
+
+func benchmarkParseUnmarshalN(b *testing.B, c Codec, mode string, ObjectMetaBuf []byte, check decodeCheck, elems int) {
 	b.SetBytes(int64(elems))
 	b.ReportAllocs()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
 	b.ResetTimer()
 	b.SetParallelism(runtime.NumCPU())
 	if testing.Verbose() {
-		b.Log(parser, "Size:", humanize.IBytes(uint64(len(ObjectMetaBuf))))
+		b.Log(c.Name(), "Size:", humanize.IBytes(uint64(len(ObjectMetaBuf))))
 	}
 	b.RunParallel(func(pb *testing.PB) {
 		var journal *ObjectMetaV2JournalEntry
 		for pb.Next() {
-			var unMarshalObjectMeta ObjectMetaV2
-			switch parser {
-			case "jsoniter-fast":
-				var json = jsoniter.ConfigFastest
-				if err := json.Unmarshal(ObjectMetaBuf, &unMarshalObjectMeta); err != nil {
-					b.Fatal(err)
+			var err error
+			switch mode {
+			case decodePooled:
+				var z *ObjectMetaV2
+				if z, err = c.(PooledCodec).UnmarshalPooled(ObjectMetaBuf); err == nil {
+					err = check.metadata(z)
+					PutObjectMetaV2(z)
 				}
-				if unMarshalObjectMeta.ObjectJournals[0].Object.DataErasureM != 8 {
-					b.Fatal("unexpected")
+			case decodeLast:
+				var z ObjectMetaV2
+				if journal, err = c.(PartialCodec).UnmarshalEntry(ObjectMetaBuf, -1, &z, journal); err == nil {
+					err = check.entry(journal)
 				}
-			case "jsoniter-compat":
-				var json = jsoniter.ConfigCompatibleWithStandardLibrary
-				if err := json.Unmarshal(ObjectMetaBuf, &unMarshalObjectMeta); err != nil {
-					b.Fatal(err)
-				}
-				if unMarshalObjectMeta.ObjectJournals[0].Object.DataErasureM != 8 {
-					b.Fatal("unexpected")
-				}
-			case "msgpack-fast":
-				_, err := unMarshalObjectMeta.UnmarshalMsg(ObjectMetaBuf)
-				if err != nil {
-					b.Fatal(err)
-				}
-				if unMarshalObjectMeta.ObjectJournals[0].Object.DataErasureM != 8 {
-					b.Fatal("unexpected")
-				}
-				if len(unMarshalObjectMeta.ObjectJournals)*len(unMarshalObjectMeta.ObjectJournals[0].Object.DataPartInfoNumbers) != elems {
-					b.Fatalf("unexpected, len (%d * %d) != want (%d)", len(unMarshalObjectMeta.ObjectJournals), len(unMarshalObjectMeta.ObjectJournals[0].Object.DataPartInfoNumbers), elems)
-				}
-			case "msgpack-last":
-				var err error
-				journal, err = unMarshalObjectMeta.GetJournalEntryN(ObjectMetaBuf, -1, journal)
-				if err != nil {
-					b.Fatal(err)
-				}
-				if journal.Object.DataErasureM != 8 {
-					b.Fatal("unexpected")
+			default:
+				var z ObjectMetaV2
+				if err = c.Unmarshal(ObjectMetaBuf, &z); err == nil {
+					err = check.metadata(&z)
 				}
 			}
+			if err != nil {
+				b.Fatal(err)
+			}
 		}
 	})
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
 }
 
 var (
@@ -79,68 +139,296 @@ var (
 	}
 )
 
-func BenchmarkParseUnmarshalJsoniterFast(b *testing.B) {
-	var json = jsoniter.ConfigFastest
+// BenchmarkParseUnmarshal decodes metadata of m parts by n versions
+// with every registered codec in every mode it supports.
+func BenchmarkParseUnmarshal(b *testing.B) {
+	benchmarkParseUnmarshalSamples(b, Codecs(), decodeModes)
+}
+
+// benchmarkParseUnmarshalSamples decodes metadata of m parts by n versions
+// with codecs in the modes returned by modes.
+func benchmarkParseUnmarshalSamples(b *testing.B, codecs []Codec, modes func(c Codec) []string) {
 	for _, m := range ms {
 		for _, n := range ns {
-			ObjectMetaBuf, err := json.Marshal(getSampleObjectMetaV2(m, n))
-			if err != nil {
-				b.Fatal(err)
+			xlmeta := getSampleObjectMetaV2(m, n)
+			for _, c := range codecs {
+				ObjectMetaBuf, err := c.Marshal(&xlmeta)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, mode := range modes(c) {
+					test := fmt.Sprintf("%s-%dx%d", VariantName(c.Name(), mode), m, n)
+					b.Run(test, func(b *testing.B) {
+						benchmarkParseUnmarshalN(b, c, mode, ObjectMetaBuf, sampleCheck{m, n}, n*m)
+					})
+				}
 			}
-			test := fmt.Sprintf("%s-%dx%d", "jsoniter-fast", m, n)
-			b.Run(test, func(b *testing.B) {
-				benchmarkParseUnmarshalN(b, ObjectMetaBuf, "jsoniter-fast", n*m)
-			})
 		}
 	}
 }
 
+// benchmarkParseUnmarshalCodec is BenchmarkParseUnmarshal of the codec name in a single mode.
+func benchmarkParseUnmarshalCodec(b *testing.B, name, mode string) {
+	c := LookupCodec(name)
+	if c == nil {
+		b.Fatalf("codec %s is not registered", name)
+	}
+	benchmarkParseUnmarshalSamples(b, []Codec{c}, func(Codec) []string { return []string{mode} })
+}
+
+// The benchmarks below run subsets of BenchmarkParseUnmarshal under their
+// earlier names, to compare with results recorded before the codec registry.
+
+func BenchmarkParseUnmarshalJsoniterFast(b *testing.B) {
+	benchmarkParseUnmarshalCodec(b, "jsoniter-fast", decodeFull)
+}
+
 func BenchmarkParseUnmarshalJsoniterCompat(b *testing.B) {
-	var json = jsoniter.ConfigCompatibleWithStandardLibrary
-	for _, m := range ms {
-		for _, n := range ns {
-			ObjectMetaBuf, err := json.Marshal(getSampleObjectMetaV2(m, n))
+	benchmarkParseUnmarshalCodec(b, "jsoniter-compat", decodeFull)
+}
+
+func BenchmarkParseUnmarshalTinylibMsg(b *testing.B) {
+	benchmarkParseUnmarshalCodec(b, "msgpack-fast", decodeFull)
+}
+
+func BenchmarkParseUnmarshalLastTinylibMsg(b *testing.B) {
+	benchmarkParseUnmarshalCodec(b, "msgpack-fast", decodeLast)
+}
+
+// BenchmarkParseUnmarshalWorkload measures journals generated by
+// DefaultWorkload, mixing delete markers, part counts and metadata sizes.
+func BenchmarkParseUnmarshalWorkload(b *testing.B) {
+	for _, n := range ns {
+		xlmeta := DefaultWorkload.Generate(n)
+		for _, c := range Codecs() {
+			ObjectMetaBuf, err := c.Marshal(&xlmeta)
 			if err != nil {
 				b.Fatal(err)
 			}
-			test := fmt.Sprintf("%s-%dx%d", "jsoniter-compat", m, n)
-			b.Run(test, func(b *testing.B) {
-				benchmarkParseUnmarshalN(b, ObjectMetaBuf, "jsoniter-compat", n*m)
-			})
+			for _, mode := range decodeModes(c) {
+				test := fmt.Sprintf("%s-workload-%d", VariantName(c.Name(), mode), n)
+				b.Run(test, func(b *testing.B) {
+					benchmarkParseUnmarshalN(b, c, mode, ObjectMetaBuf, versionsCheck(n), n)
+				})
+			}
 		}
 	}
 }
 
-func BenchmarkParseUnmarshalTinylibMsg(b *testing.B) {
-	for _, m := range ms {
-		for _, n := range ns {
-			xlmeta := getSampleObjectMetaV2(m, n)
-			ObjectMetaBuf, err := xlmeta.MarshalMsg(nil)
-			if err != nil {
-				b.Fatal(err)
+var inlineSizes = []int{
+	0,
+	4 << 10,
+	InlineDataThreshold,
+}
+
+// BenchmarkParseUnmarshalInline measures metadata-only reads
+// of journals whose versions carry inline data.
+func BenchmarkParseUnmarshalInline(b *testing.B) {
+	for _, size := range inlineSizes {
+		for _, n := range ns[:3] {
+			xlmeta := getSampleInlineObjectMetaV2(size, n)
+			for _, c := range Codecs() {
+				ObjectMetaBuf, err := c.Marshal(&xlmeta)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, mode := range decodeModes(c) {
+					test := fmt.Sprintf("%s-inline-%s-%d", VariantName(c.Name(), mode), humanize.IBytes(uint64(size)), n)
+					b.Run(test, func(b *testing.B) {
+						benchmarkParseUnmarshalN(b, c, mode, ObjectMetaBuf, sampleCheck{1, n}, n)
+					})
+				}
 			}
+		}
+	}
+}
 
-			test := fmt.Sprintf("%s-%dx%d", "msgpack-fast", m, n)
-			b.Run(test, func(b *testing.B) {
-				benchmarkParseUnmarshalN(b, ObjectMetaBuf, "msgpack-fast", n*m)
-			})
+// replicationTargets are the numbers of targets with replication state on
+// every version in BenchmarkParseUnmarshalReplication.
+var replicationTargets = []int{0, 2}
+
+// BenchmarkParseUnmarshalReplication decodes DefaultWorkload journals with
+// and without replication state. Without state the journals are those of
+// BenchmarkParseUnmarshalWorkload, so the state only costs when present.
+func BenchmarkParseUnmarshalReplication(b *testing.B) {
+	for _, targets := range replicationTargets {
+		for _, n := range ns[:3] {
+			xlmeta := DefaultWorkload.Generate(n)
+			for i := range xlmeta.ObjectJournals {
+				state := xlmeta.ObjectJournals[i].replicationTargets()
+				for t := 0; state != nil && t < targets; t++ {
+					setReplicationStatus(state, fmt.Sprintf("arn:minio:replication::%d:bucket", t), ReplicationCompleted, time.Unix(1, 0))
+				}
+			}
+			for _, c := range Codecs() {
+				ObjectMetaBuf, err := c.Marshal(&xlmeta)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, mode := range decodeModes(c) {
+					test := fmt.Sprintf("%s-replication-%d-%d", VariantName(c.Name(), mode), targets, n)
+					b.Run(test, func(b *testing.B) {
+						benchmarkParseUnmarshalN(b, c, mode, ObjectMetaBuf, versionsCheck(n), n)
+					})
+				}
+			}
 		}
 	}
 }
 
-func BenchmarkParseUnmarshalLastTinylibMsg(b *testing.B) {
-	for _, m := range ms {
-		for _, n := range ns {
-			xlmeta := getSampleObjectMetaV2(m, n)
-			ObjectMetaBuf, err := xlmeta.MarshalMsg(nil)
+// mixedReads are the shares of reads, in percent, of the mixed benchmarks.
+var mixedReads = []int{100, 99, 90, 50}
+
+// mixedObject is metadata shared by the readers and writers of the mixed
+// benchmarks. Writers rewrite the buffer under mu and publish a new copy;
+// readers load the current copy without locking.
+type mixedObject struct {
+	c         Codec
+	nversions int
+	buf       atomic.Value // []byte
+
+	mu      sync.Mutex
+	entries []ObjectMetaV2JournalEntry
+	next    int
+}
+
+// write decodes the current buffer, appends a version, drops the oldest ones
+// beyond nversions and publishes the encoded result.
+func (o *mixedObject) write() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var z ObjectMetaV2
+	if err := o.c.Unmarshal(o.buf.Load().([]byte), &z); err != nil {
+		return err
+	}
+	z.ObjectJournals = append(z.ObjectJournals, o.entries[o.next%len(o.entries)])
+	o.next++
+	if extra := len(z.ObjectJournals) - o.nversions; extra > 0 {
+		z.ObjectJournals = z.ObjectJournals[extra:]
+	}
+	buf, err := o.c.Marshal(&z)
+	if err != nil {
+		return err
+	}
+	o.buf.Store(buf)
+	return nil
+}
+
+// read decodes the latest version of the current buffer into dst,
+// decoding only that entry when the codec supports it.
+func (o *mixedObject) read(dst *ObjectMetaV2JournalEntry) (*ObjectMetaV2JournalEntry, error) {
+	buf := o.buf.Load().([]byte)
+	var z ObjectMetaV2
+	if pc, ok := o.c.(PartialCodec); ok {
+		return pc.UnmarshalEntry(buf, -1, &z, dst)
+	}
+	if err := o.c.Unmarshal(buf, &z); err != nil {
+		return nil, err
+	}
+	versions := z.versionIndexes()
+	if len(versions) == 0 {
+		return nil, errVersionNotFound
+	}
+	return &z.ObjectJournals[versions[len(versions)-1]], nil
+}
+
+func benchmarkMixedN(b *testing.B, c Codec, nparts, nversions, readPct int) {
+	z := getSampleObjectMetaV2(nparts, nversions)
+	buf, err := c.Marshal(&z)
+	if err != nil {
+		b.Fatal(err)
+	}
+	// Appended versions differ from the initial ones.
+	w := SampleWorkload(nparts)
+	w.Seed++
+	o := &mixedObject{c: c, nversions: nversions, entries: w.Generate(64).ObjectJournals}
+	o.buf.Store(buf)
+	check := sampleCheck{nparts, nversions}
+
+	var lat latencies
+	var seed int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.SetParallelism(runtime.NumCPU())
+	b.RunParallel(func(pb *testing.PB) {
+		rng := rand.New(rand.NewSource(atomic.AddInt64(&seed, 1)))
+		// Kept on the stack: recording must not allocate in the timed loop.
+		var reads, writes latencyHistogram
+		var journal *ObjectMetaV2JournalEntry
+		for pb.Next() {
+			var err error
+			start := time.Now()
+			if rng.Intn(100) < readPct {
+				if journal, err = o.read(journal); err == nil {
+					err = check.entry(journal)
+				}
+				reads.record(time.Since(start))
+			} else {
+				err = o.write()
+				writes.record(time.Since(start))
+			}
 			if err != nil {
 				b.Fatal(err)
 			}
+		}
+		lat.add(&reads, &writes)
+	})
+	b.StopTimer()
+	lat.report(b)
+}
+
+// BenchmarkParseUnmarshalMixed runs readers of the latest version
+// concurrently with writers appending versions and rewriting the buffer,
+// reporting the tail latencies of both.
+func BenchmarkParseUnmarshalMixed(b *testing.B) {
+	for _, m := range ms[:2] {
+		for _, n := range ns[1:3] {
+			for _, c := range Codecs() {
+				for _, r := range mixedReads {
+					test := fmt.Sprintf("%s-mixed-r%d-%dx%d", c.Name(), r, m, n)
+					b.Run(test, func(b *testing.B) {
+						benchmarkMixedN(b, c, m, n, r)
+					})
+				}
+			}
+		}
+	}
+}
 
-			test := fmt.Sprintf("%s-%dx%d", "msgpack-last", m, n)
-			b.Run(test, func(b *testing.B) {
-				benchmarkParseUnmarshalN(b, ObjectMetaBuf, "msgpack-last", n*m)
-			})
+// BenchmarkVersionAt finds the version current at a time in the middle
+// of the journal, decoding all versions or only indexing them.
+func BenchmarkVersionAt(b *testing.B) {
+	for _, n := range ns {
+		xlmeta := DefaultWorkload.Generate(n)
+		ObjectMetaBuf, err := xlmeta.MarshalMsg(nil)
+		if err != nil {
+			b.Fatal(err)
 		}
+		at := time.Unix(xlmeta.ObjectJournals[n/2].ModTime(), 0)
+		b.Run(fmt.Sprintf("decoded-%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var z ObjectMetaV2
+				if _, err := z.UnmarshalMsg(ObjectMetaBuf); err != nil {
+					b.Fatal(err)
+				}
+				if z.VersionAt(at) < 0 {
+					b.Fatal("no version found")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("index-%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			var journal *ObjectMetaV2JournalEntry
+			for i := 0; i < b.N; i++ {
+				x, err := NewJournalIndex(ObjectMetaBuf)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if journal, err = x.Entry(x.VersionAt(at), journal); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
@@ -0,0 +1,153 @@
+package xlmeta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	errChecksumAlgoUnavailable = errors.New("checksum algorithm not registered")
+	errChecksumsMissing        = errors.New("part checksums missing or incomplete")
+)
+
+// BitrotError is returned when a part file does not match its stored checksum.
+type BitrotError struct {
+	PartNumber int
+	Algo       ChecksumAlgo
+	Want       []byte
+	Got        []byte
+}
+
+func (e *BitrotError) Error() string {
+	return fmt.Sprintf("bitrot detected in part %d (%s): want %x, got %x", e.PartNumber, e.Algo, e.Want, e.Got)
+}
+
+type checksumAlgoInfo struct {
+	name    string
+	newHash func() hash.Hash
+}
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+var (
+	checksumAlgosMu sync.RWMutex
+	checksumAlgos   = map[ChecksumAlgo]checksumAlgoInfo{
+		SHA256: {name: "sha256", newHash: sha256.New},
+		CRC32C: {name: "crc32c", newHash: func() hash.Hash { return crc32.New(castagnoliTable) }},
+	}
+)
+
+// RegisterChecksumAlgo makes algo available for computing and verifying part checksums.
+// HighwayHash256S is not registered by default; callers provide it with their key.
+// Registering an algorithm again replaces the previous implementation.
+func RegisterChecksumAlgo(algo ChecksumAlgo, name string, newHash func() hash.Hash) {
+	checksumAlgosMu.Lock()
+	defer checksumAlgosMu.Unlock()
+	checksumAlgos[algo] = checksumAlgoInfo{name: name, newHash: newHash}
+}
+
+// Available returns whether an implementation of a has been registered.
+func (a ChecksumAlgo) Available() bool {
+	checksumAlgosMu.RLock()
+	defer checksumAlgosMu.RUnlock()
+	_, ok := checksumAlgos[a]
+	return ok
+}
+
+// New returns a new hash for a.
+func (a ChecksumAlgo) New() (hash.Hash, error) {
+	checksumAlgosMu.RLock()
+	info, ok := checksumAlgos[a]
+	checksumAlgosMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", a, errChecksumAlgoUnavailable)
+	}
+	return info.newHash(), nil
+}
+
+func (a ChecksumAlgo) String() string {
+	checksumAlgosMu.RLock()
+	info, ok := checksumAlgos[a]
+	checksumAlgosMu.RUnlock()
+	if ok {
+		return info.name
+	}
+	if a == HighwayHash256S {
+		return "highwayhash256S"
+	}
+	return fmt.Sprintf("ChecksumAlgo(%d)", uint8(a))
+}
+
+// partFileName returns the name of the file holding part number n inside a data directory.
+func partFileName(n int) string {
+	return fmt.Sprintf("part.%d", n)
+}
+
+// checksumFile returns the checksum of the file at path using algo.
+func checksumFile(algo ChecksumAlgo, path string) ([]byte, error) {
+	h, err := algo.New()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// ChecksumParts computes the checksum of every part file in dir
+// with DataErasureChecksumAlgo and stores them in DataPartInfoChecksums.
+func (z *ObjectMetaV2Object) ChecksumParts(dir string) error {
+	if !z.hasLocalParts() {
+		return errDataTransitioned
+	}
+	sums := make([][]byte, len(z.DataPartInfoNumbers))
+	for i, n := range z.DataPartInfoNumbers {
+		sum, err := checksumFile(z.DataErasureChecksumAlgo, filepath.Join(dir, partFileName(n)))
+		if err != nil {
+			return err
+		}
+		sums[i] = sum
+	}
+	z.DataPartInfoChecksums = sums
+	return nil
+}
+
+// VerifyParts checks every part file in dir against DataPartInfoChecksums.
+// A *BitrotError is returned for the first part that does not match.
+func (z *ObjectMetaV2Object) VerifyParts(dir string) error {
+	if !z.hasLocalParts() {
+		return errDataTransitioned
+	}
+	if len(z.DataPartInfoChecksums) != len(z.DataPartInfoNumbers) {
+		return errChecksumsMissing
+	}
+	for i, n := range z.DataPartInfoNumbers {
+		sum, err := checksumFile(z.DataErasureChecksumAlgo, filepath.Join(dir, partFileName(n)))
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(sum, z.DataPartInfoChecksums[i]) {
+			return &BitrotError{
+				PartNumber: n,
+				Algo:       z.DataErasureChecksumAlgo,
+				Want:       z.DataPartInfoChecksums[i],
+				Got:        sum,
+			}
+		}
+	}
+	return nil
+}
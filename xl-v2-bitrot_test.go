@@ -0,0 +1,104 @@
+package xlmeta
+
+import (
+	"crypto/md5"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestParts(t *testing.T, obj *ObjectMetaV2Object) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "xl-meta-bitrot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, n := range obj.DataPartInfoNumbers {
+		data := make([]byte, 1024+i)
+		for j := range data {
+			data[j] = byte(i + j)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, partFileName(n)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestObjectMetaV2ObjectVerifyParts(t *testing.T) {
+	for _, algo := range []ChecksumAlgo{SHA256, CRC32C} {
+		t.Run(algo.String(), func(t *testing.T) {
+			obj := newObjectMetaV2Object(4)
+			obj.DataErasureChecksumAlgo = algo
+			dir := writeTestParts(t, obj)
+			defer os.RemoveAll(dir)
+
+			if err := obj.VerifyParts(dir); err != errChecksumsMissing {
+				t.Fatalf("want %v, got %v", errChecksumsMissing, err)
+			}
+			if err := obj.ChecksumParts(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := obj.VerifyParts(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(dir, partFileName(3))
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data[100] ^= 0xff
+			if err = ioutil.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			err = obj.VerifyParts(dir)
+			var bitrot *BitrotError
+			if !errors.As(err, &bitrot) || bitrot.PartNumber != 3 {
+				t.Fatalf("want bitrot in part 3, got %v", err)
+			}
+		})
+	}
+}
+
+// unregisterChecksumAlgo removes algo from the registry,
+// so that tests registering algorithms do not leak them.
+func unregisterChecksumAlgo(algo ChecksumAlgo) {
+	checksumAlgosMu.Lock()
+	defer checksumAlgosMu.Unlock()
+	delete(checksumAlgos, algo)
+}
+
+func TestChecksumAlgoRegistry(t *testing.T) {
+	obj := newObjectMetaV2Object(1)
+	dir := writeTestParts(t, obj)
+	defer os.RemoveAll(dir)
+
+	if HighwayHash256S.Available() {
+		t.Fatal("HighwayHash256S must not be registered by default")
+	}
+	if err := obj.ChecksumParts(dir); !errors.Is(err, errChecksumAlgoUnavailable) {
+		t.Fatalf("want %v, got %v", errChecksumAlgoUnavailable, err)
+	}
+
+	const custom = ChecksumAlgo(200)
+	if custom.Available() {
+		t.Fatalf("%s registered by an earlier test", custom)
+	}
+	RegisterChecksumAlgo(custom, "md5", func() hash.Hash { return md5.New() })
+	t.Cleanup(func() { unregisterChecksumAlgo(custom) })
+	obj.DataErasureChecksumAlgo = custom
+	if err := obj.ChecksumParts(dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(obj.DataPartInfoChecksums[0]) != md5.Size {
+		t.Fatalf("unexpected checksum length %d", len(obj.DataPartInfoChecksums[0]))
+	}
+	if err := obj.VerifyParts(dir); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,132 @@
+package xlmeta
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Codec encodes and decodes ObjectMetaV2.
+type Codec interface {
+	// Name identifies the codec in the registry and in benchmark names.
+	Name() string
+	Marshal(z *ObjectMetaV2) ([]byte, error)
+	// Unmarshal decodes buf into z, which should be empty.
+	Unmarshal(buf []byte, z *ObjectMetaV2) error
+}
+
+// PartialCodec is implemented by codecs that decode a single version
+// without decoding the rest of the journal.
+type PartialCodec interface {
+	Codec
+	// UnmarshalEntry decodes version n of buf, oldest first and -1 being
+	// the latest one, into dst if it is not nil, and fills the global
+	// fields of z. Multipart uploads are not versions.
+	UnmarshalEntry(buf []byte, n int, z *ObjectMetaV2, dst *ObjectMetaV2JournalEntry) (*ObjectMetaV2JournalEntry, error)
+}
+
+// PooledCodec is implemented by codecs that decode into pooled values,
+// reusing the memory released by PutObjectMetaV2.
+type PooledCodec interface {
+	Codec
+	// UnmarshalPooled decodes buf into a value from GetObjectMetaV2.
+	// Release it with PutObjectMetaV2 once done.
+	UnmarshalPooled(buf []byte) (*ObjectMetaV2, error)
+}
+
+// VariantName returns the name of a decoding variant of the codec name, such as
+// "-pool" for PooledCodec and "-last" for PartialCodec decoding. The variant
+// replaces a "-fast" suffix, as in msgpack-pool and msgpack-last.
+// An empty variant returns name.
+func VariantName(name, variant string) string {
+	if variant == "" {
+		return name
+	}
+	return strings.TrimSuffix(name, "-fast") + variant
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = make(map[string]Codec)
+)
+
+// RegisterCodec makes c available by its name.
+// It panics if a codec of the same name is already registered.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	if _, dup := codecs[c.Name()]; dup {
+		panic("xlmeta: RegisterCodec called twice for codec " + c.Name())
+	}
+	codecs[c.Name()] = c
+}
+
+// LookupCodec returns the codec registered as name, or nil.
+func LookupCodec(name string) Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	return codecs[name]
+}
+
+// Codecs returns the registered codecs sorted by name.
+func Codecs() []Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	list := make([]Codec, 0, len(codecs))
+	for _, c := range codecs {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+func init() {
+	RegisterCodec(msgpCodec{})
+	RegisterCodec(jsonCodec{name: "jsoniter-fast", api: jsoniter.ConfigFastest})
+	RegisterCodec(jsonCodec{name: "jsoniter-compat", api: jsoniter.ConfigCompatibleWithStandardLibrary})
+}
+
+// msgpCodec is the msgpack encoding of xl.meta.
+type msgpCodec struct{}
+
+func (msgpCodec) Name() string { return "msgpack-fast" }
+
+func (msgpCodec) Marshal(z *ObjectMetaV2) ([]byte, error) {
+	return z.MarshalMsg(nil)
+}
+
+func (msgpCodec) Unmarshal(buf []byte, z *ObjectMetaV2) error {
+	_, err := z.UnmarshalMsg(buf)
+	return err
+}
+
+func (msgpCodec) UnmarshalEntry(buf []byte, n int, z *ObjectMetaV2, dst *ObjectMetaV2JournalEntry) (*ObjectMetaV2JournalEntry, error) {
+	return z.GetJournalEntryN(buf, n, dst)
+}
+
+func (msgpCodec) UnmarshalPooled(buf []byte) (*ObjectMetaV2, error) {
+	z := GetObjectMetaV2()
+	if _, err := z.UnmarshalMsgReuse(buf); err != nil {
+		PutObjectMetaV2(z)
+		return nil, err
+	}
+	return z, nil
+}
+
+// jsonCodec is the JSON encoding of xl.meta with a jsoniter configuration.
+type jsonCodec struct {
+	name string
+	api  jsoniter.API
+}
+
+func (c jsonCodec) Name() string { return c.name }
+
+func (c jsonCodec) Marshal(z *ObjectMetaV2) ([]byte, error) {
+	return c.api.Marshal(z)
+}
+
+func (c jsonCodec) Unmarshal(buf []byte, z *ObjectMetaV2) error {
+	return c.api.Unmarshal(buf, z)
+}
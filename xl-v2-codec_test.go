@@ -0,0 +1,134 @@
+package xlmeta
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// codecSamples returns the metadata every codec must round-trip.
+func codecSamples() map[string]ObjectMetaV2 {
+	samples := goldenCases()
+	rng := rand.New(rand.NewSource(1))
+	for _, name := range []string{"random-1", "random-2", "random-3"} {
+		samples[name] = randomObjectMetaV2(rng, 20)
+	}
+	return samples
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	samples := codecSamples()
+	names := make([]string, 0, len(samples))
+	for name := range samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, c := range Codecs() {
+		for _, name := range names {
+			want := samples[name]
+			buf, err := c.Marshal(&want)
+			if err != nil {
+				t.Fatalf("%s: %s: %v", c.Name(), name, err)
+			}
+			var got ObjectMetaV2
+			if err := c.Unmarshal(buf, &got); err != nil {
+				t.Fatalf("%s: %s: %v", c.Name(), name, err)
+			}
+			if !equalObjectMetaV2(&got, &want) {
+				t.Errorf("%s: %s: metadata differs after a round trip", c.Name(), name)
+			}
+
+			if pc, ok := c.(PooledCodec); ok {
+				// Decode twice to reuse the memory of the first decoding.
+				for i := 0; i < 2; i++ {
+					z, err := pc.UnmarshalPooled(buf)
+					if err != nil {
+						t.Fatalf("%s: %s: pooled: %v", c.Name(), name, err)
+					}
+					if !equalObjectMetaV2(z, &want) {
+						t.Errorf("%s: %s: pooled metadata differs", c.Name(), name)
+					}
+					PutObjectMetaV2(z)
+				}
+			}
+
+			if pc, ok := c.(PartialCodec); ok {
+				checkPartialCodec(t, pc, name, buf, &want)
+			}
+		}
+	}
+}
+
+// checkPartialCodec checks every version of buf decoded alone against want.
+func checkPartialCodec(t *testing.T, c PartialCodec, name string, buf []byte, want *ObjectMetaV2) {
+	t.Helper()
+	versions := want.versionIndexes()
+	n := len(versions)
+	if n == 0 {
+		var z ObjectMetaV2
+		if _, err := c.UnmarshalEntry(buf, -1, &z, nil); err == nil {
+			t.Errorf("%s: %s: latest version of a journal without versions decoded", c.Name(), name)
+		}
+		return
+	}
+	for i := -1; i < n; i++ {
+		var z ObjectMetaV2
+		dst, err := c.UnmarshalEntry(buf, i, &z, nil)
+		if err != nil {
+			t.Fatalf("%s: %s: entry %d: %v", c.Name(), name, i, err)
+		}
+		if z.Version != want.Version || z.Format != want.Format {
+			t.Errorf("%s: %s: entry %d: decoded version %d format %d, want %d and %d", c.Name(), name, i, z.Version, z.Format, want.Version, want.Format)
+		}
+		j := i
+		if j < 0 {
+			j = n - 1
+		}
+		got := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{*dst}}
+		expect := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{want.ObjectJournals[versions[j]]}}
+		if !equalObjectMetaV2(&got, &expect) {
+			t.Errorf("%s: %s: version %d differs from the full decoding", c.Name(), name, i)
+		}
+	}
+	var z ObjectMetaV2
+	if _, err := c.UnmarshalEntry(buf, n, &z, nil); err == nil {
+		t.Errorf("%s: %s: version %d past the end decoded", c.Name(), name, n)
+	}
+}
+
+func TestCodecRegistry(t *testing.T) {
+	for _, name := range []string{"msgpack-fast", "jsoniter-fast", "jsoniter-compat"} {
+		c := LookupCodec(name)
+		if c == nil || c.Name() != name {
+			t.Errorf("codec %s not registered", name)
+		}
+	}
+	if LookupCodec("gob") != nil {
+		t.Error("unknown codec found")
+	}
+	list := Codecs()
+	if !sort.SliceIsSorted(list, func(i, j int) bool { return list[i].Name() < list[j].Name() }) {
+		t.Error("codecs not sorted by name")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a codec twice did not panic")
+		}
+	}()
+	RegisterCodec(msgpCodec{})
+}
+
+func TestVariantName(t *testing.T) {
+	for _, tc := range []struct{ name, variant, want string }{
+		{"msgpack-fast", "", "msgpack-fast"},
+		{"msgpack-fast", "-pool", "msgpack-pool"},
+		{"msgpack-fast", "-last", "msgpack-last"},
+		{"jsoniter-compat", "-last", "jsoniter-compat-last"},
+	} {
+		if got := VariantName(tc.name, tc.variant); got != tc.want {
+			t.Errorf("VariantName(%q, %q) = %q, want %q", tc.name, tc.variant, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package xlmeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// maxMsgDepth is the deepest nesting of msgpack maps and arrays accepted.
+// xl.meta needs 7 levels.
+const maxMsgDepth = 32
+
+var (
+	errMsgLength = errors.New("msgp: declared length exceeds remaining bytes")
+	errMsgDepth  = errors.New("msgp: maximum nesting depth exceeded")
+)
+
+// msgp object kinds, by how their size is found.
+const (
+	msgInvalid = iota
+	msgFixed   // size and children given by the lead byte
+	msgLen8    // 8 bit length at byte 1
+	msgLen16   // 16 bit length at byte 1
+	msgLen32   // 32 bit length at byte 1
+	msgArray16
+	msgArray32
+	msgMap16
+	msgMap32
+)
+
+// msgSpec describes the msgpack objects starting with a lead byte.
+// For fixed objects size is the object size; otherwise it is the header size,
+// to which the length is added for strings, binaries and extensions.
+type msgSpec struct {
+	kind     uint8
+	size     uint8
+	children uint8
+}
+
+var msgSpecs = func() (specs [256]msgSpec) {
+	for i := 0; i < 256; i++ {
+		lead := byte(i)
+		switch {
+		case lead <= 0x7f || lead >= 0xe0: // fixint
+			specs[i] = msgSpec{kind: msgFixed, size: 1}
+		case lead <= 0x8f: // fixmap
+			specs[i] = msgSpec{kind: msgFixed, size: 1, children: 2 * (lead & 0x0f)}
+		case lead <= 0x9f: // fixarray
+			specs[i] = msgSpec{kind: msgFixed, size: 1, children: lead & 0x0f}
+		case lead <= 0xbf: // fixstr
+			specs[i] = msgSpec{kind: msgFixed, size: 1 + lead&0x1f}
+		}
+	}
+	for lead, size := range map[byte]uint8{
+		0xc0: 1, 0xc2: 1, 0xc3: 1, // nil, false, true
+		0xcc: 2, 0xd0: 2, // uint8, int8
+		0xcd: 3, 0xd1: 3, 0xd4: 3, // uint16, int16, fixext1
+		0xd5: 4,                   // fixext2
+		0xca: 5, 0xce: 5, 0xd2: 5, // float32, uint32, int32
+		0xd6: 6,                   // fixext4
+		0xcb: 9, 0xcf: 9, 0xd3: 9, // float64, uint64, int64
+		0xd7: 10, // fixext8
+		0xd8: 18, // fixext16
+	} {
+		specs[lead] = msgSpec{kind: msgFixed, size: size}
+	}
+	specs[0xc4] = msgSpec{kind: msgLen8, size: 2}    // bin8
+	specs[0xd9] = msgSpec{kind: msgLen8, size: 2}    // str8
+	specs[0xc7] = msgSpec{kind: msgLen8, size: 3}    // ext8
+	specs[0xc5] = msgSpec{kind: msgLen16, size: 3}   // bin16
+	specs[0xda] = msgSpec{kind: msgLen16, size: 3}   // str16
+	specs[0xc8] = msgSpec{kind: msgLen16, size: 4}   // ext16
+	specs[0xc6] = msgSpec{kind: msgLen32, size: 5}   // bin32
+	specs[0xdb] = msgSpec{kind: msgLen32, size: 5}   // str32
+	specs[0xc9] = msgSpec{kind: msgLen32, size: 6}   // ext32
+	specs[0xdc] = msgSpec{kind: msgArray16, size: 3} // array16
+	specs[0xdd] = msgSpec{kind: msgArray32, size: 5} // array32
+	specs[0xde] = msgSpec{kind: msgMap16, size: 3}   // map16
+	specs[0xdf] = msgSpec{kind: msgMap32, size: 5}   // map32
+	return specs
+}()
+
+// msgScalarSizes holds the size of the msgpack objects of a fixed size
+// that contain no other objects, by lead byte, and 0 for other objects.
+var msgScalarSizes = func() (sizes [256]uint8) {
+	for lead, spec := range msgSpecs {
+		if spec.kind == msgFixed && spec.children == 0 {
+			sizes[lead] = spec.size
+		}
+	}
+	return sizes
+}()
+
+// msgHeaderSize returns the size of the header of the msgpack object starting with lead.
+func msgHeaderSize(lead byte) int {
+	if spec := msgSpecs[lead]; spec.kind > msgFixed {
+		return int(spec.size)
+	}
+	return 1
+}
+
+// msgObjectSize returns the size of the msgpack object with header hdr,
+// excluding the objects it contains, and the number of objects it contains.
+// hdr must hold msgHeaderSize(hdr[0]) bytes.
+func msgObjectSize(hdr []byte) (size, children uint64, err error) {
+	spec := msgSpecs[hdr[0]]
+	size = uint64(spec.size)
+	switch spec.kind {
+	case msgFixed:
+		children = uint64(spec.children)
+	case msgLen8:
+		size += uint64(hdr[1])
+	case msgLen16:
+		size += uint64(binary.BigEndian.Uint16(hdr[1:]))
+	case msgLen32:
+		size += uint64(binary.BigEndian.Uint32(hdr[1:]))
+	case msgArray16:
+		children = uint64(binary.BigEndian.Uint16(hdr[1:]))
+	case msgArray32:
+		children = uint64(binary.BigEndian.Uint32(hdr[1:]))
+	case msgMap16:
+		children = 2 * uint64(binary.BigEndian.Uint16(hdr[1:]))
+	case msgMap32:
+		children = 2 * uint64(binary.BigEndian.Uint32(hdr[1:]))
+	default:
+		return 0, 0, msgp.InvalidPrefixError(hdr[0])
+	}
+	return size, children, nil
+}
+
+// checkMsgLengths checks the msgpack object at the start of b before it is decoded
+// and returns the bytes after it. Every object needs at least one byte, so arrays
+// and maps that declare more elements than there are bytes left are rejected
+// before a decoder allocates for them. Nesting is limited to maxMsgDepth.
+// The limits of opts are applied if it is not nil, taking the object as an ObjectMetaV2.
+func checkMsgLengths(b []byte, opts *DecodeOptions) ([]byte, error) {
+	return checkMsgNode(b, opts, msgMeta)
+}
+
+// checkEntryLengths is checkMsgLengths for an ObjectMetaV2JournalEntry.
+func checkEntryLengths(b []byte, opts *DecodeOptions) ([]byte, error) {
+	return checkMsgNode(b, opts, msgEntry)
+}
+
+// checkMsgNode is checkMsgLengths for an object of type root.
+func checkMsgNode(b []byte, opts *DecodeOptions, root msgNode) ([]byte, error) {
+	var pending [maxMsgDepth + 1]uint64
+	var limits msgLimits
+	limited := opts != nil && *opts != DecodeOptions{}
+	if limited {
+		limits.opts = opts
+		limits.root = root
+	}
+	pending[0] = 1
+	i, prev := 0, 0
+	for depth := 0; depth >= 0; {
+		if pending[depth] == 0 {
+			if limited {
+				if err := limits.pop(depth, i); err != nil {
+					return b[i:], err
+				}
+			}
+			depth--
+			continue
+		}
+		// Most objects are scalars of a fixed size.
+		n := pending[depth]
+		for n > 0 && i < len(b) {
+			size := msgScalarSizes[b[i]]
+			if size == 0 {
+				break
+			}
+			prev = i
+			i += int(size)
+			n--
+		}
+		pending[depth] = n
+		if i > len(b) || (n > 0 && i == len(b)) {
+			return b[len(b):], msgp.ErrShortBytes
+		}
+		if n == 0 {
+			continue
+		}
+		pending[depth]--
+		if len(b)-i < msgHeaderSize(b[i]) {
+			return b[i:], msgp.ErrShortBytes
+		}
+		size, children, err := msgObjectSize(b[i:])
+		if err != nil {
+			return b[i:], err
+		}
+		if size > uint64(len(b)-i) {
+			return b[i:], msgp.ErrShortBytes
+		}
+		start := i
+		i += int(size)
+		if children > uint64(len(b)-i) {
+			return b[i:], errMsgLength
+		}
+		if limited {
+			// In maps, values are preceded by their key and have an odd number
+			// of objects left to check, including themselves.
+			var key []byte
+			if limits.isMap[depth] && n%2 == 1 {
+				key = b[prev:start]
+			}
+			if err := limits.object(b[start:i], key, depth, start, children); err != nil {
+				return b[start:], err
+			}
+		}
+		prev = start
+		if children == 0 {
+			continue
+		}
+		if depth == maxMsgDepth {
+			return b[i:], errMsgDepth
+		}
+		depth++
+		pending[depth] = children
+	}
+	if limited {
+		if err := limits.end(i); err != nil {
+			return b[i:], err
+		}
+	}
+	return b[i:], nil
+}
+
+// readMsg appends the next msgpack object of dc to dst without decoding it.
+// Memory grows with the bytes actually read, not with the declared lengths.
+// Objects larger than maxBytes are rejected unless maxBytes is 0.
+func readMsg(dc *msgp.Reader, dst []byte, maxBytes int64) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	var pending [maxMsgDepth + 1]uint64
+	pending[0] = 1
+	for depth := 0; depth >= 0; {
+		if pending[depth] == 0 {
+			depth--
+			continue
+		}
+		pending[depth]--
+		lead, err := dc.R.Peek(1)
+		if err != nil {
+			return buf.Bytes(), unexpectedEOF(err, buf.Len() > len(dst))
+		}
+		hdr, err := dc.R.Peek(msgHeaderSize(lead[0]))
+		if err != nil {
+			return buf.Bytes(), unexpectedEOF(err, true)
+		}
+		size, children, err := msgObjectSize(hdr)
+		if err != nil {
+			return buf.Bytes(), err
+		}
+		if err := exceeds("MaxAlloc", maxBytes, int64(buf.Len()-len(dst))+int64(size)); err != nil {
+			return buf.Bytes(), err
+		}
+		if _, err := io.CopyN(buf, dc.R, int64(size)); err != nil {
+			return buf.Bytes(), unexpectedEOF(err, true)
+		}
+		if children == 0 {
+			continue
+		}
+		if depth == maxMsgDepth {
+			return buf.Bytes(), errMsgDepth
+		}
+		depth++
+		pending[depth] = children
+	}
+	return buf.Bytes(), nil
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF for io.EOF inside an object.
+func unexpectedEOF(err error, inside bool) error {
+	if err == io.EOF && inside {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// DecodeMsg implements msgp.Decodable
+// The object is read in full and checked before it is decoded.
+// No limits are applied; see DecodeMsgWithOptions.
+func (z *ObjectMetaV2) DecodeMsg(dc *msgp.Reader) (err error) {
+	return z.DecodeMsgWithOptions(dc, DecodeOptions{})
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *ObjectMetaV2) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 3
+	// write "v"
+	err = en.Append(0x83, 0xa1, 0x76)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.Version)
+	if err != nil {
+		err = msgp.WrapError(err, "Version")
+		return
+	}
+	// write "fmt"
+	err = en.Append(0xa3, 0x66, 0x6d, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint8(uint8(z.Format))
+	if err != nil {
+		err = msgp.WrapError(err, "Format")
+		return
+	}
+	// write "ojs"
+	err = en.Append(0xa3, 0x6f, 0x6a, 0x73)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.ObjectJournals)))
+	if err != nil {
+		err = msgp.WrapError(err, "ObjectJournals")
+		return
+	}
+	for za0001 := range z.ObjectJournals {
+		err = z.ObjectJournals[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "ObjectJournals", za0001)
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 3
+	// string "v"
+	o = append(o, 0x83, 0xa1, 0x76)
+	o = msgp.AppendInt64(o, z.Version)
+	// string "fmt"
+	o = append(o, 0xa3, 0x66, 0x6d, 0x74)
+	o = msgp.AppendUint8(o, uint8(z.Format))
+	// string "ojs"
+	o = append(o, 0xa3, 0x6f, 0x6a, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.ObjectJournals)))
+	for za0001 := range z.ObjectJournals {
+		o, err = z.ObjectJournals[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "ObjectJournals", za0001)
+			return
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+// Declared lengths are checked against the size of bts before decoding.
+// No limits are applied; see UnmarshalMsgWithOptions.
+func (z *ObjectMetaV2) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	return z.UnmarshalMsgWithOptions(bts, DecodeOptions{})
+}
+
+// unmarshalMsg decodes bts, which must have been checked with checkMsgLengths.
+func (z *ObjectMetaV2) unmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "v":
+			z.Version, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Version")
+				return
+			}
+		case "fmt":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Format")
+					return
+				}
+				z.Format = Format(zb0002)
+			}
+		case "ojs":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ObjectJournals")
+				return
+			}
+			if cap(z.ObjectJournals) >= int(zb0003) {
+				z.ObjectJournals = (z.ObjectJournals)[:zb0003]
+			} else {
+				z.ObjectJournals = make([]ObjectMetaV2JournalEntry, zb0003)
+			}
+			for za0001 := range z.ObjectJournals {
+				bts, err = z.ObjectJournals[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "ObjectJournals", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2) Msgsize() (s int) {
+	s = 1 + 2 + msgp.Int64Size + 4 + msgp.Uint8Size + 4 + msgp.ArrayHeaderSize
+	for za0001 := range z.ObjectJournals {
+		s += z.ObjectJournals[za0001].Msgsize()
+	}
+	return
+}
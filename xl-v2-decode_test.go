@@ -0,0 +1,239 @@
+package xlmeta
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// fuzzSeeds returns encoded sample metadata to seed the fuzz targets with.
+func fuzzSeeds(t testing.TB) [][]byte {
+	samples := []ObjectMetaV2{
+		getSampleObjectMetaV2(1, 1),
+		getSampleObjectMetaV2(3, 4),
+		getSampleInlineObjectMetaV2(100, 2),
+	}
+	z := getSampleObjectMetaV2(2, 2)
+	now := time.Unix(z.ObjectJournals[0].ModTime(), 0)
+	z.AddDeleteMarker(1, now)
+	if err := z.NewMultipartUpload("upload", *newObjectMetaV2Object(0), now); err != nil {
+		t.Fatal(err)
+	}
+	samples = append(samples, z)
+
+	var seeds [][]byte
+	for _, z := range samples {
+		buf, err := z.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seeds = append(seeds, buf)
+	}
+	return seeds
+}
+
+// hostileMsg returns metadata declaring a journal of n entries with nothing after it.
+func hostileMsg() []byte {
+	buf := msgp.AppendMapHeader(nil, 1)
+	buf = msgp.AppendString(buf, "ojs")
+	return msgp.AppendArrayHeader(buf, 1<<32-1)
+}
+
+func TestObjectMetaV2DecodeHostileLengths(t *testing.T) {
+	nested := msgp.AppendMapHeader(nil, 1)
+	nested = msgp.AppendString(nested, "x")
+	for i := 0; i < maxMsgDepth+1; i++ {
+		nested = msgp.AppendArrayHeader(nested, 1)
+	}
+	nested = msgp.AppendNil(nested)
+
+	deltas := msgp.AppendArrayHeader(nil, 1<<32-1)
+
+	object := msgp.AppendMapHeader(nil, 1)
+	object = msgp.AppendString(object, "ojs")
+	object = msgp.AppendArrayHeader(object, 1)
+	object = msgp.AppendMapHeader(object, 1)
+	object = msgp.AppendString(object, "object")
+	object = msgp.AppendMapHeader(object, 1)
+	object = msgp.AppendString(object, "dist")
+	object = msgp.AppendArrayHeader(object, 1<<32-1)
+
+	testCases := []struct {
+		name string
+		buf  []byte
+		want error
+	}{
+		{"journal", hostileMsg(), errMsgLength},
+		{"object distribution", object, errMsgLength},
+		{"nesting", nested, errMsgDepth},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var z ObjectMetaV2
+			if _, err := z.UnmarshalMsg(tc.buf); msgp.Cause(err) != tc.want {
+				t.Errorf("UnmarshalMsg: want %v, got %v", tc.want, err)
+			}
+			// Streams cannot be checked ahead, they end before the declared length.
+			want := tc.want
+			if want == errMsgLength {
+				want = io.ErrUnexpectedEOF
+			}
+			if err := z.DecodeMsg(msgp.NewReader(bytes.NewReader(tc.buf))); msgp.Cause(err) != want {
+				t.Errorf("DecodeMsg: want %v, got %v", want, err)
+			}
+			if _, err := GetObjectMetaV2().UnmarshalMsgReuse(tc.buf); msgp.Cause(err) != tc.want {
+				t.Errorf("UnmarshalMsgReuse: want %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	var z ObjectMetaV2
+	if _, err := z.GetJournalEntryN(object, 0, nil); msgp.Cause(err) != errMsgLength {
+		t.Errorf("GetJournalEntryN: want %v, got %v", errMsgLength, err)
+	}
+	var d DeltaEncodedInt
+	if _, err := d.UnmarshalMsg(deltas); msgp.Cause(err) != errMsgLength {
+		t.Errorf("DeltaEncodedInt.UnmarshalMsg: want %v, got %v", errMsgLength, err)
+	}
+	if err := d.DecodeMsg(msgp.NewReader(bytes.NewReader(deltas))); err == nil {
+		t.Error("DeltaEncodedInt.DecodeMsg: want error")
+	}
+}
+
+func TestCheckMsgLengths(t *testing.T) {
+	for _, buf := range fuzzSeeds(t) {
+		rest, err := checkMsgLengths(append(buf, 0xc0), nil)
+		if err != nil || !bytes.Equal(rest, []byte{0xc0}) {
+			t.Fatalf("valid metadata rejected: %v", err)
+		}
+		skipped, _ := msgp.Skip(append(buf, 0xc0))
+		if !bytes.Equal(rest, skipped) {
+			t.Fatal("checkMsgLengths and msgp.Skip disagree")
+		}
+		for i := range buf {
+			if _, err := checkMsgLengths(buf[:i], nil); err == nil {
+				t.Fatalf("truncated metadata of %d bytes accepted", i)
+			}
+		}
+	}
+}
+
+func FuzzObjectMetaV2UnmarshalMsg(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+	f.Add(hostileMsg())
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		var z ObjectMetaV2
+		if _, err := z.UnmarshalMsg(buf); err != nil {
+			return
+		}
+		encoded, err := z.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again ObjectMetaV2
+		if _, err = again.UnmarshalMsg(encoded); err != nil {
+			t.Fatalf("re-encoded metadata does not decode: %v", err)
+		}
+		reused := GetObjectMetaV2()
+		defer PutObjectMetaV2(reused)
+		if _, err = reused.UnmarshalMsgReuse(buf); err != nil {
+			t.Fatalf("UnmarshalMsgReuse failed where UnmarshalMsg succeeded: %v", err)
+		}
+	})
+}
+
+func FuzzObjectMetaV2DecodeMsg(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed)
+	}
+	f.Add(hostileMsg())
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		var z ObjectMetaV2
+		err := z.DecodeMsg(msgp.NewReader(bytes.NewReader(buf)))
+		var fromBytes ObjectMetaV2
+		_, bytesErr := fromBytes.UnmarshalMsg(buf)
+		if (err == nil) != (bytesErr == nil) {
+			t.Fatalf("DecodeMsg: %v, UnmarshalMsg: %v", err, bytesErr)
+		}
+	})
+}
+
+func FuzzObjectMetaV2GetJournalEntryN(f *testing.F) {
+	for _, seed := range fuzzSeeds(f) {
+		f.Add(seed, -1)
+		f.Add(seed, 0)
+	}
+	f.Add(hostileMsg(), 0)
+	f.Fuzz(func(t *testing.T, buf []byte, n int) {
+		var z ObjectMetaV2
+		z.GetJournalEntryN(buf, n, nil)
+	})
+}
+
+func FuzzDeltaEncodedInt(f *testing.F) {
+	for _, seed := range [][]int{nil, {1}, {1, 2, 3, 10000}, {5, -3, 1 << 40}} {
+		buf, _ := DeltaEncodedInt(seed).MarshalMsg(nil)
+		f.Add(buf)
+	}
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		var d DeltaEncodedInt
+		_, err := d.UnmarshalMsg(buf)
+		var streamed DeltaEncodedInt
+		streamErr := streamed.DecodeMsg(msgp.NewReader(bytes.NewReader(buf)))
+		if (err == nil) != (streamErr == nil) {
+			t.Fatalf("UnmarshalMsg: %v, DecodeMsg: %v", err, streamErr)
+		}
+		if err != nil {
+			return
+		}
+		encoded, err := d.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var again DeltaEncodedInt
+		if _, err = again.UnmarshalMsg(encoded); err != nil {
+			t.Fatal(err)
+		}
+		if len(again) != len(d) || len(streamed) != len(d) {
+			t.Fatalf("length changed: %d, %d, %d", len(d), len(again), len(streamed))
+		}
+		for i := range d {
+			if again[i] != d[i] || streamed[i] != d[i] {
+				t.Fatalf("value %d changed: %d, %d, %d", i, d[i], again[i], streamed[i])
+			}
+		}
+	})
+}
+
+func FuzzObjectMetaV2JSON(f *testing.F) {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	for _, z := range []ObjectMetaV2{getSampleObjectMetaV2(2, 2), getSampleInlineObjectMetaV2(10, 1)} {
+		buf, err := json.Marshal(z)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+	}
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		for _, api := range []jsoniter.API{jsoniter.ConfigCompatibleWithStandardLibrary, jsoniter.ConfigFastest} {
+			var z ObjectMetaV2
+			if err := api.Unmarshal(buf, &z); err != nil {
+				continue
+			}
+			encoded, err := api.Marshal(z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var again ObjectMetaV2
+			if err = api.Unmarshal(encoded, &again); err != nil {
+				t.Fatalf("re-encoded metadata does not decode: %v", err)
+			}
+		}
+	})
+}
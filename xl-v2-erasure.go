@@ -0,0 +1,199 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errInvalidErasure = errors.New("invalid erasure configuration")
+	errInvalidParts   = errors.New("invalid part information")
+	errInvalidRange   = errors.New("requested range not satisfiable")
+)
+
+// ShardRead describes a read from the shard file of a single drive.
+type ShardRead struct {
+	Shard  int   // Shard index, 0 based. Data shards come first.
+	Drive  int   // Drive index into DataErasureDistribution.
+	Offset int64 // Offset in the shard file.
+	Length int64 // Number of bytes to read from the shard file.
+}
+
+// PartRead describes the blocks and shards needed to serve a range of one part.
+type PartRead struct {
+	PartNumber int
+	PartIndex  int   // Index into DataPartInfoNumbers.
+	Offset     int64 // Offset of the range in the part.
+	Length     int64 // Length of the range in the part.
+	StartBlock int64 // First erasure block of the part that must be decoded.
+	EndBlock   int64 // Last erasure block of the part that must be decoded.
+	Shards     []ShardRead
+}
+
+// ceilFrac returns the ceiling of numerator / denominator.
+func ceilFrac(numerator, denominator int64) int64 {
+	if denominator == 0 {
+		return 0
+	}
+	return (numerator + denominator - 1) / denominator
+}
+
+// Validate checks the erasure configuration and part information of z.
+func (z *ObjectMetaV2Object) Validate() error {
+	if z.DataErasureAlgorithm != ReedSolomon {
+		return fmt.Errorf("%w: unknown algorithm %d", errInvalidErasure, z.DataErasureAlgorithm)
+	}
+	if z.DataErasureM <= 0 || z.DataErasureN < 0 || z.DataErasureBlockSize <= 0 {
+		return fmt.Errorf("%w: m=%d n=%d bsize=%d", errInvalidErasure, z.DataErasureM, z.DataErasureN, z.DataErasureBlockSize)
+	}
+	drives := z.DataErasureM + z.DataErasureN
+	if len(z.DataErasureDistribution) != drives {
+		return fmt.Errorf("%w: distribution has %d drives, want %d", errInvalidErasure, len(z.DataErasureDistribution), drives)
+	}
+	seen := make([]bool, drives)
+	for _, shard := range z.DataErasureDistribution {
+		if shard < 1 || int(shard) > drives || seen[shard-1] {
+			return fmt.Errorf("%w: distribution %v is not a permutation", errInvalidErasure, z.DataErasureDistribution)
+		}
+		seen[shard-1] = true
+	}
+	if z.DataErasureIndex < 1 || z.DataErasureIndex > drives {
+		return fmt.Errorf("%w: index %d out of range", errInvalidErasure, z.DataErasureIndex)
+	}
+	if len(z.DataPartInfoNumbers) != len(z.DataPartInfoSizes) {
+		return fmt.Errorf("%w: %d part numbers, %d part sizes", errInvalidParts, len(z.DataPartInfoNumbers), len(z.DataPartInfoSizes))
+	}
+	if len(z.DataPartInfoChecksums) != 0 && len(z.DataPartInfoChecksums) != len(z.DataPartInfoNumbers) {
+		return fmt.Errorf("%w: %d part checksums, %d parts", errInvalidParts, len(z.DataPartInfoChecksums), len(z.DataPartInfoNumbers))
+	}
+	prev := 0
+	for i, n := range z.DataPartInfoNumbers {
+		if n <= prev {
+			return fmt.Errorf("%w: part numbers not ascending at index %d", errInvalidParts, i)
+		}
+		if z.DataPartInfoSizes[i] < 0 {
+			return fmt.Errorf("%w: negative size for part %d", errInvalidParts, n)
+		}
+		prev = n
+	}
+	if err := z.validateTransition(); err != nil {
+		return err
+	}
+	return z.validateSSE()
+}
+
+// ShardSize returns the size of the shard each drive stores for a full erasure block.
+func (z *ObjectMetaV2Object) ShardSize() int64 {
+	return ceilFrac(int64(z.DataErasureBlockSize), int64(z.DataErasureM))
+}
+
+// ShardFileSize returns the size of the shard file each drive stores for a part of partSize bytes.
+func (z *ObjectMetaV2Object) ShardFileSize(partSize int64) int64 {
+	blockSize := int64(z.DataErasureBlockSize)
+	size := (partSize / blockSize) * z.ShardSize()
+	if last := partSize % blockSize; last > 0 {
+		size += ceilFrac(last, int64(z.DataErasureM))
+	}
+	return size
+}
+
+// ShardFileSizes returns the shard file size of every part.
+func (z *ObjectMetaV2Object) ShardFileSizes() []int64 {
+	stored := z.storedPartSizes()
+	sizes := make([]int64, len(stored))
+	for i, size := range stored {
+		sizes[i] = z.ShardFileSize(int64(size))
+	}
+	return sizes
+}
+
+// ShardDrive returns the index of the drive holding shard, or -1 if no drive holds it.
+func (z *ObjectMetaV2Object) ShardDrive(shard int) int {
+	for drive, s := range z.DataErasureDistribution {
+		if int(s) == shard+1 {
+			return drive
+		}
+	}
+	return -1
+}
+
+// PartsSize returns the sum of all part sizes as stored, encrypted if the object is.
+func (z *ObjectMetaV2Object) PartsSize() int64 {
+	var size int64
+	for _, s := range z.storedPartSizes() {
+		size += int64(s)
+	}
+	return size
+}
+
+// ReadLayout returns the parts, blocks, shards and drives that must be read
+// to serve length bytes of the object starting at offset.
+// Only data shards are returned; parity shards are located with ShardDrive.
+// Offsets of encrypted objects are in encrypted bytes, see EncryptedRange.
+func (z *ObjectMetaV2Object) ReadLayout(offset, length int64) ([]PartRead, error) {
+	if z.IsInline() {
+		return nil, errDataInline
+	}
+	if !z.hasLocalParts() {
+		return nil, errDataTransitioned
+	}
+	if err := z.Validate(); err != nil {
+		return nil, err
+	}
+	if offset < 0 || length < 0 || offset+length > z.PartsSize() {
+		return nil, fmt.Errorf("%w: offset %d, length %d, size %d", errInvalidRange, offset, length, z.PartsSize())
+	}
+	shardDrives := make([]int, z.DataErasureM)
+	for shard := range shardDrives {
+		shardDrives[shard] = z.ShardDrive(shard)
+	}
+
+	blockSize := int64(z.DataErasureBlockSize)
+	shardSize := z.ShardSize()
+	var reads []PartRead
+	var partStart int64
+	for i, size := range z.storedPartSizes() {
+		partSize := int64(size)
+		partEnd := partStart + partSize
+		// Empty parts hold none of the range.
+		if length == 0 || partEnd <= offset || partSize == 0 {
+			partStart = partEnd
+			continue
+		}
+		if partStart >= offset+length {
+			break
+		}
+		read := PartRead{
+			PartNumber: z.DataPartInfoNumbers[i],
+			PartIndex:  i,
+			Offset:     offset - partStart,
+		}
+		if read.Offset < 0 {
+			read.Offset = 0
+		}
+		read.Length = partSize - read.Offset
+		if rest := offset + length - (partStart + read.Offset); rest < read.Length {
+			read.Length = rest
+		}
+		read.StartBlock = read.Offset / blockSize
+		read.EndBlock = (read.Offset + read.Length - 1) / blockSize
+
+		shardOffset := read.StartBlock * shardSize
+		shardEnd := (read.EndBlock + 1) * shardSize
+		if fileSize := z.ShardFileSize(partSize); shardEnd > fileSize {
+			shardEnd = fileSize
+		}
+		read.Shards = make([]ShardRead, len(shardDrives))
+		for shard, drive := range shardDrives {
+			read.Shards[shard] = ShardRead{
+				Shard:  shard,
+				Drive:  drive,
+				Offset: shardOffset,
+				Length: shardEnd - shardOffset,
+			}
+		}
+		reads = append(reads, read)
+		partStart = partEnd
+	}
+	return reads, nil
+}
@@ -0,0 +1,175 @@
+package xlmeta
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// randomErasureObject returns an object with a random but valid erasure geometry.
+func randomErasureObject(r *rand.Rand) *ObjectMetaV2Object {
+	m := 1 + r.Intn(16)
+	n := r.Intn(17)
+	obj := &ObjectMetaV2Object{
+		DataErasureAlgorithm: ReedSolomon,
+		DataErasureM:         m,
+		DataErasureN:         n,
+		DataErasureBlockSize: 1 + r.Intn(1<<20),
+		DataErasureIndex:     1 + r.Intn(m+n),
+	}
+	for _, shard := range r.Perm(m + n) {
+		obj.DataErasureDistribution = append(obj.DataErasureDistribution, uint8(shard+1))
+	}
+	nparts := 1 + r.Intn(20)
+	number := 0
+	for i := 0; i < nparts; i++ {
+		number += 1 + r.Intn(3)
+		obj.DataPartInfoNumbers = append(obj.DataPartInfoNumbers, number)
+		obj.DataPartInfoSizes = append(obj.DataPartInfoSizes, r.Intn(5*obj.DataErasureBlockSize+1))
+	}
+	obj.StatSize = int(obj.PartsSize())
+	return obj
+}
+
+func TestObjectMetaV2ObjectShardFileSize(t *testing.T) {
+	property := func(seed int64) bool {
+		obj := randomErasureObject(rand.New(rand.NewSource(seed)))
+		m := int64(obj.DataErasureM)
+		for _, size := range obj.DataPartInfoSizes {
+			partSize := int64(size)
+			blocks := ceilFrac(partSize, int64(obj.DataErasureBlockSize))
+			shardFile := obj.ShardFileSize(partSize)
+			// All data shards together hold the part, padded by less than m bytes per block.
+			if shardFile*m < partSize || shardFile*m >= partSize+m*blocks+1 {
+				t.Logf("seed %d: part %d, shard file %d, m %d", seed, partSize, shardFile, m)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObjectMetaV2ObjectReadLayout(t *testing.T) {
+	property := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		obj := randomErasureObject(r)
+		size := obj.PartsSize()
+		offset := r.Int63n(size + 1)
+		length := r.Int63n(size - offset + 1)
+
+		reads, err := obj.ReadLayout(offset, length)
+		if err != nil {
+			t.Logf("seed %d: %v", seed, err)
+			return false
+		}
+		blockSize := int64(obj.DataErasureBlockSize)
+		pos, covered, prevIndex := offset, int64(0), -1
+		for _, read := range reads {
+			var partStart int64
+			for _, s := range obj.DataPartInfoSizes[:read.PartIndex] {
+				partStart += int64(s)
+			}
+			partSize := int64(obj.DataPartInfoSizes[read.PartIndex])
+			switch {
+			case read.PartIndex <= prevIndex,
+				read.PartNumber != obj.DataPartInfoNumbers[read.PartIndex],
+				partStart+read.Offset != pos,
+				read.Length <= 0,
+				read.Offset+read.Length > partSize,
+				read.StartBlock != read.Offset/blockSize,
+				read.EndBlock != (read.Offset+read.Length-1)/blockSize,
+				len(read.Shards) != obj.DataErasureM:
+				t.Logf("seed %d: bad part read %+v", seed, read)
+				return false
+			}
+			blockBytes := (read.EndBlock+1)*blockSize - read.StartBlock*blockSize
+			if blockBytes > partSize-read.StartBlock*blockSize {
+				blockBytes = partSize - read.StartBlock*blockSize
+			}
+			drives := make(map[int]bool)
+			for shard, sr := range read.Shards {
+				switch {
+				case sr.Shard != shard,
+					drives[sr.Drive],
+					int(obj.DataErasureDistribution[sr.Drive]) != shard+1,
+					sr.Offset != read.StartBlock*obj.ShardSize(),
+					sr.Offset+sr.Length > obj.ShardFileSize(partSize),
+					sr.Length*int64(obj.DataErasureM) < blockBytes:
+					t.Logf("seed %d: bad shard read %+v of %+v", seed, sr, read)
+					return false
+				}
+				drives[sr.Drive] = true
+			}
+			pos += read.Length
+			covered += read.Length
+			prevIndex = read.PartIndex
+		}
+		if covered != length {
+			t.Logf("seed %d: covered %d of %d bytes", seed, covered, length)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObjectMetaV2ObjectReadLayoutEmptyParts(t *testing.T) {
+	obj := &ObjectMetaV2Object{
+		DataErasureAlgorithm:    ReedSolomon,
+		DataErasureM:            2,
+		DataErasureN:            1,
+		DataErasureBlockSize:    4,
+		DataErasureIndex:        1,
+		DataErasureDistribution: []uint8{1, 2, 3},
+		DataPartInfoNumbers:     []int{1, 2, 3, 4},
+		DataPartInfoSizes:       []int{6, 0, 5, 0},
+	}
+	// part is the expected read of a part: index, offset and length.
+	type part struct{ index, offset, length int64 }
+	testCases := []struct {
+		offset, length int64
+		want           []part
+	}{
+		{0, 11, []part{{0, 0, 6}, {2, 0, 5}}},
+		{5, 2, []part{{0, 5, 1}, {2, 0, 1}}},
+		{6, 5, []part{{2, 0, 5}}},
+		{10, 1, []part{{2, 4, 1}}},
+		{11, 0, nil},
+	}
+	for _, tc := range testCases {
+		reads, err := obj.ReadLayout(tc.offset, tc.length)
+		if err != nil {
+			t.Fatalf("offset %d, length %d: %v", tc.offset, tc.length, err)
+		}
+		var got []part
+		for _, read := range reads {
+			got = append(got, part{int64(read.PartIndex), read.Offset, read.Length})
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("offset %d, length %d: got %v, want %v", tc.offset, tc.length, got, tc.want)
+		}
+	}
+}
+
+func TestObjectMetaV2ObjectReadLayoutErrors(t *testing.T) {
+	obj := newObjectMetaV2Object(2)
+	if _, err := obj.ReadLayout(0, obj.PartsSize()+1); !errors.Is(err, errInvalidRange) {
+		t.Fatalf("want %v, got %v", errInvalidRange, err)
+	}
+	obj.DataErasureDistribution[0] = obj.DataErasureDistribution[1]
+	if _, err := obj.ReadLayout(0, 1); !errors.Is(err, errInvalidErasure) {
+		t.Fatalf("want %v, got %v", errInvalidErasure, err)
+	}
+	obj = newObjectMetaV2Object(2)
+	obj.DataPartInfoNumbers[1] = 1
+	if err := obj.Validate(); !errors.Is(err, errInvalidParts) {
+		t.Fatalf("want %v, got %v", errInvalidParts, err)
+	}
+}
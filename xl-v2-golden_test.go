@@ -0,0 +1,293 @@
+package xlmeta
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate the golden metadata in testdata/golden")
+
+const goldenDir = "testdata/golden"
+
+// goldenCases returns the metadata written to the golden corpus by -update.
+func goldenCases() map[string]ObjectMetaV2 {
+	modTime := DefaultWorkload.ModTime.Unix()
+
+	deleteMarkers := ObjectMetaV2{Version: 200, Format: XL}
+	for i := 0; i < 3; i++ {
+		deleteMarkers.ObjectJournals = append(deleteMarkers.ObjectJournals, ObjectMetaV2JournalEntry{
+			Type:         Delete,
+			DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: uint64(i + 1), ModTime: modTime + int64(i)},
+		})
+	}
+
+	unicode := getSampleObjectMetaV2(1, 1)
+	unicode.ObjectJournals[0].Object.MetaUser = map[string][]string{
+		"content-type":        {"text/plain; charset=utf-8"},
+		"X-Amz-Meta-Título":   {"Ñandú", "日本語のテキスト"},
+		"X-Amz-Meta-Emoji":    {"🪣📦"},
+		"X-Amz-Meta-Empty":    {""},
+		"X-Amz-Meta-Ελληνικά": {"κείμενο", "ünïcödé"},
+	}
+
+	features := getSampleObjectMetaV2(2, 4)
+	objs := features.ObjectJournals
+	if err := objs[0].Object.SetInlineData([]byte("inline object data")); err != nil {
+		panic(err)
+	}
+	objs[1].Object.RetentionMode = RetentionCompliance
+	objs[1].Object.RetainUntil = modTime + 86400
+	objs[1].Object.LegalHold = true
+	objs[1].Object.DataPartInfoChecksums = [][]byte{bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)}
+	objs[2].Object.Replication = []ObjectMetaV2Replication{
+		{ARN: "arn:minio:replication::a:bucket", Status: ReplicationCompleted, ModTime: modTime},
+		{ARN: "arn:minio:replication::b:bucket", Status: ReplicationFailed, ModTime: modTime, Resync: "resync-1"},
+	}
+	obj := objs[3].Object
+	obj.SSE = &ObjectMetaV2SSE{
+		Type:      SSEKMS,
+		Algorithm: "DAREv2-HMAC-SHA256",
+		SealedKey: bytes.Repeat([]byte{3}, 64),
+		IV:        bytes.Repeat([]byte{4}, 32),
+		KeyID:     "my-key",
+		DataKey:   bytes.Repeat([]byte{5}, 48),
+		Context:   []byte(`{"bucket":"object"}`),
+	}
+	for _, size := range obj.DataPartInfoSizes {
+		obj.SSE.PartSizes = append(obj.SSE.PartSizes, int(EncryptedSize(int64(size))))
+	}
+	link := *objs[2].Object
+	link.VersionID++
+	features.ObjectJournals = append(features.ObjectJournals, ObjectMetaV2JournalEntry{Type: Link, Link: (*ObjectMetaV2Link)(&link)})
+	template := *objs[1].Object
+	template.MetaUser = map[string][]string{"content-type": {"application/zip"}}
+	if err := features.NewMultipartUpload("upload-id", template, time.Unix(modTime, 0)); err != nil {
+		panic(err)
+	}
+	upload := features.ObjectJournals[len(features.ObjectJournals)-1].Multipart
+	upload.Parts = []ObjectMetaV2MultipartPart{
+		{Number: 1, Size: minPartSize, ETag: "0123456789abcdef0123456789abcdef", ModTime: modTime},
+		{Number: 3, Size: 100, ETag: "fedcba9876543210fedcba9876543210", ModTime: modTime + 1},
+	}
+
+	tiered := getSampleObjectMetaV2(2, 2)
+	if err := tiered.ObjectJournals[0].Object.Transition("WARM-TIER", "prefix/remote-object-0", "remote-version-0"); err != nil {
+		panic(err)
+	}
+	restored := tiered.ObjectJournals[1].Object
+	if err := restored.Transition("WARM-TIER", "prefix/remote-object-1", ""); err != nil {
+		panic(err)
+	}
+	if err := restored.Restore(0x1234, time.Unix(modTime+7*86400, 0)); err != nil {
+		panic(err)
+	}
+
+	// parts-10000 covers large part counts.
+	workload := DefaultWorkload
+	workload.PartCount.Max = 100
+
+	return map[string]ObjectMetaV2{
+		"empty":          {Version: 200, Format: XL},
+		"delete-markers": deleteMarkers,
+		"parts-10000":    getSampleObjectMetaV2(maxPartNumber, 1),
+		"unicode":        unicode,
+		"workload":       workload.Generate(20),
+		"features":       features,
+		"tiered":         tiered,
+	}
+}
+
+// canonicalMsg appends the msgpack object at the start of b to dst with the
+// entries of metadata maps sorted by key, since Go maps are encoded in random
+// order. Struct fields keep their order. It returns the bytes after the object.
+// It compares encodings that are not stable; golden files hold them as encoded.
+func canonicalMsg(dst, b []byte, sortMap bool) ([]byte, []byte, error) {
+	switch msgp.NextType(b) {
+	case msgp.MapType:
+		n, rest, err := msgp.ReadMapHeaderBytes(b)
+		if err != nil {
+			return dst, b, err
+		}
+		type entry struct{ key, value []byte }
+		entries := make([]entry, n)
+		for i := range entries {
+			var key []byte
+			start := rest
+			if key, rest, err = msgp.ReadMapKeyZC(rest); err != nil {
+				return dst, b, err
+			}
+			entries[i].key = start[:len(start)-len(rest)]
+			metadata := string(key) == "msys" || string(key) == "muser"
+			if entries[i].value, rest, err = canonicalMsg(nil, rest, metadata); err != nil {
+				return dst, b, err
+			}
+		}
+		if sortMap {
+			sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+		}
+		dst = msgp.AppendMapHeader(dst, n)
+		for _, e := range entries {
+			dst = append(append(dst, e.key...), e.value...)
+		}
+		return dst, rest, nil
+	case msgp.ArrayType:
+		n, rest, err := msgp.ReadArrayHeaderBytes(b)
+		if err != nil {
+			return dst, b, err
+		}
+		dst = msgp.AppendArrayHeader(dst, n)
+		for i := uint32(0); i < n; i++ {
+			if dst, rest, err = canonicalMsg(dst, rest, false); err != nil {
+				return dst, b, err
+			}
+		}
+		return dst, rest, nil
+	}
+	rest, err := msgp.Skip(b)
+	if err != nil {
+		return dst, b, err
+	}
+	return append(dst, b[:len(b)-len(rest)]...), rest, nil
+}
+
+// stableEncoding reports whether the msgpack and jsoniter-fast encodings of z
+// are always the same. They are unless a metadata map has several keys,
+// since Go maps are encoded in random order.
+func stableEncoding(z *ObjectMetaV2) bool {
+	for i := range z.ObjectJournals {
+		if obj := z.ObjectJournals[i].metaObject(); obj != nil && (len(obj.MetaSys) > 1 || len(obj.MetaUser) > 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// golden holds the encodings of metadata as written by each encoder.
+type golden struct {
+	msg  []byte // msgpack
+	js   []byte // Indented jsoniter-compat, which sorts map keys.
+	fast []byte // jsoniter-fast
+}
+
+// goldenEncode returns the encodings of z.
+func goldenEncode(t *testing.T, z *ObjectMetaV2) (g golden) {
+	var err error
+	if g.msg, err = z.MarshalMsg(nil); err != nil {
+		t.Fatal(err)
+	}
+	if g.js, err = jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(z, "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	g.js = append(g.js, '\n')
+	if g.fast, err = jsoniter.ConfigFastest.Marshal(z); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// sameMsg reports whether the msgpack encodings got and want are equal,
+// or equal once metadata maps are sorted if the encoding is not stable.
+func sameMsg(t *testing.T, got, want []byte, stable bool) bool {
+	if stable {
+		return bytes.Equal(got, want)
+	}
+	got, _, err := canonicalMsg(nil, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err = canonicalMsg(nil, want, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Equal(got, want)
+}
+
+func TestGoldenCorpus(t *testing.T) {
+	if *updateGolden {
+		for name, z := range goldenCases() {
+			g := goldenEncode(t, &z)
+			path := filepath.Join(goldenDir, name)
+			for ext, buf := range map[string][]byte{".msgp": g.msg, ".json": g.js, ".fast.json": g.fast} {
+				if err := ioutil.WriteFile(path+ext, buf, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(goldenDir, "*.msgp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < len(goldenCases()) {
+		t.Fatalf("found %d golden files, want at least %d; run with -update", len(files), len(goldenCases()))
+	}
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), ".msgp")
+		t.Run(name, func(t *testing.T) {
+			var want golden
+			for _, f := range []struct {
+				ext string
+				buf *[]byte
+			}{{".msgp", &want.msg}, {".json", &want.js}, {".fast.json", &want.fast}} {
+				buf, err := ioutil.ReadFile(filepath.Join(goldenDir, name+f.ext))
+				if err != nil {
+					t.Fatal(err)
+				}
+				*f.buf = buf
+			}
+
+			var z ObjectMetaV2
+			if rest, err := z.UnmarshalMsg(want.msg); err != nil {
+				t.Fatal(err)
+			} else if len(rest) > 0 {
+				t.Fatalf("%d bytes left after UnmarshalMsg", len(rest))
+			}
+			stable := stableEncoding(&z)
+			got := goldenEncode(t, &z)
+			if !bytes.Equal(got.js, want.js) {
+				t.Error("decoded metadata differs from the JSON expectation")
+			}
+			if !sameMsg(t, got.msg, want.msg, stable) {
+				t.Error("re-encoded metadata differs from the golden file")
+			}
+			if stable && !bytes.Equal(got.fast, want.fast) {
+				t.Error("re-encoded jsoniter-fast metadata differs from the golden file")
+			}
+
+			for _, tc := range []struct {
+				api jsoniter.API
+				js  []byte
+			}{
+				{jsoniter.ConfigCompatibleWithStandardLibrary, want.js},
+				{jsoniter.ConfigFastest, want.js},
+				{jsoniter.ConfigFastest, want.fast},
+			} {
+				var fromJSON ObjectMetaV2
+				if err := tc.api.Unmarshal(tc.js, &fromJSON); err != nil {
+					t.Fatal(err)
+				}
+				if !sameMsg(t, goldenEncode(t, &fromJSON).msg, want.msg, stable) {
+					t.Error("metadata decoded from JSON encodes differently")
+				}
+			}
+
+			var streamed ObjectMetaV2
+			if err := streamed.DecodeMsg(msgp.NewReader(bytes.NewReader(want.msg))); err != nil {
+				t.Fatal(err)
+			}
+			if !sameMsg(t, goldenEncode(t, &streamed).msg, want.msg, stable) {
+				t.Error("DecodeMsg and UnmarshalMsg disagree")
+			}
+		})
+	}
+}
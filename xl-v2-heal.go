@@ -0,0 +1,303 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var errNoHealDrives = errors.New("no drives to heal")
+
+// HealDrive is the metadata of an object read from one drive of its erasure set.
+type HealDrive struct {
+	// Meta is nil when the metadata is missing or could not be read.
+	Meta *ObjectMetaV2
+	// Offline drives can neither be read nor written.
+	Offline bool
+}
+
+// HealOptions controls PlanHeal.
+type HealOptions struct {
+	// VerifyShards checks the shard files of obj, the copy of a version on
+	// drive, typically with VerifyParts. Shards are assumed intact when nil.
+	VerifyShards func(drive int, obj *ObjectMetaV2Object) error
+}
+
+// HealActionType is the kind of a HealAction.
+type HealActionType uint8
+
+const (
+	// HealRewriteMetadata writes the metadata of a version to a drive
+	// missing it or holding a copy that differs from the other drives.
+	HealRewriteMetadata HealActionType = iota + 1
+	// HealReconstructShard rebuilds the shard of a version on a drive
+	// from the shards of other drives.
+	HealReconstructShard
+	// HealUnrecoverable reports a version with too few sources to be healed.
+	HealUnrecoverable
+)
+
+func (t HealActionType) String() string {
+	switch t {
+	case HealRewriteMetadata:
+		return "rewrite-metadata"
+	case HealReconstructShard:
+		return "reconstruct-shard"
+	case HealUnrecoverable:
+		return "unrecoverable"
+	}
+	return fmt.Sprintf("HealActionType(%d)", uint8(t))
+}
+
+// HealAction is a repair of a version, or a version that cannot be repaired.
+type HealAction struct {
+	Type      HealActionType
+	VersionID uint64
+	Drive     int // Drive to repair, -1 for unrecoverable versions.
+	Shard     int // Shard to reconstruct, 0 based, -1 for other actions.
+	// Sources are the drives to read from: drives with the agreed metadata
+	// for rewrites and with intact shards for reconstructions. For
+	// unrecoverable versions, the sources left.
+	Sources []int
+	Need    int // Sources needed to heal the version.
+}
+
+// HealPlan lists the actions healing an object.
+// Actions are sorted by type, version and drive.
+type HealPlan struct {
+	Drives   int
+	Offline  []int
+	Versions int // Versions, delete markers included, found on any drive.
+	Actions  []HealAction
+}
+
+// Healthy returns whether the object needs no repair.
+func (p *HealPlan) Healthy() bool {
+	return len(p.Actions) == 0
+}
+
+// healVersion gathers the copies of a version across drives.
+type healVersion struct {
+	id     uint64
+	copies []healCopy                  // Distinct copies, in order of first drive.
+	objs   map[int]*ObjectMetaV2Object // Object of the version on each drive, nil for delete markers.
+}
+
+// healCopy is a copy of a version and the drives holding it.
+type healCopy struct {
+	entry  ObjectMetaV2JournalEntry // Without the fields specific to each drive.
+	drives []int
+}
+
+// healEntry returns a copy of e without the fields that differ between the
+// drives of an erasure set: the erasure index and the part checksums,
+// which are computed on the shard of each drive.
+func healEntry(e *ObjectMetaV2JournalEntry) ObjectMetaV2JournalEntry {
+	c := *e
+	switch {
+	case e.Type == Object && e.Object != nil:
+		obj := *e.Object
+		obj.DataErasureIndex, obj.DataPartInfoChecksums = 0, nil
+		c.Object = &obj
+	case e.Type == Link && e.Link != nil:
+		link := *e.Link
+		link.DataErasureIndex, link.DataPartInfoChecksums = 0, nil
+		c.Link = &link
+	}
+	return c
+}
+
+// add records the copy e of v on drive.
+func (v *healVersion) add(drive int, e *ObjectMetaV2JournalEntry) {
+	v.objs[drive] = e.replicationObject()
+	entry := healEntry(e)
+	for i := range v.copies {
+		if reflect.DeepEqual(v.copies[i].entry, entry) {
+			v.copies[i].drives = append(v.copies[i].drives, drive)
+			return
+		}
+	}
+	v.copies = append(v.copies, healCopy{entry: entry, drives: []int{drive}})
+}
+
+// PlanHeal compares the metadata of an object on the drives of its erasure
+// set, indexed like DataErasureDistribution, and returns the actions that
+// heal it. The metadata held by most drives is the reference copy of each
+// version. A version with data needs DataErasureM drives holding the
+// reference metadata and an intact shard; delete markers and transitioned
+// versions only need the metadata, of DataErasureM drives for transitioned
+// versions and of half of the drives for delete markers.
+// Offline drives are sources of nothing and are not repaired.
+func PlanHeal(drives []HealDrive, opts HealOptions) (*HealPlan, error) {
+	if len(drives) == 0 {
+		return nil, errNoHealDrives
+	}
+	plan := &HealPlan{Drives: len(drives)}
+	versions := make(map[uint64]*healVersion)
+	for d, drive := range drives {
+		if drive.Offline {
+			plan.Offline = append(plan.Offline, d)
+			continue
+		}
+		if drive.Meta == nil {
+			continue
+		}
+		for _, idx := range drive.Meta.versionIndexes() {
+			e := &drive.Meta.ObjectJournals[idx]
+			v := versions[e.VersionID()]
+			if v == nil {
+				v = &healVersion{id: e.VersionID(), objs: make(map[int]*ObjectMetaV2Object)}
+				versions[v.id] = v
+			}
+			v.add(d, e)
+		}
+	}
+	plan.Versions = len(versions)
+
+	for _, v := range versions {
+		actions, err := v.plan(drives, opts)
+		if err != nil {
+			return nil, err
+		}
+		plan.Actions = append(plan.Actions, actions...)
+	}
+	sort.Slice(plan.Actions, func(i, j int) bool {
+		a, b := &plan.Actions[i], &plan.Actions[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.VersionID != b.VersionID {
+			return a.VersionID < b.VersionID
+		}
+		return a.Drive < b.Drive
+	})
+	return plan, nil
+}
+
+// reference returns the drives holding the most common copy of v.
+// Ties are broken by the lowest drive index.
+func (v *healVersion) reference() []int {
+	var best []int
+	for _, c := range v.copies {
+		if len(c.drives) > len(best) || (len(c.drives) == len(best) && c.drives[0] < best[0]) {
+			best = c.drives
+		}
+	}
+	return best
+}
+
+// plan returns the actions healing v.
+func (v *healVersion) plan(drives []HealDrive, opts HealOptions) ([]HealAction, error) {
+	ref := v.reference()
+	obj := v.objs[ref[0]]
+	// Delete markers have no erasure configuration to take a quorum from.
+	// They need the read quorum of the default one, whose parity is half
+	// of the drives, and so as many copies as it has data shards.
+	need := len(drives) / 2
+	if need == 0 {
+		need = 1
+	}
+	hasShards := false
+	if obj != nil {
+		if len(obj.DataErasureDistribution) != len(drives) {
+			return nil, fmt.Errorf("%w: version %s is distributed on %d drives, not %d",
+				errInvalidErasure, FormatVersionID(v.id), len(obj.DataErasureDistribution), len(drives))
+		}
+		need = obj.DataErasureM
+		hasShards = !obj.IsInline() && obj.hasLocalParts() && obj.PartsSize() > 0
+	}
+
+	sources := ref
+	if hasShards {
+		sources = nil
+		for _, d := range ref {
+			if opts.VerifyShards == nil || opts.VerifyShards(d, v.objs[d]) == nil {
+				sources = append(sources, d)
+			}
+		}
+	}
+	if len(sources) < need {
+		return []HealAction{{Type: HealUnrecoverable, VersionID: v.id, Drive: -1, Shard: -1, Sources: sources, Need: need}}, nil
+	}
+
+	var actions []HealAction
+	inRef := make(map[int]bool, len(ref))
+	for _, d := range ref {
+		inRef[d] = true
+	}
+	for d, drive := range drives {
+		if drive.Offline || inRef[d] {
+			continue
+		}
+		actions = append(actions, HealAction{Type: HealRewriteMetadata, VersionID: v.id, Drive: d, Shard: -1, Sources: ref, Need: need})
+	}
+	if hasShards {
+		intact := make(map[int]bool, len(sources))
+		for _, d := range sources {
+			intact[d] = true
+		}
+		for d, drive := range drives {
+			if drive.Offline || intact[d] {
+				continue
+			}
+			shard := int(obj.DataErasureDistribution[d]) - 1
+			actions = append(actions, HealAction{Type: HealReconstructShard, VersionID: v.id, Drive: d, Shard: shard, Sources: sources, Need: need})
+		}
+	}
+	return actions, nil
+}
+
+// WriteReport writes the plan for a dry run, one action per line, followed
+// by a summary:
+//
+//	ACTION             VERSION           DRIVE  SHARD  SOURCES
+//	rewrite-metadata   0000000000000001  2      -      0,1,3
+//	reconstruct-shard  0000000000000001  2      4      0,1,3
+//	unrecoverable      0000000000000002  -      -      1 of 2: 0
+//	1 metadata rewrites, 1 shard reconstructions, 1 unrecoverable versions; 3 versions on 4 drives, offline: none
+func (p *HealPlan) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tVERSION\tDRIVE\tSHARD\tSOURCES")
+	var counts [HealUnrecoverable + 1]int
+	for _, a := range p.Actions {
+		counts[a.Type]++
+		drive, shard, sources := "-", "-", joinInts(a.Sources)
+		if a.Drive >= 0 {
+			drive = strconv.Itoa(a.Drive)
+		}
+		if a.Shard >= 0 {
+			shard = strconv.Itoa(a.Shard)
+		}
+		if a.Type == HealUnrecoverable {
+			sources = fmt.Sprintf("%d of %d: %s", len(a.Sources), a.Need, sources)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", a.Type, FormatVersionID(a.VersionID), drive, shard, sources)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	offline := "none"
+	if len(p.Offline) > 0 {
+		offline = joinInts(p.Offline)
+	}
+	_, err := fmt.Fprintf(w, "%d metadata rewrites, %d shard reconstructions, %d unrecoverable versions; %d versions on %d drives, offline: %s\n",
+		counts[HealRewriteMetadata], counts[HealReconstructShard], counts[HealUnrecoverable], p.Versions, p.Drives, offline)
+	return err
+}
+
+// joinInts formats ints separated by commas, or "-" if there are none.
+func joinInts(ints []int) string {
+	if len(ints) == 0 {
+		return "-"
+	}
+	s := make([]string, len(ints))
+	for i, n := range ints {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,245 @@
+package xlmeta
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// healDrives returns the metadata of an object on the 4 drives of a 2+2
+// erasure set: versions 1 and 3 with data, delete marker 2 and version 4
+// transitioned to a remote tier.
+func healDrives(t *testing.T) []HealDrive {
+	t.Helper()
+	var z ObjectMetaV2
+	for id := uint64(1); id <= 4; id++ {
+		if id == 2 {
+			z.AddDeleteMarker(id, day(int(id)))
+			continue
+		}
+		obj := newObjectMetaV2Object(2)
+		obj.VersionID, obj.StatModTime = id, day(int(id)).Unix()
+		obj.DataErasureM, obj.DataErasureN = 2, 2
+		obj.DataErasureDistribution = []uint8{3, 1, 4, 2}
+		if id == 4 {
+			if err := obj.Transition("WARM", "remote", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		z.ObjectJournals = append(z.ObjectJournals, ObjectMetaV2JournalEntry{Type: Object, Object: obj})
+	}
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drives := make([]HealDrive, 4)
+	for d := range drives {
+		meta := &ObjectMetaV2{}
+		if _, err := meta.UnmarshalMsg(buf); err != nil {
+			t.Fatal(err)
+		}
+		// The erasure index and the checksums are specific to each drive.
+		for _, e := range meta.ObjectJournals {
+			if e.Type == Object {
+				e.Object.DataErasureIndex = d + 1
+				e.Object.DataPartInfoChecksums = [][]byte{{byte(d)}, {byte(d)}}
+			}
+		}
+		drives[d].Meta = meta
+	}
+	return drives
+}
+
+func TestPlanHeal(t *testing.T) {
+	rewrite := func(id uint64, drive int, sources ...int) HealAction {
+		return HealAction{Type: HealRewriteMetadata, VersionID: id, Drive: drive, Shard: -1, Sources: sources, Need: 2}
+	}
+	reconstruct := func(id uint64, drive, shard int, sources ...int) HealAction {
+		return HealAction{Type: HealReconstructShard, VersionID: id, Drive: drive, Shard: shard, Sources: sources, Need: 2}
+	}
+	unrecoverable := func(id uint64, sources ...int) HealAction {
+		return HealAction{Type: HealUnrecoverable, VersionID: id, Drive: -1, Shard: -1, Sources: sources, Need: 2}
+	}
+
+	testCases := []struct {
+		name    string
+		fail    func(drives []HealDrive)
+		corrupt map[int]uint64 // Drives with a corrupt shard of a version.
+		offline []int
+		want    []HealAction
+	}{
+		{
+			name: "healthy",
+			fail: func(drives []HealDrive) {},
+		},
+		{
+			name:    "offline drive",
+			fail:    func(drives []HealDrive) { drives[1].Offline = true },
+			offline: []int{1},
+		},
+		{
+			name: "missing metadata",
+			fail: func(drives []HealDrive) { drives[2].Meta = nil },
+			want: []HealAction{
+				rewrite(1, 2, 0, 1, 3),
+				rewrite(2, 2, 0, 1, 3),
+				rewrite(3, 2, 0, 1, 3),
+				rewrite(4, 2, 0, 1, 3),
+				reconstruct(1, 2, 3, 0, 1, 3),
+				reconstruct(3, 2, 3, 0, 1, 3),
+			},
+		},
+		{
+			name: "missing latest version",
+			fail: func(drives []HealDrive) {
+				meta := drives[0].Meta
+				meta.ObjectJournals = meta.ObjectJournals[:3]
+			},
+			want: []HealAction{rewrite(4, 0, 1, 2, 3)},
+		},
+		{
+			name: "stale metadata",
+			fail: func(drives []HealDrive) {
+				drives[3].Meta.ObjectJournals[0].Object.MetaUser = map[string][]string{"etag": {"stale"}}
+			},
+			want: []HealAction{
+				rewrite(1, 3, 0, 1, 2),
+				reconstruct(1, 3, 1, 0, 1, 2),
+			},
+		},
+		{
+			name:    "corrupt shard",
+			fail:    func(drives []HealDrive) {},
+			corrupt: map[int]uint64{1: 3},
+			want:    []HealAction{reconstruct(3, 1, 0, 0, 2, 3)},
+		},
+		{
+			name: "offline and corrupt",
+			fail: func(drives []HealDrive) {
+				drives[0].Offline = true
+				drives[2].Meta = nil
+			},
+			corrupt: map[int]uint64{1: 1},
+			offline: []int{0},
+			want: []HealAction{
+				rewrite(2, 2, 1, 3),
+				rewrite(3, 2, 1, 3),
+				rewrite(4, 2, 1, 3),
+				reconstruct(3, 2, 3, 1, 3),
+				unrecoverable(1, 3),
+			},
+		},
+		{
+			name: "lost quorum",
+			fail: func(drives []HealDrive) {
+				drives[0].Offline = true
+				drives[1].Meta = nil
+				drives[2].Meta = nil
+			},
+			offline: []int{0},
+			want: []HealAction{
+				unrecoverable(1, 3),
+				unrecoverable(2, 3),
+				unrecoverable(3, 3),
+				unrecoverable(4, 3),
+			},
+		},
+		{
+			name: "tied copies",
+			fail: func(drives []HealDrive) {
+				for _, d := range drives[2:] {
+					d.Meta.ObjectJournals[0].Object.MetaUser = map[string][]string{"etag": {"stale"}}
+				}
+			},
+			want: []HealAction{
+				rewrite(1, 2, 0, 1),
+				rewrite(1, 3, 0, 1),
+				reconstruct(1, 2, 3, 0, 1),
+				reconstruct(1, 3, 1, 0, 1),
+			},
+		},
+		{
+			name: "delete marker on half of the drives",
+			fail: func(drives []HealDrive) {
+				for _, d := range drives[:2] {
+					journals := d.Meta.ObjectJournals
+					d.Meta.ObjectJournals = append(journals[:1], journals[2:]...)
+				}
+			},
+			want: []HealAction{rewrite(2, 0, 2, 3), rewrite(2, 1, 2, 3)},
+		},
+		{
+			name: "dangling version",
+			fail: func(drives []HealDrive) {
+				drives[1].Meta.AddDeleteMarker(5, day(5))
+			},
+			want: []HealAction{unrecoverable(5, 1)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drives := healDrives(t)
+			tc.fail(drives)
+			var verified []int
+			plan, err := PlanHeal(drives, HealOptions{VerifyShards: func(drive int, obj *ObjectMetaV2Object) error {
+				if obj.DataErasureIndex != drive+1 {
+					t.Errorf("drive %d verified with the copy of drive %d", drive, obj.DataErasureIndex-1)
+				}
+				verified = append(verified, drive)
+				if id, ok := tc.corrupt[drive]; ok && id == obj.VersionID {
+					return &BitrotError{PartNumber: 1}
+				}
+				return nil
+			}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(verified) == 0 {
+				t.Error("shards not verified")
+			}
+			if !reflect.DeepEqual(plan.Actions, tc.want) {
+				t.Errorf("actions\ngot  %v\nwant %v", plan.Actions, tc.want)
+			}
+			if !reflect.DeepEqual(plan.Offline, tc.offline) || plan.Drives != 4 || plan.Healthy() != (len(tc.want) == 0) {
+				t.Errorf("plan %+v", plan)
+			}
+		})
+	}
+}
+
+func TestPlanHealErrors(t *testing.T) {
+	if _, err := PlanHeal(nil, HealOptions{}); err != errNoHealDrives {
+		t.Errorf("no drives: want %v, got %v", errNoHealDrives, err)
+	}
+	drives := healDrives(t)
+	if _, err := PlanHeal(drives[:3], HealOptions{}); !errors.Is(err, errInvalidErasure) {
+		t.Errorf("drives not matching the distribution: want %v, got %v", errInvalidErasure, err)
+	}
+}
+
+func TestHealPlanWriteReport(t *testing.T) {
+	drives := healDrives(t)
+	drives[0].Offline = true
+	drives[2].Meta = nil
+	drives[3].Meta.ObjectJournals[0].Object.StatSize++
+	plan, err := PlanHeal(drives, HealOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := plan.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `ACTION             VERSION           DRIVE  SHARD  SOURCES
+rewrite-metadata   0000000000000002  2      -      1,3
+rewrite-metadata   0000000000000003  2      -      1,3
+rewrite-metadata   0000000000000004  2      -      1,3
+reconstruct-shard  0000000000000003  2      3      1,3
+unrecoverable      0000000000000001  -      -      1 of 2: 1
+3 metadata rewrites, 1 shard reconstructions, 1 unrecoverable versions; 4 versions on 4 drives, offline: 0
+`
+	if buf.String() != want {
+		t.Errorf("report\ngot\n%s\nwant\n%s", buf.String(), want)
+	}
+}
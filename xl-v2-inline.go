@@ -0,0 +1,129 @@
+package xlmeta
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// InlineDataThreshold is the largest object that is stored inline in the metadata.
+const InlineDataThreshold = 128 << 10
+
+var (
+	errInlineDataTooLarge = errors.New("object too large to be stored inline")
+	errDataInline         = errors.New("object data is stored inline")
+)
+
+// InlineData is object data stored inside the metadata journal.
+// UnmarshalMsg does not copy the data; it references the decoded buffer
+// until Copy is called.
+type InlineData struct {
+	data []byte
+}
+
+// Bytes returns the inline data.
+// After UnmarshalMsg it references the decoded buffer and must not be modified.
+func (z *InlineData) Bytes() []byte {
+	return z.data
+}
+
+// Copy returns a copy of the inline data that does not reference the decoded buffer.
+func (z *InlineData) Copy() []byte {
+	return append([]byte(nil), z.data...)
+}
+
+// Len returns the length of the inline data.
+func (z *InlineData) Len() int {
+	return len(z.data)
+}
+
+// SetInlineData stores data inside the metadata of z.
+// The object will have a single part and no DataDir.
+func (z *ObjectMetaV2Object) SetInlineData(data []byte) error {
+	if len(data) > InlineDataThreshold {
+		return errInlineDataTooLarge
+	}
+	z.Inline = &InlineData{data: data}
+	z.DataDir = 0
+	z.DataPartInfoNumbers = append(z.DataPartInfoNumbers[:0], 1)
+	z.DataPartInfoSizes = append(z.DataPartInfoSizes[:0], len(data))
+	z.DataPartInfoChecksums = nil
+	z.StatSize = len(data)
+	return nil
+}
+
+// IsInline returns whether the data of z is stored in the metadata.
+// Inline objects have no DataDir and no part files on disk.
+func (z *ObjectMetaV2Object) IsInline() bool {
+	return z.Inline != nil
+}
+
+// InlineData returns the inline data of z without copying it, or nil.
+func (z *ObjectMetaV2Object) InlineData() []byte {
+	if z.Inline == nil {
+		return nil
+	}
+	return z.Inline.Bytes()
+}
+
+// DecodeMsg implements msgp.Decodable
+// The data is read into a new buffer, since z may reference a buffer
+// decoded by UnmarshalMsg or passed to SetInlineData.
+func (z *InlineData) DecodeMsg(dc *msgp.Reader) (err error) {
+	z.data, err = dc.ReadBytes(nil)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z InlineData) EncodeMsg(en *msgp.Writer) (err error) {
+	err = en.WriteBytes(z.data)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z InlineData) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendBytes(o, z.data)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *InlineData) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	z.data, bts, err = msgp.ReadBytesZC(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z InlineData) Msgsize() (s int) {
+	s = msgp.BytesPrefixSize + len(z.data)
+	return
+}
+
+// MarshalJSON implements json.Marshaler
+// Empty data is encoded as "" rather than null, which would decode as no
+// inline data at all.
+func (z InlineData) MarshalJSON() ([]byte, error) {
+	if z.data == nil {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(z.data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (z *InlineData) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &z.data)
+}
@@ -0,0 +1,128 @@
+package xlmeta
+
+import (
+	"bytes"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
+)
+
+func getSampleInlineObjectMetaV2(size int, nversions int) ObjectMetaV2 {
+	xlmeta := getSampleObjectMetaV2(1, nversions)
+	for _, journal := range xlmeta.ObjectJournals {
+		if err := journal.Object.SetInlineData(bytes.Repeat([]byte("x"), size)); err != nil {
+			panic(err)
+		}
+	}
+	return xlmeta
+}
+
+func TestObjectMetaV2InlineData(t *testing.T) {
+	obj := newObjectMetaV2Object(4)
+	if err := obj.SetInlineData(make([]byte, InlineDataThreshold+1)); err != errInlineDataTooLarge {
+		t.Fatalf("want %v, got %v", errInlineDataTooLarge, err)
+	}
+
+	xlmeta := getSampleInlineObjectMetaV2(1000, 3)
+	xlmeta.ObjectJournals[2].Object.Inline.data[0] = 'y'
+	buf, err := xlmeta.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded ObjectMetaV2
+	if _, err = decoded.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	obj = decoded.ObjectJournals[2].Object
+	if !obj.IsInline() || obj.DataDir != 0 || obj.StatSize != 1000 {
+		t.Fatalf("unexpected inline object: %+v", obj)
+	}
+	data := obj.InlineData()
+	if len(data) != 1000 || data[0] != 'y' || data[1] != 'x' {
+		t.Fatalf("unexpected inline data %q", data[:2])
+	}
+	if _, err = obj.ReadLayout(0, 1); err != errDataInline {
+		t.Fatalf("want %v, got %v", errDataInline, err)
+	}
+
+	// Decoded inline data must reference buf and copies must not.
+	idx := bytes.Index(buf, []byte("yxxx"))
+	if &buf[idx] != &data[0] {
+		t.Fatal("UnmarshalMsg copied inline data")
+	}
+	if cp := obj.Inline.Copy(); &cp[0] == &data[0] || !bytes.Equal(cp, data) {
+		t.Fatal("Copy did not copy inline data")
+	}
+
+	journal, err := decoded.GetJournalEntryN(buf, -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(journal.Object.InlineData(), data) {
+		t.Fatal("GetJournalEntryN returned wrong inline data")
+	}
+
+	var streamed ObjectMetaV2
+	if err = streamed.DecodeMsg(msgp.NewReader(bytes.NewReader(buf))); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(streamed.ObjectJournals[2].Object.InlineData(), data) {
+		t.Fatal("DecodeMsg returned wrong inline data")
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonBuf, err := json.Marshal(xlmeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ObjectMetaV2
+	if err = json.Unmarshal(jsonBuf, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromJSON.ObjectJournals[2].Object.InlineData(), data) || fromJSON.ObjectJournals[0].Object.IsInline() != true {
+		t.Fatal("JSON round trip lost inline data")
+	}
+}
+
+func TestInlineDataDecodeAfterUnmarshal(t *testing.T) {
+	first := msgp.AppendBytes(nil, []byte("first"))
+	second := msgp.AppendBytes(nil, []byte("other"))
+	var z InlineData
+	if _, err := z.UnmarshalMsg(first); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte(nil), first...)
+	if err := z.DecodeMsg(msgp.NewReader(bytes.NewReader(second))); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, want) {
+		t.Errorf("DecodeMsg overwrote the buffer decoded by UnmarshalMsg: %q", first)
+	}
+	if string(z.Bytes()) != "other" {
+		t.Errorf("DecodeMsg read %q", z.Bytes())
+	}
+}
+
+func TestInlineDataEmptyRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{nil, {}} {
+		xlmeta := getSampleObjectMetaV2(1, 1)
+		if err := xlmeta.ObjectJournals[0].Object.SetInlineData(data); err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range Codecs() {
+			buf, err := c.Marshal(&xlmeta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded ObjectMetaV2
+			if err := c.Unmarshal(buf, &decoded); err != nil {
+				t.Fatalf("%s: %v", c.Name(), err)
+			}
+			if obj := decoded.ObjectJournals[0].Object; !obj.IsInline() || obj.Inline.Len() != 0 {
+				t.Errorf("%s: empty inline data of %v not kept: %s", c.Name(), data, buf)
+			}
+		}
+	}
+}
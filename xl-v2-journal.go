@@ -0,0 +1,123 @@
+package xlmeta
+
+import (
+	"errors"
+	"time"
+)
+
+var errVersionNotFound = errors.New("version not found")
+
+// IsVersion returns whether the entry is an object version.
+// In-progress multipart uploads are not versions.
+func (z *ObjectMetaV2JournalEntry) IsVersion() bool {
+	switch z.Type {
+	case Object:
+		return z.Object != nil
+	case Delete:
+		return z.DeleteMarker != nil
+	case Link:
+		return z.Link != nil
+	}
+	return false
+}
+
+// VersionID returns the version ID of the entry.
+func (z *ObjectMetaV2JournalEntry) VersionID() uint64 {
+	switch {
+	case z.Type == Object && z.Object != nil:
+		return z.Object.VersionID
+	case z.Type == Delete && z.DeleteMarker != nil:
+		return z.DeleteMarker.VersionID
+	case z.Type == Link && z.Link != nil:
+		return z.Link.VersionID
+	}
+	return 0
+}
+
+// ModTime returns the modification time of the entry in seconds since the epoch.
+// For multipart uploads this is the initiation time.
+func (z *ObjectMetaV2JournalEntry) ModTime() int64 {
+	switch {
+	case z.Type == Object && z.Object != nil:
+		return z.Object.StatModTime
+	case z.Type == Delete && z.DeleteMarker != nil:
+		return z.DeleteMarker.ModTime
+	case z.Type == Link && z.Link != nil:
+		return z.Link.StatModTime
+	case z.Type == Multipart && z.Multipart != nil:
+		return z.Multipart.Initiated
+	}
+	return 0
+}
+
+// versionIndexes returns the journal indexes of all versions, oldest first.
+// The last index is the latest version.
+func (z *ObjectMetaV2) versionIndexes() []int {
+	idxs := make([]int, 0, len(z.ObjectJournals))
+	for i := range z.ObjectJournals {
+		if z.ObjectJournals[i].IsVersion() {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// DeleteOptions controls the removal of versions.
+type DeleteOptions struct {
+	// BypassGovernance allows removing versions under governance retention.
+	BypassGovernance bool
+	// Now is the time retention is checked against. time.Now is used when zero.
+	Now time.Time
+}
+
+// FindVersion returns the journal index of versionID, or -1.
+func (z *ObjectMetaV2) FindVersion(versionID uint64) int {
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		if e.IsVersion() && e.VersionID() == versionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddDeleteMarker adds a delete marker, inserted in the journal by modTime.
+// Delete markers can be added on top of locked versions.
+func (z *ObjectMetaV2) AddDeleteMarker(versionID uint64, modTime time.Time) {
+	z.InsertVersion(ObjectMetaV2JournalEntry{
+		Type: Delete,
+		DeleteMarker: &ObjectMetaV2DeleteMarker{
+			VersionID: versionID,
+			ModTime:   modTime.Unix(),
+		},
+	})
+}
+
+// DeleteVersion permanently removes versionID from the journal.
+// Versions under retention or legal hold are refused with an *ObjectLockedError.
+// Delete markers are never locked.
+func (z *ObjectMetaV2) DeleteVersion(versionID uint64, opts DeleteOptions) error {
+	idx := z.FindVersion(versionID)
+	if idx < 0 {
+		return errVersionNotFound
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	e := &z.ObjectJournals[idx]
+	var obj *ObjectMetaV2Object
+	switch e.Type {
+	case Object:
+		obj = e.Object
+	case Link:
+		obj = (*ObjectMetaV2Object)(e.Link)
+	}
+	if obj != nil {
+		if err := obj.checkLocked(now, opts.BypassGovernance); err != nil {
+			return err
+		}
+	}
+	z.ObjectJournals = append(z.ObjectJournals[:idx], z.ObjectJournals[idx+1:]...)
+	return nil
+}
@@ -0,0 +1,264 @@
+package xlmeta
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var errInvalidLifecycleRule = errors.New("invalid lifecycle rule")
+
+// LifecycleConfig is an S3 bucket lifecycle configuration.
+type LifecycleConfig struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []LifecycleRule `xml:"Rule"`
+}
+
+// LifecycleRule is a single rule of a lifecycle configuration.
+type LifecycleRule struct {
+	ID                           string                        `xml:"ID,omitempty"`
+	Status                       string                        `xml:"Status"`
+	Prefix                       string                        `xml:"Prefix,omitempty"` // Deprecated, use Filter.
+	Filter                       LifecycleFilter               `xml:"Filter"`
+	Expiration                   *LifecycleExpiration          `xml:"Expiration,omitempty"`
+	Transitions                  []LifecycleTransition         `xml:"Transition,omitempty"`
+	NoncurrentVersionExpiration  *NoncurrentVersionExpiration  `xml:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions []NoncurrentVersionTransition `xml:"NoncurrentVersionTransition,omitempty"`
+}
+
+// LifecycleFilter selects the objects a rule applies to.
+type LifecycleFilter struct {
+	Prefix string `xml:"Prefix,omitempty"`
+}
+
+// LifecycleExpiration expires current versions.
+type LifecycleExpiration struct {
+	Days                      int        `xml:"Days,omitempty"`
+	Date                      *time.Time `xml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool       `xml:"ExpiredObjectDeleteMarker,omitempty"`
+}
+
+// LifecycleTransition moves current versions to another storage class.
+type LifecycleTransition struct {
+	Days         int    `xml:"Days"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// NoncurrentVersionExpiration removes noncurrent versions.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays          int `xml:"NoncurrentDays"`
+	NewerNoncurrentVersions int `xml:"NewerNoncurrentVersions,omitempty"` // Number of newest noncurrent versions to keep.
+}
+
+// NoncurrentVersionTransition moves noncurrent versions to another storage class.
+type NoncurrentVersionTransition struct {
+	NoncurrentDays int    `xml:"NoncurrentDays"`
+	StorageClass   string `xml:"StorageClass"`
+}
+
+// ParseLifecycleConfig reads and validates an XML lifecycle configuration.
+func ParseLifecycleConfig(r io.Reader) (*LifecycleConfig, error) {
+	var lc LifecycleConfig
+	if err := xml.NewDecoder(r).Decode(&lc); err != nil {
+		return nil, err
+	}
+	if err := lc.Validate(); err != nil {
+		return nil, err
+	}
+	return &lc, nil
+}
+
+// Validate checks that every rule of lc is well formed.
+func (lc *LifecycleConfig) Validate() error {
+	for _, rule := range lc.Rules {
+		invalid := func(reason string) error {
+			return fmt.Errorf("%w %q: %s", errInvalidLifecycleRule, rule.ID, reason)
+		}
+		if rule.Status != "Enabled" && rule.Status != "Disabled" {
+			return invalid("status must be Enabled or Disabled")
+		}
+		if rule.Expiration == nil && rule.NoncurrentVersionExpiration == nil &&
+			len(rule.Transitions) == 0 && len(rule.NoncurrentVersionTransitions) == 0 {
+			return invalid("no action")
+		}
+		if exp := rule.Expiration; exp != nil {
+			set := 0
+			if exp.Days != 0 {
+				set++
+			}
+			if exp.Date != nil {
+				set++
+			}
+			if exp.ExpiredObjectDeleteMarker {
+				set++
+			}
+			if set != 1 || exp.Days < 0 {
+				return invalid("expiration needs exactly one of Days, Date and ExpiredObjectDeleteMarker")
+			}
+		}
+		if exp := rule.NoncurrentVersionExpiration; exp != nil && (exp.NoncurrentDays <= 0 || exp.NewerNoncurrentVersions < 0) {
+			return invalid("noncurrent expiration days must be positive")
+		}
+		for _, tr := range rule.Transitions {
+			if tr.Days < 0 || tr.StorageClass == "" {
+				return invalid("transition needs days and a storage class")
+			}
+		}
+		for _, tr := range rule.NoncurrentVersionTransitions {
+			if tr.NoncurrentDays <= 0 || tr.StorageClass == "" {
+				return invalid("noncurrent transition needs days and a storage class")
+			}
+		}
+	}
+	return nil
+}
+
+// LifecycleAction is an action due on a version.
+type LifecycleAction uint8
+
+const (
+	// NoLifecycleAction means nothing is due.
+	NoLifecycleAction LifecycleAction = iota
+	// DeleteVersionAction removes the journal entry.
+	DeleteVersionAction
+	// AddDeleteMarkerAction expires the current version by adding a delete marker on top of it.
+	AddDeleteMarkerAction
+	// TransitionAction moves the version data to another storage class.
+	TransitionAction
+)
+
+func (a LifecycleAction) String() string {
+	switch a {
+	case NoLifecycleAction:
+		return "None"
+	case DeleteVersionAction:
+		return "DeleteVersion"
+	case AddDeleteMarkerAction:
+		return "AddDeleteMarker"
+	case TransitionAction:
+		return "Transition"
+	}
+	return fmt.Sprintf("LifecycleAction(%d)", uint8(a))
+}
+
+// LifecycleEvent is an action due on a journal entry.
+type LifecycleEvent struct {
+	Index        int // Journal index of the version.
+	VersionID    uint64
+	Action       LifecycleAction
+	RuleID       string
+	StorageClass string    // Target storage class of transitions.
+	Due          time.Time // Time the action became due.
+}
+
+// LifecycleEvaluator computes the lifecycle actions due on object versions.
+// Buckets are assumed to have versioning enabled.
+type LifecycleEvaluator struct {
+	Config LifecycleConfig
+	// Now returns the evaluation time. time.Now is used when nil.
+	Now func() time.Time
+}
+
+// expectedExpiryTime returns midnight UTC of the day after modTime plus days,
+// the time S3 considers a version expired.
+func expectedExpiryTime(modTime time.Time, days int) time.Time {
+	if days == 0 {
+		return modTime
+	}
+	t := modTime.UTC().Add(time.Duration(days+1) * 24 * time.Hour)
+	return t.Truncate(24 * time.Hour)
+}
+
+func (r *LifecycleRule) matches(object string) bool {
+	if r.Status != "Enabled" {
+		return false
+	}
+	return strings.HasPrefix(object, r.Prefix) && strings.HasPrefix(object, r.Filter.Prefix)
+}
+
+// Eval returns the actions due on the versions of object, in journal order.
+// At most one action is returned per version; deletions win over transitions
+// and earlier rules win over later ones. Locked versions are never deleted
+// and transitioned versions are not transitioned again.
+func (ev *LifecycleEvaluator) Eval(object string, z *ObjectMetaV2) []LifecycleEvent {
+	now := time.Now()
+	if ev.Now != nil {
+		now = ev.Now()
+	}
+	versions := z.versionIndexes()
+	if len(versions) == 0 {
+		return nil
+	}
+	events := make([]LifecycleEvent, len(versions))
+	set := func(i int, event LifecycleEvent) {
+		if event.Due.After(now) {
+			return
+		}
+		// Lifecycle never removes locked versions.
+		if e := &z.ObjectJournals[versions[i]]; event.Action == DeleteVersionAction && e.Type == Object &&
+			e.Object.checkLocked(now, false) != nil {
+			return
+		}
+		// Transitioned versions are not moved again.
+		if e := &z.ObjectJournals[versions[i]]; event.Action == TransitionAction && e.Type == Object &&
+			e.Object.IsTransitioned() {
+			return
+		}
+		cur := events[i].Action
+		if cur == NoLifecycleAction || (cur == TransitionAction && event.Action != TransitionAction) {
+			event.Index = versions[i]
+			event.VersionID = z.ObjectJournals[versions[i]].VersionID()
+			events[i] = event
+		}
+	}
+
+	latest := len(versions) - 1
+	for _, rule := range ev.Config.Rules {
+		if !rule.matches(object) {
+			continue
+		}
+		current := &z.ObjectJournals[versions[latest]]
+		modTime := time.Unix(current.ModTime(), 0)
+		if current.Type == Delete {
+			if exp := rule.Expiration; exp != nil && exp.ExpiredObjectDeleteMarker && len(versions) == 1 {
+				set(latest, LifecycleEvent{Action: DeleteVersionAction, RuleID: rule.ID, Due: modTime})
+			}
+		} else {
+			if exp := rule.Expiration; exp != nil && exp.Days > 0 {
+				set(latest, LifecycleEvent{Action: AddDeleteMarkerAction, RuleID: rule.ID, Due: expectedExpiryTime(modTime, exp.Days)})
+			}
+			if exp := rule.Expiration; exp != nil && exp.Date != nil {
+				set(latest, LifecycleEvent{Action: AddDeleteMarkerAction, RuleID: rule.ID, Due: *exp.Date})
+			}
+			for _, tr := range rule.Transitions {
+				set(latest, LifecycleEvent{Action: TransitionAction, RuleID: rule.ID, StorageClass: tr.StorageClass, Due: expectedExpiryTime(modTime, tr.Days)})
+			}
+		}
+
+		// A version becomes noncurrent when its successor is written.
+		// newer counts the noncurrent versions that are newer than i.
+		for i, newer := latest-1, 0; i >= 0; i, newer = i-1, newer+1 {
+			successor := time.Unix(z.ObjectJournals[versions[i+1]].ModTime(), 0)
+			if exp := rule.NoncurrentVersionExpiration; exp != nil && newer >= exp.NewerNoncurrentVersions {
+				set(i, LifecycleEvent{Action: DeleteVersionAction, RuleID: rule.ID, Due: expectedExpiryTime(successor, exp.NoncurrentDays)})
+			}
+			if z.ObjectJournals[versions[i]].Type == Delete {
+				continue
+			}
+			for _, tr := range rule.NoncurrentVersionTransitions {
+				set(i, LifecycleEvent{Action: TransitionAction, RuleID: rule.ID, StorageClass: tr.StorageClass, Due: expectedExpiryTime(successor, tr.NoncurrentDays)})
+			}
+		}
+	}
+
+	due := events[:0]
+	for _, event := range events {
+		if event.Action != NoLifecycleAction {
+			due = append(due, event)
+		}
+	}
+	return due
+}
@@ -0,0 +1,214 @@
+package xlmeta
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var lifecycleEpoch = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return lifecycleEpoch.Add(time.Duration(n) * 24 * time.Hour)
+}
+
+// lifecycleJournal returns a journal with an entry per mtime; negative days are delete markers.
+func lifecycleJournal(days ...int) *ObjectMetaV2 {
+	var z ObjectMetaV2
+	for i, d := range days {
+		if d < 0 {
+			z.ObjectJournals = append(z.ObjectJournals, ObjectMetaV2JournalEntry{
+				Type:         Delete,
+				DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: uint64(i + 1), ModTime: day(-d).Unix()},
+			})
+			continue
+		}
+		obj := newObjectMetaV2Object(1)
+		obj.VersionID = uint64(i + 1)
+		obj.StatModTime = day(d).Unix()
+		z.ObjectJournals = append(z.ObjectJournals, ObjectMetaV2JournalEntry{Type: Object, Object: obj})
+	}
+	return &z
+}
+
+func parseTestLifecycle(t *testing.T, rules string) LifecycleConfig {
+	t.Helper()
+	lc, err := ParseLifecycleConfig(strings.NewReader("<LifecycleConfiguration>" + rules + "</LifecycleConfiguration>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *lc
+}
+
+type lifecycleResult struct {
+	VersionID    uint64
+	Action       LifecycleAction
+	StorageClass string
+}
+
+func TestLifecycleEvaluator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rules   string
+		object  string
+		journal *ObjectMetaV2
+		now     time.Time
+		want    []lifecycleResult
+	}{
+		{
+			name:    "current expiration not yet due",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>`,
+			journal: lifecycleJournal(0),
+			// Expiry is rounded up to the next midnight UTC.
+			now: time.Date(2020, 1, 2, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:    "current expiration adds delete marker",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>`,
+			journal: lifecycleJournal(0, 5),
+			now:     day(7),
+			want:    []lifecycleResult{{2, AddDeleteMarkerAction, ""}},
+		},
+		{
+			name:    "current expiration by date",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><Date>2020-02-01T00:00:00Z</Date></Expiration></Rule>`,
+			journal: lifecycleJournal(0),
+			now:     day(31),
+			want:    []lifecycleResult{{1, AddDeleteMarkerAction, ""}},
+		},
+		{
+			name:    "disabled rule",
+			rules:   `<Rule><Status>Disabled</Status><Expiration><Days>1</Days></Expiration></Rule>`,
+			journal: lifecycleJournal(0),
+			now:     day(10),
+		},
+		{
+			name:    "prefix filter",
+			rules:   `<Rule><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Expiration><Days>1</Days></Expiration></Rule>`,
+			object:  "data/object",
+			journal: lifecycleJournal(0),
+			now:     day(10),
+		},
+		{
+			name:    "noncurrent expiration counts from successor",
+			rules:   `<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>5</NoncurrentDays></NoncurrentVersionExpiration></Rule>`,
+			journal: lifecycleJournal(0, 10, 20),
+			now:     day(22),
+			want:    []lifecycleResult{{1, DeleteVersionAction, ""}},
+		},
+		{
+			name:    "newer noncurrent versions are retained",
+			rules:   `<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays><NewerNoncurrentVersions>2</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule>`,
+			journal: lifecycleJournal(0, 1, 2, 3, 4),
+			now:     day(30),
+			want:    []lifecycleResult{{1, DeleteVersionAction, ""}, {2, DeleteVersionAction, ""}},
+		},
+		{
+			name:    "noncurrent delete markers expire",
+			rules:   `<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays></NoncurrentVersionExpiration></Rule>`,
+			journal: lifecycleJournal(0, -1, 2),
+			now:     day(30),
+			want:    []lifecycleResult{{1, DeleteVersionAction, ""}, {2, DeleteVersionAction, ""}},
+		},
+		{
+			name:    "expired delete marker is removed",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`,
+			journal: lifecycleJournal(-3),
+			now:     day(3),
+			want:    []lifecycleResult{{1, DeleteVersionAction, ""}},
+		},
+		{
+			name:    "delete marker with noncurrent versions is kept",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`,
+			journal: lifecycleJournal(0, -3),
+			now:     day(30),
+		},
+		{
+			name:    "current expiration ignores delete markers",
+			rules:   `<Rule><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>`,
+			journal: lifecycleJournal(0, -1),
+			now:     day(30),
+		},
+		{
+			name: "transitions",
+			rules: `<Rule><ID>tier</ID><Status>Enabled</Status>
+				<Transition><Days>10</Days><StorageClass>WARM</StorageClass></Transition>
+				<NoncurrentVersionTransition><NoncurrentDays>1</NoncurrentDays><StorageClass>COLD</StorageClass></NoncurrentVersionTransition>
+			</Rule>`,
+			journal: lifecycleJournal(0, -1, 2, 3),
+			now:     day(14),
+			want:    []lifecycleResult{{1, TransitionAction, "COLD"}, {3, TransitionAction, "COLD"}, {4, TransitionAction, "WARM"}},
+		},
+		{
+			name: "transitioned versions are not transitioned again",
+			rules: `<Rule><Status>Enabled</Status>
+				<NoncurrentVersionTransition><NoncurrentDays>1</NoncurrentDays><StorageClass>COLD</StorageClass></NoncurrentVersionTransition>
+			</Rule>`,
+			journal: func() *ObjectMetaV2 {
+				z := lifecycleJournal(0, 1, 2)
+				if err := z.TransitionVersion(1, "COLD", "remote", ""); err != nil {
+					t.Fatal(err)
+				}
+				return z
+			}(),
+			now:  day(14),
+			want: []lifecycleResult{{2, TransitionAction, "COLD"}},
+		},
+		{
+			name: "deletion wins over transition",
+			rules: `<Rule><Status>Enabled</Status><Transition><Days>1</Days><StorageClass>WARM</StorageClass></Transition></Rule>
+				<Rule><Status>Enabled</Status><Expiration><Days>2</Days></Expiration></Rule>`,
+			journal: lifecycleJournal(0),
+			now:     day(5),
+			want:    []lifecycleResult{{1, AddDeleteMarkerAction, ""}},
+		},
+		{
+			name:  "multipart uploads are not versions",
+			rules: `<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays></NoncurrentVersionExpiration></Rule>`,
+			journal: func() *ObjectMetaV2 {
+				z := lifecycleJournal(0)
+				if err := z.NewMultipartUpload("upload", ObjectMetaV2Object{}, day(1)); err != nil {
+					t.Fatal(err)
+				}
+				return z
+			}(),
+			now: day(30),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := tc.now
+			ev := LifecycleEvaluator{
+				Config: parseTestLifecycle(t, tc.rules),
+				Now:    func() time.Time { return now },
+			}
+			var got []lifecycleResult
+			for _, event := range ev.Eval(tc.object, tc.journal) {
+				if tc.journal.ObjectJournals[event.Index].VersionID() != event.VersionID || event.Due.After(now) {
+					t.Fatalf("inconsistent event %+v", event)
+				}
+				got = append(got, lifecycleResult{event.VersionID, event.Action, event.StorageClass})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseLifecycleConfigInvalid(t *testing.T) {
+	for _, rules := range []string{
+		`<Rule><Status>Maybe</Status><Expiration><Days>1</Days></Expiration></Rule>`,
+		`<Rule><Status>Enabled</Status></Rule>`,
+		`<Rule><Status>Enabled</Status><Expiration><Days>1</Days><ExpiredObjectDeleteMarker>true</ExpiredObjectDeleteMarker></Expiration></Rule>`,
+		`<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>0</NoncurrentDays></NoncurrentVersionExpiration></Rule>`,
+		`<Rule><Status>Enabled</Status><Transition><Days>1</Days></Transition></Rule>`,
+	} {
+		_, err := ParseLifecycleConfig(strings.NewReader("<LifecycleConfiguration>" + rules + "</LifecycleConfiguration>"))
+		if !errors.Is(err, errInvalidLifecycleRule) {
+			t.Errorf("%s: want %v, got %v", rules, errInvalidLifecycleRule, err)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package xlmeta
+
+import (
+	"fmt"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// DecodeOptions limits the resources decoding untrusted metadata may use.
+// Zero values mean no limit.
+type DecodeOptions struct {
+	MaxVersions  int // Journal entries.
+	MaxParts     int // Parts of an object or multipart upload.
+	MaxMetaKeys  int // Keys of each MetaSys and MetaUser map.
+	MaxMetaBytes int // Encoded size of each MetaSys and MetaUser map.
+	// MaxAlloc is the memory the decoded value may need, estimated from
+	// the declared lengths of arrays, maps, strings and binaries.
+	MaxAlloc int64
+}
+
+// UntrustedDecodeOptions are limits suited to metadata from untrusted sources,
+// for UnmarshalMsgWithOptions, DecodeMsgWithOptions and GetJournalEntryNWithOptions.
+// UnmarshalMsg, DecodeMsg, UnmarshalMsgReuse and GetJournalEntryN apply no limits.
+// The allocation budget is not limited; declared lengths are always checked
+// against the input size, which bounds allocations relative to it.
+var UntrustedDecodeOptions = DecodeOptions{
+	MaxVersions:  10000,
+	MaxParts:     maxPartNumber,
+	MaxMetaKeys:  1000,
+	MaxMetaBytes: 1 << 20,
+}
+
+// Estimated memory needed by decoded array elements and map entries,
+// in addition to their encoded size.
+const (
+	msgArrayElemAlloc = 8
+	msgMapEntryAlloc  = 32
+)
+
+// DecodeLimitError is returned when metadata exceeds a limit of DecodeOptions.
+// It is returned as is, not wrapped with msgp context.
+type DecodeLimitError struct {
+	Limit string // Name of the DecodeOptions field.
+	Max   int64
+	Got   int64
+}
+
+func (e *DecodeLimitError) Error() string {
+	return fmt.Sprintf("metadata exceeds %s: %d > %d", e.Limit, e.Got, e.Max)
+}
+
+// exceeds returns a *DecodeLimitError if max is set and got is above it.
+func exceeds(limit string, max int64, got int64) error {
+	if max > 0 && got > max {
+		return &DecodeLimitError{Limit: limit, Max: max, Got: got}
+	}
+	return nil
+}
+
+// wrapDecodeError adds msgp context to err, except to limit errors.
+func wrapDecodeError(err error, ctx ...interface{}) error {
+	if _, ok := err.(*DecodeLimitError); ok {
+		return err
+	}
+	return msgp.WrapError(err, ctx...)
+}
+
+// msgNode is the type of a map or array of encoded metadata,
+// as far as the limits of DecodeOptions are concerned.
+type msgNode uint8
+
+const (
+	msgOther     msgNode = iota // Not limited, nor anything inside it.
+	msgMeta                     // ObjectMetaV2
+	msgJournal                  // ObjectMetaV2.ObjectJournals
+	msgEntry                    // ObjectMetaV2JournalEntry
+	msgObject                   // ObjectMetaV2Object, also of links and multipart uploads.
+	msgMultipart                // ObjectMetaV2Multipart
+	msgSSE                      // ObjectMetaV2SSE
+)
+
+// msgLimit is the limit of DecodeOptions that applies to the length of a map or array.
+type msgLimit uint8
+
+const (
+	limitNone msgLimit = iota
+	limitVersions
+	limitParts
+	limitMeta // Keys and encoded size.
+)
+
+// msgField returns the type of the field key of a parent map
+// and the limit that applies to it.
+func msgField(parent msgNode, key string) (msgNode, msgLimit) {
+	switch parent {
+	case msgMeta:
+		if key == "ojs" {
+			return msgJournal, limitVersions
+		}
+	case msgEntry:
+		switch key {
+		case "object", "link":
+			return msgObject, limitNone
+		case "mpart":
+			return msgMultipart, limitNone
+		}
+	case msgMultipart:
+		switch key {
+		case "parts":
+			return msgOther, limitParts
+		case "object":
+			return msgObject, limitNone
+		}
+	case msgObject:
+		switch key {
+		case "pnum", "psz", "pcsum":
+			return msgOther, limitParts
+		case "msys", "muser":
+			return msgOther, limitMeta
+		case "sse":
+			return msgSSE, limitNone
+		}
+	case msgSSE:
+		if key == "psz" {
+			return msgOther, limitParts
+		}
+	}
+	return msgOther, limitNone
+}
+
+// msgLimits applies DecodeOptions while checkMsgLengths walks an object.
+// Limits of versions, parts and metadata apply to the fields of their
+// struct only, found by following map keys from the root with msgField.
+type msgLimits struct {
+	opts  *DecodeOptions
+	root  msgNode
+	alloc int64
+	// Per nesting level: whether it is a map, the type of the map or array,
+	// and 1 + the offset of the metadata map it is, or 0.
+	isMap     [maxMsgDepth + 1]bool
+	node      [maxMsgDepth + 1]msgNode
+	metaStart [maxMsgDepth + 1]int
+}
+
+// object applies the limits to the object with header hdr starting at offset start.
+// key is the encoded map key of the object if it is a map value.
+func (l *msgLimits) object(hdr, key []byte, depth, start int, children uint64) error {
+	lead := hdr[0]
+	isMap := lead&0xf0 == 0x80 || lead == 0xde || lead == 0xdf
+	isArray := lead&0xf0 == 0x90 || lead == 0xdc || lead == 0xdd
+	if l.opts.MaxAlloc > 0 && children > 0 {
+		if isMap {
+			l.alloc += int64(children/2) * msgMapEntryAlloc
+		} else {
+			l.alloc += int64(children) * msgArrayElemAlloc
+		}
+		if err := exceeds("MaxAlloc", l.opts.MaxAlloc, l.alloc+int64(start+len(hdr))); err != nil {
+			return err
+		}
+	}
+	node, limit := msgOther, limitNone
+	switch {
+	case !isMap && !isArray:
+	case depth == 0:
+		node = l.root
+	case key != nil:
+		field, _, err := msgp.ReadMapKeyZC(key)
+		if err != nil {
+			// The decoder rejects the key.
+			return nil
+		}
+		node, limit = msgField(l.node[depth], msgp.UnsafeString(field))
+	case l.node[depth] == msgJournal:
+		node = msgEntry
+	}
+	meta := false
+	var err error
+	switch limit {
+	case limitVersions:
+		err = exceeds("MaxVersions", int64(l.opts.MaxVersions), int64(children))
+	case limitParts:
+		err = exceeds("MaxParts", int64(l.opts.MaxParts), int64(children))
+	case limitMeta:
+		if isMap {
+			err = exceeds("MaxMetaKeys", int64(l.opts.MaxMetaKeys), int64(children/2))
+			meta = l.opts.MaxMetaBytes > 0
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if children > 0 && depth < maxMsgDepth {
+		l.isMap[depth+1] = isMap
+		l.node[depth+1] = node
+		l.metaStart[depth+1] = 0
+		if meta {
+			l.metaStart[depth+1] = start + 1
+		}
+	}
+	return nil
+}
+
+// pop applies the limits to the map or array at depth, which ends at offset end.
+func (l *msgLimits) pop(depth, end int) error {
+	if start := l.metaStart[depth]; start > 0 {
+		return exceeds("MaxMetaBytes", int64(l.opts.MaxMetaBytes), int64(end-(start-1)))
+	}
+	return nil
+}
+
+// end applies the limits to the object of size bytes.
+func (l *msgLimits) end(size int) error {
+	return exceeds("MaxAlloc", l.opts.MaxAlloc, l.alloc+int64(size))
+}
+
+// UnmarshalMsgWithOptions is UnmarshalMsg with the limits of opts.
+func (z *ObjectMetaV2) UnmarshalMsgWithOptions(bts []byte, opts DecodeOptions) (o []byte, err error) {
+	if _, err = checkMsgLengths(bts, &opts); err != nil {
+		err = wrapDecodeError(err)
+		return
+	}
+	return z.unmarshalMsg(bts)
+}
+
+// DecodeMsgWithOptions is DecodeMsg with the limits of opts.
+// MaxAlloc also limits the size of the object read from dc.
+func (z *ObjectMetaV2) DecodeMsgWithOptions(dc *msgp.Reader, opts DecodeOptions) (err error) {
+	bts, err := readMsg(dc, nil, opts.MaxAlloc)
+	if err != nil {
+		err = wrapDecodeError(err)
+		return
+	}
+	_, err = z.UnmarshalMsgWithOptions(bts, opts)
+	return
+}
@@ -0,0 +1,243 @@
+package xlmeta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// limitedMetadata returns encoded metadata with versions versions of parts parts,
+// each having keys MetaSys and MetaUser values of valueSize bytes.
+func limitedMetadata(t *testing.T, versions, parts, keys, valueSize int) []byte {
+	z := getSampleObjectMetaV2(parts, versions)
+	for i := range z.ObjectJournals {
+		obj := z.ObjectJournals[i].Object
+		obj.MetaSys = make(map[string][]byte, keys)
+		obj.MetaUser = make(map[string][]string, keys)
+		for k := 0; k < keys; k++ {
+			key := fmt.Sprintf("key-%d", k)
+			obj.MetaSys[key] = bytes.Repeat([]byte{'s'}, valueSize)
+			obj.MetaUser[key] = []string{strings.Repeat("u", valueSize)}
+		}
+	}
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+// wantLimit fails t unless err is a *DecodeLimitError for limit.
+func wantLimit(t *testing.T, what string, err error, limit string) {
+	t.Helper()
+	var le *DecodeLimitError
+	if limit == "" {
+		if err != nil {
+			t.Errorf("%s: %v", what, err)
+		}
+		return
+	}
+	if !errors.As(err, &le) || le.Limit != limit {
+		t.Errorf("%s: want %s exceeded, got %v", what, limit, err)
+	}
+}
+
+func TestObjectMetaV2DecodeLimits(t *testing.T) {
+	buf := limitedMetadata(t, 10, 20, 5, 100)
+	testCases := []struct {
+		name  string
+		opts  DecodeOptions
+		limit string
+	}{
+		{"none", DecodeOptions{}, ""},
+		{"untrusted", UntrustedDecodeOptions, ""},
+		{"versions", DecodeOptions{MaxVersions: 9}, "MaxVersions"},
+		{"versions at limit", DecodeOptions{MaxVersions: 10}, ""},
+		{"parts", DecodeOptions{MaxParts: 19}, "MaxParts"},
+		{"parts at limit", DecodeOptions{MaxParts: 20}, ""},
+		{"metadata keys", DecodeOptions{MaxMetaKeys: 4}, "MaxMetaKeys"},
+		{"metadata keys at limit", DecodeOptions{MaxMetaKeys: 5}, ""},
+		{"metadata bytes", DecodeOptions{MaxMetaBytes: 500}, "MaxMetaBytes"},
+		{"metadata bytes above size", DecodeOptions{MaxMetaBytes: 600}, ""},
+		{"allocations", DecodeOptions{MaxAlloc: int64(len(buf))}, "MaxAlloc"},
+		{"allocations above size", DecodeOptions{MaxAlloc: 4 * int64(len(buf))}, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var z ObjectMetaV2
+			_, err := z.UnmarshalMsgWithOptions(buf, tc.opts)
+			wantLimit(t, "UnmarshalMsgWithOptions", err, tc.limit)
+
+			err = z.DecodeMsgWithOptions(msgp.NewReader(bytes.NewReader(buf)), tc.opts)
+			wantLimit(t, "DecodeMsgWithOptions", err, tc.limit)
+
+			// A single entry fits the allocation budget of the whole object.
+			limit := tc.limit
+			if limit == "MaxAlloc" {
+				limit = ""
+			}
+			_, err = z.GetJournalEntryNWithOptions(buf, -1, nil, tc.opts)
+			wantLimit(t, "GetJournalEntryNWithOptions", err, limit)
+		})
+	}
+}
+
+func TestObjectMetaV2DecodeLimitsUntrusted(t *testing.T) {
+	testCases := []struct {
+		name  string
+		buf   []byte
+		limit string
+	}{
+		{"versions", limitedMetadata(t, UntrustedDecodeOptions.MaxVersions+1, 1, 0, 0), "MaxVersions"},
+		{"parts", limitedMetadata(t, 1, UntrustedDecodeOptions.MaxParts+1, 0, 0), "MaxParts"},
+		{"metadata keys", limitedMetadata(t, 1, 1, UntrustedDecodeOptions.MaxMetaKeys+1, 0), "MaxMetaKeys"},
+		{"metadata bytes", limitedMetadata(t, 1, 1, 2, UntrustedDecodeOptions.MaxMetaBytes/2), "MaxMetaBytes"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Limits apply only when requested.
+			var z ObjectMetaV2
+			_, err := z.UnmarshalMsg(tc.buf)
+			wantLimit(t, "UnmarshalMsg", err, "")
+
+			err = z.DecodeMsg(msgp.NewReader(bytes.NewReader(tc.buf)))
+			wantLimit(t, "DecodeMsg", err, "")
+
+			_, err = GetObjectMetaV2().UnmarshalMsgReuse(tc.buf)
+			wantLimit(t, "UnmarshalMsgReuse", err, "")
+
+			_, err = z.GetJournalEntryN(tc.buf, 0, nil)
+			wantLimit(t, "GetJournalEntryN", err, "")
+
+			if _, err = NewJournalIndex(tc.buf); err != nil {
+				t.Errorf("NewJournalIndex: %v", err)
+			}
+
+			_, err = z.UnmarshalMsgWithOptions(tc.buf, UntrustedDecodeOptions)
+			wantLimit(t, "UnmarshalMsgWithOptions", err, tc.limit)
+
+			err = z.DecodeMsgWithOptions(msgp.NewReader(bytes.NewReader(tc.buf)), UntrustedDecodeOptions)
+			wantLimit(t, "DecodeMsgWithOptions", err, tc.limit)
+
+			_, err = z.GetJournalEntryNWithOptions(tc.buf, 0, nil, UntrustedDecodeOptions)
+			wantLimit(t, "GetJournalEntryNWithOptions", err, tc.limit)
+		})
+	}
+}
+
+func TestObjectMetaV2DecodeLimitsCrafted(t *testing.T) {
+	// Keys encoded as str8 instead of fixstr are accepted by the decoder.
+	str8 := func(b []byte, s string) []byte {
+		return append(append(b, 0xd9, byte(len(s))), s...)
+	}
+	journal := msgp.AppendMapHeader(nil, 1)
+	journal = str8(journal, "ojs")
+	journal = msgp.AppendArrayHeader(journal, 3)
+	for i := 0; i < 3; i++ {
+		journal = msgp.AppendMapHeader(journal, 0)
+	}
+
+	// Keys are found after an empty metadata map.
+	parts := msgp.AppendMapHeader(nil, 1)
+	parts = msgp.AppendString(parts, "ojs")
+	parts = msgp.AppendArrayHeader(parts, 1)
+	parts = msgp.AppendMapHeader(parts, 1)
+	parts = msgp.AppendString(parts, "object")
+	parts = msgp.AppendMapHeader(parts, 2)
+	parts = msgp.AppendString(parts, "muser")
+	parts = msgp.AppendMapHeader(parts, 0)
+	parts = msgp.AppendString(parts, "pnum")
+	parts = msgp.AppendArrayHeader(parts, 3)
+	for i := 1; i <= 3; i++ {
+		parts = msgp.AppendInt(parts, 1)
+	}
+
+	// Values named like limited keys are not limited.
+	values := msgp.AppendMapHeader(nil, 1)
+	values = msgp.AppendString(values, "ojs")
+	values = msgp.AppendArrayHeader(values, 3)
+	for i := 0; i < 3; i++ {
+		values = msgp.AppendString(values, "ojs")
+	}
+
+	testCases := []struct {
+		name  string
+		buf   []byte
+		opts  DecodeOptions
+		limit string
+	}{
+		{"str8 key", journal, DecodeOptions{MaxVersions: 2}, "MaxVersions"},
+		{"parts after metadata", parts, DecodeOptions{MaxParts: 2}, "MaxParts"},
+		{"values", values, DecodeOptions{MaxVersions: 3}, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := checkMsgLengths(tc.buf, &tc.opts)
+			wantLimit(t, "checkMsgLengths", err, tc.limit)
+		})
+	}
+}
+
+func TestObjectMetaV2DecodeLimitsPaths(t *testing.T) {
+	opts := DecodeOptions{MaxVersions: 4, MaxParts: 4}
+	many := []string{"1", "2", "3", "4", "5"}
+	testCases := []struct {
+		name   string
+		modify func(z *ObjectMetaV2)
+		limit  string
+	}{
+		{"user metadata named like limited fields", func(z *ObjectMetaV2) {
+			obj := z.ObjectJournals[0].Object
+			obj.MetaUser = map[string][]string{}
+			for _, key := range []string{"ojs", "parts", "pnum", "psz", "pcsum", "object", "mpart", "sse"} {
+				obj.MetaUser[key] = many
+			}
+		}, ""},
+		{"multipart parts", func(z *ObjectMetaV2) {
+			mp := &ObjectMetaV2Multipart{UploadID: "upload", Initiated: 1}
+			for i := 1; i <= 5; i++ {
+				mp.Parts = append(mp.Parts, ObjectMetaV2MultipartPart{Number: i, Size: i})
+			}
+			z.ObjectJournals = append(z.ObjectJournals, ObjectMetaV2JournalEntry{Type: Multipart, Multipart: mp})
+		}, "MaxParts"},
+		{"encrypted part sizes", func(z *ObjectMetaV2) {
+			z.ObjectJournals[0].Object.SSE = &ObjectMetaV2SSE{PartSizes: DeltaEncodedInt{1, 2, 3, 4, 5}}
+		}, "MaxParts"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var z ObjectMetaV2
+			if _, err := z.UnmarshalMsg(limitedMetadata(t, 1, 1, 0, 0)); err != nil {
+				t.Fatal(err)
+			}
+			tc.modify(&z)
+			buf, err := z.MarshalMsg(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = z.UnmarshalMsgWithOptions(buf, opts)
+			wantLimit(t, "UnmarshalMsgWithOptions", err, tc.limit)
+
+			// Single journal entries are limited the same way.
+			entry, err := z.ObjectJournals[len(z.ObjectJournals)-1].MarshalMsg(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = checkEntryLengths(entry, &opts)
+			wantLimit(t, "checkEntryLengths", err, tc.limit)
+		})
+	}
+}
+
+func TestReadMsgLimit(t *testing.T) {
+	buf := limitedMetadata(t, 2, 2, 0, 0)
+	if _, err := readMsg(msgp.NewReader(bytes.NewReader(buf)), nil, int64(len(buf))); err != nil {
+		t.Fatal(err)
+	}
+	_, err := readMsg(msgp.NewReader(bytes.NewReader(buf)), nil, int64(len(buf)-1))
+	wantLimit(t, "readMsg", err, "MaxAlloc")
+}
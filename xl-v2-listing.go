@@ -0,0 +1,253 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetaFile is the name of the metadata file in each object directory.
+const MetaFile = "xl.meta"
+
+// maxListKeys is the largest page of a listing, as in S3.
+const maxListKeys = 1000
+
+var (
+	errInvalidVersionID  = errors.New("invalid version ID")
+	errVersionIDMarker   = errors.New("version ID marker without key marker")
+	errDuplicateListName = errors.New("duplicate object name")
+)
+
+// FormatVersionID returns the S3 form of versionID: "null" for the null
+// version, the zero ID, and 16 hexadecimal digits otherwise.
+func FormatVersionID(versionID uint64) string {
+	if versionID == 0 {
+		return "null"
+	}
+	return fmt.Sprintf("%016x", versionID)
+}
+
+// ParseVersionID parses a version ID formatted by FormatVersionID.
+func ParseVersionID(s string) (uint64, error) {
+	if s == "null" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || len(s) != 16 || id == 0 {
+		return 0, fmt.Errorf("%w: %q", errInvalidVersionID, s)
+	}
+	return id, nil
+}
+
+// NamedMetadata is the metadata of the object Name.
+type NamedMetadata struct {
+	Name string
+	Meta *ObjectMetaV2
+}
+
+// ListVersionsOptions selects a page of a version listing,
+// with the parameters of S3 ListObjectVersions.
+type ListVersionsOptions struct {
+	Prefix    string // Only list object names starting with Prefix.
+	Delimiter string // Roll up names containing Delimiter after Prefix into common prefixes.
+	// KeyMarker and VersionIDMarker continue a truncated listing after the
+	// version VersionIDMarker of KeyMarker. Without VersionIDMarker, the
+	// listing continues after all versions of KeyMarker.
+	KeyMarker       string
+	VersionIDMarker string
+	MaxKeys         int // Versions, delete markers and common prefixes per page, up to 1000 (the default).
+}
+
+// ObjectVersion is a version or delete marker in a listing.
+type ObjectVersion struct {
+	Key          string
+	VersionID    string // As formatted by FormatVersionID.
+	IsLatest     bool
+	DeleteMarker bool
+	LastModified time.Time
+	Size         int64  // Zero for delete markers.
+	ETag         string // Empty for delete markers.
+}
+
+// ListVersionsResult is a page of a version listing.
+type ListVersionsResult struct {
+	// Versions holds versions and delete markers by name, newest first for each name.
+	Versions       []ObjectVersion
+	CommonPrefixes []string
+	IsTruncated    bool
+	// NextKeyMarker and NextVersionIDMarker continue a truncated listing.
+	// NextVersionIDMarker is empty when the page ends with a common prefix.
+	NextKeyMarker       string
+	NextVersionIDMarker string
+}
+
+// ListObjectVersions lists the versions of objects like S3 ListObjectVersions.
+func ListObjectVersions(objects []NamedMetadata, opts ListVersionsOptions) (*ListVersionsResult, error) {
+	sorted := append([]NamedMetadata(nil), objects...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	names := make([]string, len(sorted))
+	for i, o := range sorted {
+		if i > 0 && o.Name == names[i-1] {
+			return nil, fmt.Errorf("%w: %q", errDuplicateListName, o.Name)
+		}
+		names[i] = o.Name
+	}
+	return listVersions(names, func(i int) (*ObjectMetaV2, error) { return sorted[i].Meta, nil }, opts)
+}
+
+// ListObjectVersionsDir lists the versions of the objects stored under dir,
+// each in a directory named like the object holding its MetaFile.
+// Only the metadata of the objects listed is read.
+func ListObjectVersionsDir(dir string, opts ListVersionsOptions) (*ListVersionsResult, error) {
+	var names []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != MetaFile {
+			return nil
+		}
+		name, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil || name == "." {
+			return err
+		}
+		names = append(names, filepath.ToSlash(name))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return listVersions(names, func(i int) (*ObjectMetaV2, error) {
+		path := filepath.Join(dir, filepath.FromSlash(names[i]), MetaFile)
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var z ObjectMetaV2
+		if _, err := z.UnmarshalMsg(buf); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return &z, nil
+	}, opts)
+}
+
+// listVersions lists the versions of the objects of sorted names,
+// loading the metadata of name i with load.
+func listVersions(names []string, load func(i int) (*ObjectMetaV2, error), opts ListVersionsOptions) (*ListVersionsResult, error) {
+	maxKeys := opts.MaxKeys
+	if maxKeys <= 0 || maxKeys > maxListKeys {
+		maxKeys = maxListKeys
+	}
+	var markerID uint64
+	if opts.VersionIDMarker != "" {
+		if opts.KeyMarker == "" {
+			return nil, errVersionIDMarker
+		}
+		var err error
+		if markerID, err = ParseVersionID(opts.VersionIDMarker); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ListVersionsResult{}
+	count := 0
+	// full reports whether the page is full, truncating it.
+	full := func() bool {
+		if count < maxKeys {
+			count++
+			return false
+		}
+		res.IsTruncated = true
+		return true
+	}
+	lastPrefix := ""
+	for i := sort.SearchStrings(names, opts.KeyMarker); i < len(names); i++ {
+		name := names[i]
+		if !strings.HasPrefix(name, opts.Prefix) {
+			if name > opts.Prefix {
+				break
+			}
+			continue
+		}
+		if opts.Delimiter != "" {
+			if n := strings.Index(name[len(opts.Prefix):], opts.Delimiter); n >= 0 {
+				prefix := name[:len(opts.Prefix)+n+len(opts.Delimiter)]
+				// A prefix up to the marker was listed with it.
+				if prefix == lastPrefix || (opts.KeyMarker != "" && prefix <= opts.KeyMarker) {
+					continue
+				}
+				if full() {
+					break
+				}
+				res.CommonPrefixes = append(res.CommonPrefixes, prefix)
+				res.NextKeyMarker, res.NextVersionIDMarker = prefix, ""
+				lastPrefix = prefix
+				continue
+			}
+		}
+		if name == opts.KeyMarker && opts.VersionIDMarker == "" {
+			continue
+		}
+		z, err := load(i)
+		if err != nil {
+			return nil, err
+		}
+		idxs := z.versionIndexes()
+		// Versions are listed newest first, the journal holds them oldest first.
+		next := len(idxs) - 1
+		if name == opts.KeyMarker {
+			for next >= 0 && z.ObjectJournals[idxs[next]].VersionID() != markerID {
+				next--
+			}
+			if next < 0 {
+				return nil, fmt.Errorf("%w: version %s of %q not found", errInvalidVersionID, opts.VersionIDMarker, name)
+			}
+			next--
+		}
+		for ; next >= 0; next-- {
+			if full() {
+				break
+			}
+			v := objectVersion(name, &z.ObjectJournals[idxs[next]])
+			v.IsLatest = next == len(idxs)-1
+			res.Versions = append(res.Versions, v)
+			res.NextKeyMarker, res.NextVersionIDMarker = name, v.VersionID
+		}
+		if res.IsTruncated {
+			break
+		}
+	}
+	if !res.IsTruncated {
+		res.NextKeyMarker, res.NextVersionIDMarker = "", ""
+	}
+	return res, nil
+}
+
+// objectVersion returns the listing of version e of name.
+func objectVersion(name string, e *ObjectMetaV2JournalEntry) ObjectVersion {
+	v := ObjectVersion{
+		Key:          name,
+		VersionID:    FormatVersionID(e.VersionID()),
+		LastModified: time.Unix(e.ModTime(), 0).UTC(),
+	}
+	obj := e.Object
+	switch e.Type {
+	case Delete:
+		v.DeleteMarker = true
+		return v
+	case Link:
+		obj = (*ObjectMetaV2Object)(e.Link)
+	}
+	v.Size = int64(obj.StatSize)
+	if etag := obj.MetaUser["etag"]; len(etag) > 0 {
+		v.ETag = etag[0]
+	}
+	return v
+}
@@ -0,0 +1,301 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// listingObjects returns objects with versions, delete markers, a null
+// version and an object with only a multipart upload, which is not listed.
+func listingObjects() []NamedMetadata {
+	obj := func(id uint64, t int64) ObjectMetaV2JournalEntry {
+		o := newObjectMetaV2Object(1)
+		o.VersionID, o.StatModTime, o.StatSize = id, t, int(id)*100
+		o.MetaUser = map[string][]string{"etag": {fmt.Sprint("etag-", id)}}
+		return ObjectMetaV2JournalEntry{Type: Object, Object: o}
+	}
+	marker := func(id uint64, t int64) ObjectMetaV2JournalEntry {
+		return ObjectMetaV2JournalEntry{Type: Delete, DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: id, ModTime: t}}
+	}
+	meta := func(entries ...ObjectMetaV2JournalEntry) *ObjectMetaV2 {
+		return &ObjectMetaV2{Version: 200, Format: XL, ObjectJournals: entries}
+	}
+	upload := ObjectMetaV2JournalEntry{Type: Multipart, Multipart: &ObjectMetaV2Multipart{UploadID: "upload", Initiated: 45}}
+	// Not sorted by name.
+	return []NamedMetadata{
+		{"e", meta(obj(0, 50), obj(9, 60))},
+		{"a.txt", meta(obj(1, 10), obj(2, 20))},
+		{"b/1", meta(obj(3, 5), upload, marker(4, 30))},
+		{"d", meta(upload)},
+		{"b/2", meta(obj(5, 7))},
+		{"c", meta(marker(6, 40))},
+	}
+}
+
+// ver returns the expected listing of a version of listingObjects.
+func ver(key string, id uint64, latest bool, t int64) ObjectVersion {
+	return ObjectVersion{
+		Key:          key,
+		VersionID:    FormatVersionID(id),
+		IsLatest:     latest,
+		LastModified: time.Unix(t, 0).UTC(),
+		Size:         int64(id) * 100,
+		ETag:         fmt.Sprint("etag-", id),
+	}
+}
+
+// deleted returns the expected listing of a delete marker of listingObjects.
+func deleted(key string, id uint64, latest bool, t int64) ObjectVersion {
+	return ObjectVersion{Key: key, VersionID: FormatVersionID(id), IsLatest: latest, DeleteMarker: true, LastModified: time.Unix(t, 0).UTC()}
+}
+
+func TestListObjectVersions(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts ListVersionsOptions
+		want ListVersionsResult
+	}{
+		{
+			name: "all",
+			want: ListVersionsResult{Versions: []ObjectVersion{
+				ver("a.txt", 2, true, 20),
+				ver("a.txt", 1, false, 10),
+				deleted("b/1", 4, true, 30),
+				ver("b/1", 3, false, 5),
+				ver("b/2", 5, true, 7),
+				deleted("c", 6, true, 40),
+				ver("e", 9, true, 60),
+				ver("e", 0, false, 50),
+			}},
+		},
+		{
+			name: "delimiter",
+			opts: ListVersionsOptions{Delimiter: "/"},
+			want: ListVersionsResult{
+				Versions: []ObjectVersion{
+					ver("a.txt", 2, true, 20),
+					ver("a.txt", 1, false, 10),
+					deleted("c", 6, true, 40),
+					ver("e", 9, true, 60),
+					ver("e", 0, false, 50),
+				},
+				CommonPrefixes: []string{"b/"},
+			},
+		},
+		{
+			name: "prefix",
+			opts: ListVersionsOptions{Prefix: "b/", Delimiter: "/"},
+			want: ListVersionsResult{Versions: []ObjectVersion{
+				deleted("b/1", 4, true, 30),
+				ver("b/1", 3, false, 5),
+				ver("b/2", 5, true, 7),
+			}},
+		},
+		{
+			name: "prefix without match",
+			opts: ListVersionsOptions{Prefix: "b/3"},
+			want: ListVersionsResult{},
+		},
+		{
+			name: "truncated in an object",
+			opts: ListVersionsOptions{MaxKeys: 3},
+			want: ListVersionsResult{
+				Versions: []ObjectVersion{
+					ver("a.txt", 2, true, 20),
+					ver("a.txt", 1, false, 10),
+					deleted("b/1", 4, true, 30),
+				},
+				IsTruncated:         true,
+				NextKeyMarker:       "b/1",
+				NextVersionIDMarker: "0000000000000004",
+			},
+		},
+		{
+			name: "continued in an object",
+			opts: ListVersionsOptions{KeyMarker: "b/1", VersionIDMarker: "0000000000000004", MaxKeys: 3},
+			want: ListVersionsResult{
+				Versions: []ObjectVersion{
+					ver("b/1", 3, false, 5),
+					ver("b/2", 5, true, 7),
+					deleted("c", 6, true, 40),
+				},
+				IsTruncated:         true,
+				NextKeyMarker:       "c",
+				NextVersionIDMarker: "0000000000000006",
+			},
+		},
+		{
+			name: "continued after the null version",
+			opts: ListVersionsOptions{KeyMarker: "e", VersionIDMarker: "null"},
+			want: ListVersionsResult{},
+		},
+		{
+			name: "key marker only",
+			opts: ListVersionsOptions{KeyMarker: "b/2"},
+			want: ListVersionsResult{Versions: []ObjectVersion{
+				deleted("c", 6, true, 40),
+				ver("e", 9, true, 60),
+				ver("e", 0, false, 50),
+			}},
+		},
+		{
+			name: "truncated on a common prefix",
+			opts: ListVersionsOptions{Delimiter: "/", MaxKeys: 3},
+			want: ListVersionsResult{
+				Versions: []ObjectVersion{
+					ver("a.txt", 2, true, 20),
+					ver("a.txt", 1, false, 10),
+				},
+				CommonPrefixes: []string{"b/"},
+				IsTruncated:    true,
+				NextKeyMarker:  "b/",
+			},
+		},
+		{
+			name: "continued after a common prefix",
+			opts: ListVersionsOptions{Delimiter: "/", KeyMarker: "b/", MaxKeys: 3},
+			want: ListVersionsResult{Versions: []ObjectVersion{
+				deleted("c", 6, true, 40),
+				ver("e", 9, true, 60),
+				ver("e", 0, false, 50),
+			}},
+		},
+		{
+			name: "exactly full",
+			opts: ListVersionsOptions{KeyMarker: "c", MaxKeys: 2},
+			want: ListVersionsResult{Versions: []ObjectVersion{
+				ver("e", 9, true, 60),
+				ver("e", 0, false, 50),
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		got, err := ListObjectVersions(listingObjects(), tc.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(*got, tc.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tc.name, *got, tc.want)
+		}
+	}
+}
+
+// listAllPages lists every page of list, checking the page sizes.
+func listAllPages(t *testing.T, opts ListVersionsOptions, list func(ListVersionsOptions) (*ListVersionsResult, error)) ListVersionsResult {
+	t.Helper()
+	var all ListVersionsResult
+	for {
+		page, err := list(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(page.Versions) + len(page.CommonPrefixes); n > opts.MaxKeys || (page.IsTruncated && n != opts.MaxKeys) {
+			t.Fatalf("page of %d entries, max %d, truncated %v", n, opts.MaxKeys, page.IsTruncated)
+		}
+		all.Versions = append(all.Versions, page.Versions...)
+		all.CommonPrefixes = append(all.CommonPrefixes, page.CommonPrefixes...)
+		if !page.IsTruncated {
+			return all
+		}
+		opts.KeyMarker, opts.VersionIDMarker = page.NextKeyMarker, page.NextVersionIDMarker
+	}
+}
+
+func TestListObjectVersionsPages(t *testing.T) {
+	list := func(opts ListVersionsOptions) (*ListVersionsResult, error) {
+		return ListObjectVersions(listingObjects(), opts)
+	}
+	for _, delimiter := range []string{"", "/"} {
+		want, err := list(ListVersionsOptions{Delimiter: delimiter})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for maxKeys := 1; maxKeys <= 9; maxKeys++ {
+			got := listAllPages(t, ListVersionsOptions{Delimiter: delimiter, MaxKeys: maxKeys}, list)
+			if !reflect.DeepEqual(got, *want) {
+				t.Errorf("delimiter %q by %d: pages differ from a single listing\ngot  %+v\nwant %+v", delimiter, maxKeys, got, *want)
+			}
+		}
+	}
+}
+
+func TestListObjectVersionsErrors(t *testing.T) {
+	objects := listingObjects()
+	for _, opts := range []ListVersionsOptions{
+		{VersionIDMarker: "0000000000000001"},
+		{KeyMarker: "a.txt", VersionIDMarker: "1"},
+		{KeyMarker: "a.txt", VersionIDMarker: "0000000000000000"},
+	} {
+		if _, err := ListObjectVersions(objects, opts); err == nil {
+			t.Errorf("%+v: listed", opts)
+		}
+	}
+	if _, err := ListObjectVersions(objects, ListVersionsOptions{KeyMarker: "a.txt", VersionIDMarker: "0000000000000003"}); !errors.Is(err, errInvalidVersionID) {
+		t.Errorf("marker of another object: want %v, got %v", errInvalidVersionID, err)
+	}
+	if _, err := ListObjectVersions(append(objects, objects[0]), ListVersionsOptions{}); !errors.Is(err, errDuplicateListName) {
+		t.Errorf("duplicate names: want %v, got %v", errDuplicateListName, err)
+	}
+
+	for _, id := range []uint64{0, 1, 1<<64 - 1} {
+		if got, err := ParseVersionID(FormatVersionID(id)); err != nil || got != id {
+			t.Errorf("version ID %d: got %d, %v", id, got, err)
+		}
+	}
+}
+
+func TestListObjectVersionsDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xl-meta-list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	objects := listingObjects()
+	for _, o := range objects {
+		buf, err := o.Meta.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(o.Name), MetaFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, buf, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Other files are ignored.
+	if err := ioutil.WriteFile(filepath.Join(dir, "b", "part.1"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []ListVersionsOptions{{}, {Delimiter: "/"}, {Prefix: "b/"}, {MaxKeys: 3}} {
+		want, err := ListObjectVersions(objects, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ListObjectVersionsDir(dir, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%+v: directory listing differs\ngot  %+v\nwant %+v", opts, *got, *want)
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "c", MetaFile), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ListObjectVersionsDir(dir, ListVersionsOptions{}); err == nil {
+		t.Error("corrupt metadata listed")
+	}
+	if _, err := ListObjectVersionsDir(dir, ListVersionsOptions{MaxKeys: 2}); err != nil {
+		t.Errorf("metadata past the page read: %v", err)
+	}
+}
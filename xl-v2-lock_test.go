@@ -0,0 +1,123 @@
+package xlmeta
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestObjectMetaV2DeleteVersionLocked(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	future := now.Add(time.Hour)
+	testCases := []struct {
+		name      string
+		mode      RetentionMode
+		until     time.Time
+		legalHold bool
+		bypass    bool
+		locked    bool
+	}{
+		{name: "unlocked"},
+		{name: "governance", mode: RetentionGovernance, until: future, locked: true},
+		{name: "governance bypass", mode: RetentionGovernance, until: future, bypass: true},
+		{name: "compliance", mode: RetentionCompliance, until: future, locked: true},
+		{name: "compliance bypass", mode: RetentionCompliance, until: future, bypass: true, locked: true},
+		{name: "retention expired", mode: RetentionCompliance, until: now},
+		{name: "legal hold", legalHold: true, bypass: true, locked: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			z := lifecycleJournal(0)
+			obj := z.ObjectJournals[0].Object
+			obj.RetentionMode, obj.RetainUntil, obj.LegalHold = tc.mode, tc.until.Unix(), tc.legalHold
+
+			// Adding and removing a delete marker on top of a locked version is allowed.
+			z.AddDeleteMarker(2, now)
+			if err := z.DeleteVersion(2, DeleteOptions{Now: now}); err != nil {
+				t.Fatal(err)
+			}
+
+			err := z.DeleteVersion(1, DeleteOptions{Now: now, BypassGovernance: tc.bypass})
+			var lockErr *ObjectLockedError
+			if tc.locked {
+				if !errors.As(err, &lockErr) || lockErr.VersionID != 1 || len(z.ObjectJournals) != 1 {
+					t.Fatalf("want locked error, got %v", err)
+				}
+				return
+			}
+			if err != nil || len(z.ObjectJournals) != 0 {
+				t.Fatalf("version not deleted: %v", err)
+			}
+			if err = z.DeleteVersion(1, DeleteOptions{Now: now}); err != errVersionNotFound {
+				t.Fatalf("want %v, got %v", errVersionNotFound, err)
+			}
+		})
+	}
+}
+
+func TestObjectMetaV2ObjectSetRetention(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	obj := newObjectMetaV2Object(1)
+	if err := obj.SetRetention(RetentionGovernance, now, now, false); !errors.Is(err, errInvalidRetention) {
+		t.Fatalf("want %v, got %v", errInvalidRetention, err)
+	}
+	if err := obj.SetRetention(RetentionGovernance, now.Add(2*time.Hour), now, false); err != nil {
+		t.Fatal(err)
+	}
+	var lockErr *ObjectLockedError
+	if err := obj.SetRetention(RetentionGovernance, now.Add(time.Hour), now, false); !errors.As(err, &lockErr) {
+		t.Fatalf("shortening governance without bypass: %v", err)
+	}
+	if err := obj.SetRetention(RetentionNone, time.Time{}, now, true); err != nil || obj.RetainUntil != 0 {
+		t.Fatalf("removing governance with bypass: %v", err)
+	}
+	if err := obj.SetRetention(RetentionCompliance, now.Add(time.Hour), now, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj.SetRetention(RetentionGovernance, now.Add(2*time.Hour), now, true); !errors.As(err, &lockErr) {
+		t.Fatalf("downgrading compliance: %v", err)
+	}
+	if err := obj.SetRetention(RetentionCompliance, now.Add(2*time.Hour), now, false); err != nil {
+		t.Fatalf("extending compliance: %v", err)
+	}
+	obj.LegalHold = true
+
+	xlmeta := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{{Type: Object, Object: obj}}}
+	buf, err := xlmeta.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ObjectMetaV2
+	if _, err = decoded.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonBuf, err := json.Marshal(xlmeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ObjectMetaV2
+	if err = json.Unmarshal(jsonBuf, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []*ObjectMetaV2Object{decoded.ObjectJournals[0].Object, fromJSON.ObjectJournals[0].Object} {
+		if got.RetentionMode != RetentionCompliance || got.RetainUntil != now.Add(2*time.Hour).Unix() || !got.LegalHold {
+			t.Fatalf("retention lost in round trip: %+v", got)
+		}
+	}
+}
+
+func TestLifecycleEvaluatorSkipsLockedVersions(t *testing.T) {
+	z := lifecycleJournal(0, 1, 2)
+	z.ObjectJournals[0].Object.LegalHold = true
+	ev := LifecycleEvaluator{
+		Config: parseTestLifecycle(t, `<Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays></NoncurrentVersionExpiration></Rule>`),
+		Now:    func() time.Time { return day(30) },
+	}
+	events := ev.Eval("", z)
+	if len(events) != 1 || events[0].VersionID != 2 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
@@ -0,0 +1,386 @@
+package xlmeta
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// MetaSys keys holding the sealed data key of an object.
+const (
+	metaSysKeyID     = "meta-kek-id" // ID of the master key that sealed the data key.
+	metaSysDataKey   = "meta-dek"    // Sealed data key.
+	metaSysSealedSet = "meta-sealed" // Comma separated names of the sealed values.
+)
+
+var (
+	errKeyNotFound     = errors.New("master key not found")
+	errInvalidKeyFile  = errors.New("invalid key file")
+	errUnsealFailed    = errors.New("message authentication failed")
+	errMetaSysSealed   = errors.New("metadata already sealed")
+	errReservedMetaSys = errors.New("reserved metadata key")
+)
+
+// KeyProvider generates and decrypts per-object data keys with master keys
+// it holds. The context is authenticated but not stored in the sealed key;
+// the same context must be given to DecryptKey.
+type KeyProvider interface {
+	// GenerateKey returns a new 256 bit data key in plaintext and sealed
+	// with the current master key, along with the ID of that master key.
+	GenerateKey(context []byte) (keyID string, plaintext, sealed []byte, err error)
+	// DecryptKey unseals a data key sealed by the master key keyID.
+	DecryptKey(keyID string, sealed, context []byte) ([]byte, error)
+}
+
+// MetaSysCryptoError is returned when sealed metadata cannot be unsealed.
+// Err is errKeyNotFound when the master key is unknown and errUnsealFailed
+// when the data was tampered with or sealed with another key.
+type MetaSysCryptoError struct {
+	VersionID uint64
+	Key       string // MetaSys key, or the key ID of the master key.
+	Err       error
+}
+
+func (e *MetaSysCryptoError) Error() string {
+	return fmt.Sprintf("version %d: unsealing %q: %v", e.VersionID, e.Key, e.Err)
+}
+
+func (e *MetaSysCryptoError) Unwrap() error { return e.Err }
+
+// LocalKeyProvider is a KeyProvider with master keys read from a file.
+type LocalKeyProvider struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewLocalKeyProvider reads master keys from path.
+// Each line holds a key ID and a base64 encoded 256 bit key separated by ':'.
+// Empty lines and lines starting with '#' are ignored.
+// The first key seals new data keys, the others only decrypt existing ones.
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseLocalKeys(f)
+}
+
+func parseLocalKeys(r io.Reader) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{keys: make(map[string]cipher.AEAD)}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.IndexByte(text, ':')
+		if i <= 0 {
+			return nil, fmt.Errorf("%w: line %d: missing key ID", errInvalidKeyFile, line)
+		}
+		id := text[:i]
+		key, err := base64.StdEncoding.DecodeString(text[i+1:])
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%w: line %d: key must be 32 base64 encoded bytes", errInvalidKeyFile, line)
+		}
+		if _, ok := p.keys[id]; ok {
+			return nil, fmt.Errorf("%w: line %d: duplicate key ID %q", errInvalidKeyFile, line, id)
+		}
+		if p.keys[id], err = newGCM(key); err != nil {
+			return nil, err
+		}
+		if p.current == "" {
+			p.current = id
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if p.current == "" {
+		return nil, fmt.Errorf("%w: no keys", errInvalidKeyFile)
+	}
+	return p, nil
+}
+
+// GenerateKey implements KeyProvider.
+func (p *LocalKeyProvider) GenerateKey(context []byte) (keyID string, plaintext, sealed []byte, err error) {
+	plaintext = make([]byte, 32)
+	if _, err = io.ReadFull(rand.Reader, plaintext); err != nil {
+		return "", nil, nil, err
+	}
+	sealed, err = sealGCM(p.keys[p.current], plaintext, context)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return p.current, plaintext, sealed, nil
+}
+
+// DecryptKey implements KeyProvider.
+func (p *LocalKeyProvider) DecryptKey(keyID string, sealed, context []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, errKeyNotFound
+	}
+	return openGCM(aead, sealed, context)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealGCM encrypts plaintext with a random nonce, which is prepended to the result.
+func sealGCM(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	out := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, out); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out, plaintext, additionalData), nil
+}
+
+func openGCM(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errUnsealFailed
+	}
+	nonce := sealed[:aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, sealed[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, errUnsealFailed
+	}
+	return plaintext, nil
+}
+
+// MetaSysSealer encrypts selected MetaSys values with a data key unique
+// to each object version. The data key is sealed by the key provider and
+// stored in MetaSys along with the master key ID.
+// Values are bound to their version and key, so they cannot be swapped.
+// Metadata must be unsealed before it is modified.
+type MetaSysSealer struct {
+	Keys KeyProvider
+	// Names of the MetaSys values to encrypt. Absent names are skipped.
+	Names []string
+}
+
+// versionContext returns versionID followed by s.
+// It binds data keys to their version and set of sealed names,
+// and sealed values to their version and name.
+func versionContext(versionID uint64, s string) []byte {
+	ctx := make([]byte, 8, 8+len(s))
+	binary.BigEndian.PutUint64(ctx, versionID)
+	return append(ctx, s...)
+}
+
+// Seal encrypts the selected MetaSys values of z in place.
+func (s *MetaSysSealer) Seal(z *ObjectMetaV2Object) error {
+	if _, ok := z.MetaSys[metaSysDataKey]; ok {
+		return errMetaSysSealed
+	}
+	var names []string
+	for _, name := range s.Names {
+		switch name {
+		case metaSysKeyID, metaSysDataKey, metaSysSealedSet:
+			return fmt.Errorf("%w: %s", errReservedMetaSys, name)
+		}
+		if strings.Contains(name, ",") {
+			return fmt.Errorf("%w: %q contains ','", errReservedMetaSys, name)
+		}
+		if _, ok := z.MetaSys[name]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	set := strings.Join(names, ",")
+
+	keyID, dataKey, sealedKey, err := s.Keys.GenerateKey(versionContext(z.VersionID, set))
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+	sealed := make(map[string][]byte, len(names))
+	for _, name := range names {
+		if sealed[name], err = sealGCM(aead, z.MetaSys[name], versionContext(z.VersionID, name)); err != nil {
+			return err
+		}
+	}
+	for name, value := range sealed {
+		z.MetaSys[name] = value
+	}
+	z.MetaSys[metaSysKeyID] = []byte(keyID)
+	z.MetaSys[metaSysDataKey] = sealedKey
+	z.MetaSys[metaSysSealedSet] = []byte(set)
+	return nil
+}
+
+// Unseal decrypts the MetaSys values of z sealed by Seal.
+// Objects without sealed values are left unchanged.
+// Failures are returned as *MetaSysCryptoError and leave z unchanged.
+func (s *MetaSysSealer) Unseal(z *ObjectMetaV2Object) error {
+	metaSys, err := s.unsealed(z)
+	if err != nil || metaSys == nil {
+		return err
+	}
+	z.MetaSys = metaSys
+	return nil
+}
+
+// unsealed returns a copy of the MetaSys of z with the values sealed by Seal
+// decrypted, or nil if z has no sealed values. z is not modified.
+func (s *MetaSysSealer) unsealed(z *ObjectMetaV2Object) (map[string][]byte, error) {
+	sealedKey, ok := z.MetaSys[metaSysDataKey]
+	if !ok {
+		return nil, nil
+	}
+	keyID := string(z.MetaSys[metaSysKeyID])
+	set := string(z.MetaSys[metaSysSealedSet])
+	dataKey, err := s.Keys.DecryptKey(keyID, sealedKey, versionContext(z.VersionID, set))
+	if err != nil {
+		return nil, &MetaSysCryptoError{VersionID: z.VersionID, Key: keyID, Err: err}
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	metaSys := make(map[string][]byte, len(z.MetaSys))
+	for k, v := range z.MetaSys {
+		metaSys[k] = v
+	}
+	for _, name := range strings.Split(set, ",") {
+		value, ok := z.MetaSys[name]
+		if !ok {
+			return nil, &MetaSysCryptoError{VersionID: z.VersionID, Key: name, Err: errUnsealFailed}
+		}
+		if metaSys[name], err = openGCM(aead, value, versionContext(z.VersionID, name)); err != nil {
+			return nil, &MetaSysCryptoError{VersionID: z.VersionID, Key: name, Err: err}
+		}
+	}
+	delete(metaSys, metaSysKeyID)
+	delete(metaSys, metaSysDataKey)
+	delete(metaSys, metaSysSealedSet)
+	return metaSys, nil
+}
+
+// metaObject returns the object carrying the metadata of e, or nil.
+func (e *ObjectMetaV2JournalEntry) metaObject() *ObjectMetaV2Object {
+	switch {
+	case e.Type == Object && e.Object != nil:
+		return e.Object
+	case e.Type == Link && e.Link != nil:
+		return (*ObjectMetaV2Object)(e.Link)
+	case e.Type == Multipart && e.Multipart != nil:
+		return &e.Multipart.Object
+	}
+	return nil
+}
+
+// journalObjects calls fn for every object carrying metadata in the journal of z.
+func (z *ObjectMetaV2) journalObjects(fn func(obj *ObjectMetaV2Object) error) error {
+	for i := range z.ObjectJournals {
+		if obj := z.ObjectJournals[i].metaObject(); obj != nil {
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SealAll seals every version and multipart upload of z in place.
+// MarshalMsgWithOptions seals the encoded metadata without modifying z.
+func (s *MetaSysSealer) SealAll(z *ObjectMetaV2) error {
+	return z.journalObjects(s.Seal)
+}
+
+// UnsealAll unseals every version and multipart upload of z.
+// Decoding never unseals: call it on metadata decoded by any of UnmarshalMsg,
+// UnmarshalMsgReuse, DecodeMsg and their variants with options.
+// If any version fails to unseal, its error is returned and z is unchanged.
+func (s *MetaSysSealer) UnsealAll(z *ObjectMetaV2) error {
+	var objs []*ObjectMetaV2Object
+	var metaSys []map[string][]byte
+	err := z.journalObjects(func(obj *ObjectMetaV2Object) error {
+		m, err := s.unsealed(obj)
+		if m != nil {
+			objs = append(objs, obj)
+			metaSys = append(metaSys, m)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for i, obj := range objs {
+		obj.MetaSys = metaSys[i]
+	}
+	return nil
+}
+
+// sealedCopy returns a copy of z with the selected MetaSys values of every
+// version and multipart upload sealed. z is not modified; the copy only
+// shares with z what sealing does not change.
+func (s *MetaSysSealer) sealedCopy(z *ObjectMetaV2) (*ObjectMetaV2, error) {
+	c := *z
+	c.ObjectJournals = make([]ObjectMetaV2JournalEntry, len(z.ObjectJournals))
+	for i, e := range z.ObjectJournals {
+		switch {
+		case e.Type == Object && e.Object != nil:
+			obj := *e.Object
+			e.Object = &obj
+		case e.Type == Link && e.Link != nil:
+			link := *e.Link
+			e.Link = &link
+		case e.Type == Multipart && e.Multipart != nil:
+			mp := *e.Multipart
+			e.Multipart = &mp
+		}
+		if obj := e.metaObject(); obj != nil && obj.MetaSys != nil {
+			metaSys := make(map[string][]byte, len(obj.MetaSys)+3)
+			for k, v := range obj.MetaSys {
+				metaSys[k] = v
+			}
+			obj.MetaSys = metaSys
+			if err := s.Seal(obj); err != nil {
+				return nil, err
+			}
+		}
+		c.ObjectJournals[i] = e
+	}
+	return &c, nil
+}
+
+// EncodeOptions controls MarshalMsgWithOptions.
+type EncodeOptions struct {
+	// Sealer, if not nil, seals the selected MetaSys values of the
+	// encoded metadata. Unseal it after decoding with UnsealAll.
+	Sealer *MetaSysSealer
+}
+
+// MarshalMsgWithOptions is MarshalMsg with opts.
+// z itself is never sealed and keeps its values in plaintext.
+func (z *ObjectMetaV2) MarshalMsgWithOptions(b []byte, opts EncodeOptions) ([]byte, error) {
+	if opts.Sealer == nil {
+		return z.MarshalMsg(b)
+	}
+	c, err := opts.Sealer.sealedCopy(z)
+	if err != nil {
+		return b, err
+	}
+	return c.MarshalMsg(b)
+}
@@ -0,0 +1,287 @@
+package xlmeta
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// writeKeyFile writes a key file with the given key IDs and keys and returns its path.
+func writeKeyFile(t *testing.T, dir, name string, keys ...string) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("# test keys\n\n")
+	for i := 0; i < len(keys); i += 2 {
+		b.WriteString(keys[i] + ":" + base64.StdEncoding.EncodeToString([]byte(keys[i+1])) + "\n")
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMetaSysSealer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xl-meta-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key1 := strings.Repeat("1", 32)
+	key2 := strings.Repeat("2", 32)
+	keys, err := NewLocalKeyProvider(writeKeyFile(t, dir, "keys", "k1", key1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealer := &MetaSysSealer{Keys: keys, Names: []string{"mac", "absent"}}
+
+	// sealed returns the sample metadata sealed and encoded.
+	sealed := func() []byte {
+		xlmeta := getSampleObjectMetaV2(1, 2)
+		xlmeta.ObjectJournals[1].Object.VersionID = 1
+		if err := sealer.SealAll(&xlmeta); err != nil {
+			t.Fatal(err)
+		}
+		if err := sealer.SealAll(&xlmeta); err != errMetaSysSealed {
+			t.Fatalf("want %v, got %v", errMetaSysSealed, err)
+		}
+		obj := xlmeta.ObjectJournals[0].Object
+		if bytes.Contains(obj.MetaSys["mac"], []byte("hmac")) || string(obj.MetaSys["minio-release"]) != "DEVELOPMENT.GOGET" {
+			t.Fatalf("unexpected sealed metadata %q", obj.MetaSys)
+		}
+		buf, err := xlmeta.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf
+	}
+	decode := func(buf []byte) *ObjectMetaV2 {
+		var xlmeta ObjectMetaV2
+		if _, err := xlmeta.UnmarshalMsg(buf); err != nil {
+			t.Fatal(err)
+		}
+		return &xlmeta
+	}
+
+	xlmeta := decode(sealed())
+	if err = sealer.UnsealAll(xlmeta); err != nil {
+		t.Fatal(err)
+	}
+	want := newObjectMetaV2Object(1).MetaSys
+	for _, e := range xlmeta.ObjectJournals {
+		if len(e.Object.MetaSys) != len(want) || !bytes.Equal(e.Object.MetaSys["mac"], want["mac"]) {
+			t.Fatalf("unexpected unsealed metadata %q", e.Object.MetaSys)
+		}
+	}
+
+	// Old master keys still unseal after rotation.
+	rotated, err := NewLocalKeyProvider(writeKeyFile(t, dir, "rotated", "k2", key2, "k1", key1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = (&MetaSysSealer{Keys: rotated}).UnsealAll(decode(sealed())); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		keys    *LocalKeyProvider
+		tamper  func(xlmeta *ObjectMetaV2)
+		wantKey string
+		wantErr error
+	}{
+		{
+			name:    "unknown master key",
+			keys:    parseTestKeys(t, "k2:"+base64.StdEncoding.EncodeToString([]byte(key2))),
+			wantKey: "k1",
+			wantErr: errKeyNotFound,
+		},
+		{
+			name:    "wrong master key",
+			keys:    parseTestKeys(t, "k1:"+base64.StdEncoding.EncodeToString([]byte(key2))),
+			wantKey: "k1",
+			wantErr: errUnsealFailed,
+		},
+		{
+			name: "tampered value",
+			tamper: func(xlmeta *ObjectMetaV2) {
+				xlmeta.ObjectJournals[0].Object.MetaSys["mac"][20] ^= 1
+			},
+			wantKey: "mac",
+			wantErr: errUnsealFailed,
+		},
+		{
+			name: "value of another version",
+			tamper: func(xlmeta *ObjectMetaV2) {
+				objs := xlmeta.ObjectJournals
+				objs[0].Object.MetaSys["mac"] = objs[1].Object.MetaSys["mac"]
+			},
+			wantKey: "mac",
+			wantErr: errUnsealFailed,
+		},
+		{
+			name: "sealed set changed",
+			tamper: func(xlmeta *ObjectMetaV2) {
+				xlmeta.ObjectJournals[0].Object.MetaSys[metaSysSealedSet] = []byte("mac,minio-release")
+			},
+			wantKey: "k1",
+			wantErr: errUnsealFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			xlmeta := decode(sealed())
+			if tc.tamper != nil {
+				tc.tamper(xlmeta)
+			}
+			keys := keys
+			if tc.keys != nil {
+				keys = tc.keys
+			}
+			before := string(xlmeta.ObjectJournals[0].Object.MetaSys["mac"])
+			err := (&MetaSysSealer{Keys: keys}).Unseal(xlmeta.ObjectJournals[0].Object)
+			var cryptoErr *MetaSysCryptoError
+			if !errors.As(err, &cryptoErr) || cryptoErr.Key != tc.wantKey || !errors.Is(err, tc.wantErr) {
+				t.Fatalf("want %v for %q, got %v", tc.wantErr, tc.wantKey, err)
+			}
+			if string(xlmeta.ObjectJournals[0].Object.MetaSys["mac"]) != before {
+				t.Fatal("failed unseal modified metadata")
+			}
+		})
+	}
+}
+
+func TestMetaSysSealerCodec(t *testing.T) {
+	sealer := &MetaSysSealer{
+		Keys:  parseTestKeys(t, "k1:"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("1"), 32))),
+		Names: []string{"mac"},
+	}
+	xlmeta := getSampleObjectMetaV2(1, 2)
+	xlmeta.ObjectJournals[1].Object.VersionID = 1
+	template := *newObjectMetaV2Object(0)
+	if err := xlmeta.NewMultipartUpload("upload", template, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	want := newObjectMetaV2Object(1).MetaSys
+
+	// Encoding twice seals each time and leaves the metadata in plaintext.
+	var buf []byte
+	for i := 0; i < 2; i++ {
+		var err error
+		if buf, err = xlmeta.MarshalMsgWithOptions(buf[:0], EncodeOptions{Sealer: sealer}); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range xlmeta.ObjectJournals {
+			if obj := e.metaObject(); !bytes.Equal(obj.MetaSys["mac"], want["mac"]) || obj.MetaSys[metaSysDataKey] != nil {
+				t.Fatalf("encoding modified metadata %q", obj.MetaSys)
+			}
+		}
+	}
+	if bytes.Contains(buf, want["mac"]) {
+		t.Fatal("value encoded in plaintext")
+	}
+
+	var plain ObjectMetaV2
+	if _, err := plain.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plain.ObjectJournals[plain.multipartUpload("upload")].Multipart.Object.MetaSys[metaSysDataKey]; !ok {
+		t.Fatal("multipart upload not sealed")
+	}
+
+	// Decoding keeps the values sealed until UnsealAll.
+	decoders := map[string]func() (*ObjectMetaV2, error){
+		"UnmarshalMsg": func() (*ObjectMetaV2, error) {
+			var z ObjectMetaV2
+			_, err := z.UnmarshalMsg(buf)
+			return &z, err
+		},
+		"DecodeMsg": func() (*ObjectMetaV2, error) {
+			var z ObjectMetaV2
+			return &z, z.DecodeMsg(msgp.NewReader(bytes.NewReader(buf)))
+		},
+		"UnmarshalMsgReuse": func() (*ObjectMetaV2, error) {
+			z := GetObjectMetaV2()
+			_, err := z.UnmarshalMsgReuse(buf)
+			return z, err
+		},
+	}
+	for name, decode := range decoders {
+		got, err := decode()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := sealer.UnsealAll(got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !equalObjectMetaV2(got, &xlmeta) {
+			t.Fatalf("%s: unsealed metadata differs\ngot  %+v\nwant %+v", name, got.ObjectJournals[0].Object.MetaSys, want)
+		}
+	}
+	var header ObjectMetaV2
+	entry, err := header.GetJournalEntryN(buf, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sealer.Unseal(entry.Object); err != nil || !bytes.Equal(entry.Object.MetaSys["mac"], want["mac"]) {
+		t.Fatalf("entry not unsealed: %q, %v", entry.Object.MetaSys, err)
+	}
+
+	// Failures leave every version sealed.
+	for name, keys := range map[string]KeyProvider{
+		"unknown key": parseTestKeys(t, "k2:"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("2"), 32))),
+		"tampered":    sealer.Keys,
+	} {
+		var got ObjectMetaV2
+		if _, err := got.UnmarshalMsg(buf); err != nil {
+			t.Fatal(err)
+		}
+		if name == "tampered" {
+			mac := append([]byte(nil), got.ObjectJournals[1].Object.MetaSys["mac"]...)
+			mac[0] ^= 1
+			got.ObjectJournals[1].Object.MetaSys["mac"] = mac
+		}
+		var cryptoErr *MetaSysCryptoError
+		if err := (&MetaSysSealer{Keys: keys}).UnsealAll(&got); !errors.As(err, &cryptoErr) {
+			t.Fatalf("%s: want *MetaSysCryptoError, got %v", name, err)
+		}
+		for i := range got.ObjectJournals {
+			if obj := got.ObjectJournals[i].metaObject(); obj.MetaSys[metaSysDataKey] == nil {
+				t.Fatalf("%s: entry %d unsealed by a failed UnsealAll", name, i)
+			}
+		}
+	}
+}
+
+func parseTestKeys(t *testing.T, file string) *LocalKeyProvider {
+	t.Helper()
+	keys, err := parseLocalKeys(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestParseLocalKeys(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	for _, file := range []string{
+		"",
+		"# no keys\n",
+		key + "\n",
+		"k1:" + base64.StdEncoding.EncodeToString(make([]byte, 16)),
+		"k1:not base64",
+		"k1:" + key + "\nk1:" + key,
+	} {
+		if _, err := parseLocalKeys(strings.NewReader(file)); !errors.Is(err, errInvalidKeyFile) {
+			t.Errorf("%q: want %v, got %v", file, errInvalidKeyFile, err)
+		}
+	}
+}
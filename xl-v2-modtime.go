@@ -0,0 +1,291 @@
+package xlmeta
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+var errNoJournal = errors.New("metadata without journal")
+
+// modTimeJournal is a journal sorted by ModTime, oldest first, so that the
+// version current at a time is found by binary search. InsertVersion keeps
+// the journal sorted and SortByModTime sorts journals written in another
+// order. Lookups work on decoded metadata and, through a JournalIndex,
+// on the serialized form without decoding the entries.
+type modTimeJournal interface {
+	Len() int
+	modTime(i int) int64
+	isVersion(i int) bool
+}
+
+// entriesUpTo returns the number of entries modified at or before t.
+func entriesUpTo(j modTimeJournal, t int64) int {
+	return sort.Search(j.Len(), func(i int) bool { return j.modTime(i) > t })
+}
+
+// versionAt returns the index of the latest version modified at or before t,
+// or -1 if there is none.
+func versionAt(j modTimeJournal, t int64) int {
+	for i := entriesUpTo(j, t) - 1; i >= 0; i-- {
+		if j.isVersion(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// versionsBetween returns the indexes of up to max versions modified in
+// [from, to), starting at index start, and the index to continue from,
+// or -1 when there are no more.
+func versionsBetween(j modTimeJournal, from, to int64, start, max int) (idxs []int, next int) {
+	i := sort.Search(j.Len(), func(i int) bool { return j.modTime(i) >= from })
+	if i < start {
+		i = start
+	}
+	end := entriesUpTo(j, to-1)
+	for ; i < end; i++ {
+		if !j.isVersion(i) {
+			continue
+		}
+		if max > 0 && len(idxs) == max {
+			return idxs, i
+		}
+		idxs = append(idxs, i)
+	}
+	return idxs, -1
+}
+
+type decodedJournal []ObjectMetaV2JournalEntry
+
+func (j decodedJournal) Len() int             { return len(j) }
+func (j decodedJournal) modTime(i int) int64  { return j[i].ModTime() }
+func (j decodedJournal) isVersion(i int) bool { return j[i].IsVersion() }
+
+// SortByModTime sorts the journal by ModTime, oldest first.
+// Entries of the same ModTime keep their order.
+func (z *ObjectMetaV2) SortByModTime() {
+	sort.SliceStable(z.ObjectJournals, func(i, j int) bool {
+		return z.ObjectJournals[i].ModTime() < z.ObjectJournals[j].ModTime()
+	})
+}
+
+// IsSortedByModTime returns whether the journal is sorted by ModTime.
+func (z *ObjectMetaV2) IsSortedByModTime() bool {
+	return sort.SliceIsSorted(z.ObjectJournals, func(i, j int) bool {
+		return z.ObjectJournals[i].ModTime() < z.ObjectJournals[j].ModTime()
+	})
+}
+
+// InsertVersion inserts e after the entries modified at or before it,
+// keeping the journal sorted, and returns its index.
+func (z *ObjectMetaV2) InsertVersion(e ObjectMetaV2JournalEntry) int {
+	i := entriesUpTo(decodedJournal(z.ObjectJournals), e.ModTime())
+	z.ObjectJournals = append(z.ObjectJournals, ObjectMetaV2JournalEntry{})
+	copy(z.ObjectJournals[i+1:], z.ObjectJournals[i:])
+	z.ObjectJournals[i] = e
+	return i
+}
+
+// VersionAt returns the journal index of the version current at t,
+// the latest one modified at or before t, or -1 if there is none.
+// The journal must be sorted by ModTime.
+func (z *ObjectMetaV2) VersionAt(t time.Time) int {
+	return versionAt(decodedJournal(z.ObjectJournals), t.Unix())
+}
+
+// VersionsBetween returns the journal indexes of the versions modified
+// in [from, to), oldest first. At most max indexes are returned when max
+// is positive, starting at journal index start; next is the start of the
+// following page, or -1 after the last one.
+// The journal must be sorted by ModTime.
+func (z *ObjectMetaV2) VersionsBetween(from, to time.Time, start, max int) (idxs []int, next int) {
+	return versionsBetween(decodedJournal(z.ObjectJournals), from.Unix(), to.Unix(), start, max)
+}
+
+// JournalIndex locates the journal entries of serialized metadata
+// without decoding them. It references the serialized metadata,
+// which must not be modified while the index is used.
+type JournalIndex struct {
+	bts      []byte
+	offsets  []int // Start of each entry in bts, followed by the end of the last one.
+	modTimes []int64
+	versions []bool
+}
+
+// NewJournalIndex indexes the journal of serialized metadata.
+// Declared lengths of entries are checked against the size of bts.
+func NewJournalIndex(bts []byte) (*JournalIndex, error) {
+	x := &JournalIndex{bts: bts}
+	start := len(bts)
+	sz, bts, err := msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return nil, msgp.WrapError(err)
+	}
+	found := false
+	for ; sz > 0; sz-- {
+		var field []byte
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return nil, msgp.WrapError(err)
+		}
+		if msgp.UnsafeString(field) != "ojs" {
+			if bts, err = msgp.Skip(bts); err != nil {
+				return nil, msgp.WrapError(err)
+			}
+			continue
+		}
+		var n uint32
+		n, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			return nil, msgp.WrapError(err, "ObjectJournals")
+		}
+		// Every entry takes at least a byte.
+		if uint64(n) > uint64(len(bts)) {
+			return nil, msgp.WrapError(msgp.ErrShortBytes, "ObjectJournals")
+		}
+		found = true
+		x.offsets = make([]int, 0, n+1)
+		x.modTimes = make([]int64, 0, n)
+		x.versions = make([]bool, 0, n)
+		for i := uint32(0); i < n; i++ {
+			x.offsets = append(x.offsets, start-len(bts))
+			entry := bts
+			if bts, err = checkMsgLengths(bts, nil); err != nil {
+				return nil, wrapDecodeError(err, "ObjectJournals")
+			}
+			modTime, version, err := entryModTime(entry[:len(entry)-len(bts)])
+			if err != nil {
+				return nil, msgp.WrapError(err, "ObjectJournals", i)
+			}
+			x.modTimes = append(x.modTimes, modTime)
+			x.versions = append(x.versions, version)
+		}
+		x.offsets = append(x.offsets, start-len(bts))
+	}
+	if !found {
+		return nil, errNoJournal
+	}
+	return x, nil
+}
+
+// entryModTime returns what ModTime and IsVersion return for the serialized
+// journal entry bts, which has been checked.
+func entryModTime(bts []byte) (modTime int64, version bool, err error) {
+	var typ JournalType
+	var present [Multipart + 1]bool
+	var modTimes [Multipart + 1]int64
+	sz, bts, err := msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return 0, false, err
+	}
+	for ; sz > 0; sz-- {
+		var field []byte
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return 0, false, err
+		}
+		var kind JournalType
+		timeField := "mtime"
+		switch msgp.UnsafeString(field) {
+		case "type":
+			var v uint8
+			v, bts, err = msgp.ReadUint8Bytes(bts)
+			if err != nil {
+				return 0, false, err
+			}
+			typ = JournalType(v)
+			continue
+		case "delete":
+			kind = Delete
+		case "object":
+			kind = Object
+		case "link":
+			kind = Link
+		case "mpart":
+			kind, timeField = Multipart, "init"
+		default:
+			if bts, err = msgp.Skip(bts); err != nil {
+				return 0, false, err
+			}
+			continue
+		}
+		if msgp.IsNil(bts) {
+			bts = bts[1:]
+			continue
+		}
+		present[kind] = true
+		if modTimes[kind], bts, err = mapInt64(bts, timeField); err != nil {
+			return 0, false, err
+		}
+	}
+	if typ > Multipart || !present[typ] {
+		return 0, false, nil
+	}
+	return modTimes[typ], typ != Multipart, nil
+}
+
+// mapInt64 returns the integer value of key in the serialized map bts,
+// zero if it is missing, and the bytes after the map.
+func mapInt64(bts []byte, key string) (v int64, o []byte, err error) {
+	sz, bts, err := msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return 0, nil, err
+	}
+	for ; sz > 0; sz-- {
+		var field []byte
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			return 0, nil, err
+		}
+		if msgp.UnsafeString(field) == key {
+			v, bts, err = msgp.ReadInt64Bytes(bts)
+		} else {
+			bts, err = msgp.Skip(bts)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	return v, bts, nil
+}
+
+// Len returns the number of journal entries.
+func (x *JournalIndex) Len() int { return len(x.modTimes) }
+
+func (x *JournalIndex) modTime(i int) int64  { return x.modTimes[i] }
+func (x *JournalIndex) isVersion(i int) bool { return x.versions[i] }
+
+// ModTime returns the ModTime of entry i.
+func (x *JournalIndex) ModTime(i int) time.Time { return time.Unix(x.modTimes[i], 0) }
+
+// IsSortedByModTime returns whether the journal is sorted by ModTime.
+func (x *JournalIndex) IsSortedByModTime() bool {
+	return sort.SliceIsSorted(x.modTimes, func(i, j int) bool { return x.modTimes[i] < x.modTimes[j] })
+}
+
+// Entry decodes entry i into dst if it is not nil.
+func (x *JournalIndex) Entry(i int, dst *ObjectMetaV2JournalEntry) (*ObjectMetaV2JournalEntry, error) {
+	if i < 0 || i >= x.Len() {
+		return nil, msgp.WrapError(errors.New("requested object index not found"), "ObjectJournals", i)
+	}
+	if dst == nil {
+		dst = &ObjectMetaV2JournalEntry{}
+	}
+	if _, err := dst.UnmarshalMsg(x.bts[x.offsets[i]:x.offsets[i+1]]); err != nil {
+		return nil, msgp.WrapError(err, "ObjectJournals", i)
+	}
+	return dst, nil
+}
+
+// VersionAt is ObjectMetaV2.VersionAt on the serialized journal.
+func (x *JournalIndex) VersionAt(t time.Time) int {
+	return versionAt(x, t.Unix())
+}
+
+// VersionsBetween is ObjectMetaV2.VersionsBetween on the serialized journal.
+func (x *JournalIndex) VersionsBetween(from, to time.Time, start, max int) (idxs []int, next int) {
+	return versionsBetween(x, from.Unix(), to.Unix(), start, max)
+}
@@ -0,0 +1,256 @@
+package xlmeta
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// modTimeJournalSample returns a sorted journal of objects, delete markers,
+// links and multipart uploads, with several entries sharing a ModTime.
+func modTimeJournalSample() ObjectMetaV2 {
+	z := ObjectMetaV2{Version: 200, Format: XL}
+	obj := func(id uint64, t int64) ObjectMetaV2JournalEntry {
+		o := newObjectMetaV2Object(2)
+		o.VersionID, o.StatModTime = id, t
+		return ObjectMetaV2JournalEntry{Type: Object, Object: o}
+	}
+	link := obj(7, 30)
+	link.Type, link.Link, link.Object = Link, (*ObjectMetaV2Link)(link.Object), nil
+	z.ObjectJournals = []ObjectMetaV2JournalEntry{
+		obj(1, 10),
+		obj(2, 20),
+		{Type: Multipart, Multipart: &ObjectMetaV2Multipart{UploadID: "upload", Initiated: 20}},
+		obj(3, 20),
+		{Type: Multipart, Multipart: &ObjectMetaV2Multipart{UploadID: "upload-2", Initiated: 25}},
+		link,
+		{Type: Delete, DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: 4, ModTime: 40}},
+		obj(5, 50),
+	}
+	return z
+}
+
+// linearVersionAt is VersionAt by scanning the journal.
+func linearVersionAt(z *ObjectMetaV2, t int64) int {
+	idx := -1
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		if e.IsVersion() && e.ModTime() <= t {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// linearVersionsBetween is VersionsBetween by scanning the journal.
+func linearVersionsBetween(z *ObjectMetaV2, from, to int64) []int {
+	var idxs []int
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		if e.IsVersion() && e.ModTime() >= from && e.ModTime() < to {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+func TestSortByModTime(t *testing.T) {
+	want := modTimeJournalSample()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		z := modTimeJournalSample()
+		rng.Shuffle(len(z.ObjectJournals), func(i, j int) {
+			z.ObjectJournals[i], z.ObjectJournals[j] = z.ObjectJournals[j], z.ObjectJournals[i]
+		})
+		var inserted ObjectMetaV2
+		for _, e := range z.ObjectJournals {
+			inserted.InsertVersion(e)
+		}
+		if !inserted.IsSortedByModTime() {
+			t.Fatal("InsertVersion did not keep the journal sorted")
+		}
+		z.SortByModTime()
+		if !z.IsSortedByModTime() {
+			t.Fatal("journal not sorted")
+		}
+		for j, e := range z.ObjectJournals {
+			if e.ModTime() != want.ObjectJournals[j].ModTime() || inserted.ObjectJournals[j].ModTime() != e.ModTime() {
+				t.Fatalf("entry %d: ModTime %d, want %d", j, e.ModTime(), want.ObjectJournals[j].ModTime())
+			}
+		}
+	}
+
+	// Entries of the same ModTime keep their order.
+	z := modTimeJournalSample()
+	e := z.ObjectJournals[1]
+	e.Object = &ObjectMetaV2Object{VersionID: 6, StatModTime: 20}
+	if i := z.InsertVersion(e); i != 4 {
+		t.Errorf("version inserted at %d, want 4", i)
+	}
+	z.SortByModTime()
+	if id := z.ObjectJournals[4].VersionID(); id != 6 {
+		t.Errorf("sorting reordered entries of the same ModTime: version %d at index 4", id)
+	}
+}
+
+// TestJournalWriters writes versions out of ModTime order through every
+// writer of the journal, which must all keep it sorted.
+func TestJournalWriters(t *testing.T) {
+	z := ObjectMetaV2{Version: 200, Format: XL}
+	obj := newObjectMetaV2Object(2)
+	obj.VersionID, obj.StatModTime = 1, 30
+	z.InsertVersion(ObjectMetaV2JournalEntry{Type: Object, Object: obj})
+	z.AddDeleteMarker(2, time.Unix(10, 0))
+	if err := z.NewMultipartUpload("upload", *newObjectMetaV2Object(0), time.Unix(20, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.NewMultipartUpload("upload-2", *newObjectMetaV2Object(0), time.Unix(40, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.AddMultipartPart("upload", 1, 10, testPartETag(1), time.Unix(20, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.CompleteMultipartUpload("upload", []CompletePart{{1, testPartETag(1)}}, 3, time.Unix(25, 0)); err != nil {
+		t.Fatal(err)
+	}
+	z.AddDeleteMarker(4, time.Unix(5, 0))
+	z.AddDeleteMarker(5, time.Unix(30, 0))
+
+	var ids []uint64
+	for i := range z.ObjectJournals {
+		ids = append(ids, z.ObjectJournals[i].VersionID())
+	}
+	if want := []uint64{4, 2, 3, 1, 5, 0}; !reflect.DeepEqual(ids, want) || !z.IsSortedByModTime() {
+		t.Fatalf("journal versions %v, want %v sorted by ModTime", ids, want)
+	}
+	for sec := int64(0); sec <= 50; sec++ {
+		if got, want := z.VersionAt(time.Unix(sec, 0)), linearVersionAt(&z, sec); got != want {
+			t.Errorf("version at %d: got %d, want %d", sec, got, want)
+		}
+		for to := sec; to <= 50; to += 5 {
+			got, _ := z.VersionsBetween(time.Unix(sec, 0), time.Unix(to, 0), 0, 0)
+			if want := linearVersionsBetween(&z, sec, to); !reflect.DeepEqual(got, want) {
+				t.Errorf("versions in [%d, %d): got %v, want %v", sec, to, got, want)
+			}
+		}
+	}
+}
+
+func TestVersionAt(t *testing.T) {
+	z := modTimeJournalSample()
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := NewJournalIndex(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Len() != len(z.ObjectJournals) || !x.IsSortedByModTime() {
+		t.Fatalf("index of %d entries, want %d sorted ones", x.Len(), len(z.ObjectJournals))
+	}
+	for i := range z.ObjectJournals {
+		if x.ModTime(i).Unix() != z.ObjectJournals[i].ModTime() || x.isVersion(i) != z.ObjectJournals[i].IsVersion() {
+			t.Errorf("entry %d: indexed ModTime %d version %v", i, x.modTime(i), x.isVersion(i))
+		}
+	}
+
+	for sec := int64(0); sec <= 60; sec++ {
+		want := linearVersionAt(&z, sec)
+		if got := z.VersionAt(time.Unix(sec, 0)); got != want {
+			t.Errorf("decoded version at %d: got %d, want %d", sec, got, want)
+		}
+		if got := x.VersionAt(time.Unix(sec, 0)); got != want {
+			t.Errorf("serialized version at %d: got %d, want %d", sec, got, want)
+		}
+	}
+}
+
+func TestVersionsBetween(t *testing.T) {
+	z := modTimeJournalSample()
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := NewJournalIndex(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type lister func(from, to time.Time, start, max int) ([]int, int)
+	for name, list := range map[string]lister{"decoded": z.VersionsBetween, "serialized": x.VersionsBetween} {
+		for from := int64(0); from <= 60; from += 5 {
+			for to := from; to <= 60; to += 5 {
+				want := linearVersionsBetween(&z, from, to)
+				for max := 0; max <= 3; max++ {
+					var got []int
+					start, pages := 0, 0
+					for start >= 0 {
+						var page []int
+						page, start = list(time.Unix(from, 0), time.Unix(to, 0), start, max)
+						if max > 0 && len(page) > max {
+							t.Fatalf("%s: page of %d versions, max %d", name, len(page), max)
+						}
+						got = append(got, page...)
+						if pages++; pages > len(z.ObjectJournals)+1 {
+							t.Fatalf("%s: pagination does not end", name)
+						}
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("%s: versions in [%d, %d) by %d: got %v, want %v", name, from, to, max, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestJournalIndexEntry(t *testing.T) {
+	z := DefaultWorkload.Generate(20)
+	if !z.IsSortedByModTime() {
+		t.Fatal("workload journal not sorted")
+	}
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := NewJournalIndex(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range z.ObjectJournals {
+		e, err := x.Entry(i, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{*e}}
+		want := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{z.ObjectJournals[i]}}
+		if !equalObjectMetaV2(&got, &want) {
+			t.Errorf("entry %d differs", i)
+		}
+	}
+	if _, err := x.Entry(len(z.ObjectJournals), nil); err == nil {
+		t.Error("entry past the end decoded")
+	}
+
+	if _, err := NewJournalIndex(buf[:len(buf)-10]); err == nil {
+		t.Error("truncated metadata indexed")
+	}
+	var empty ObjectMetaV2
+	if buf, err = empty.MarshalMsg(nil); err != nil {
+		t.Fatal(err)
+	}
+	if x, err = NewJournalIndex(buf); err != nil || x.Len() != 0 || x.VersionAt(time.Now()) != -1 {
+		t.Errorf("empty journal: %v", err)
+	}
+	if _, err := NewJournalIndex([]byte{0x81, 0xa1, 'v', 0x01}); !errors.Is(err, errNoJournal) {
+		t.Errorf("want %v, got %v", errNoJournal, err)
+	}
+	for _, seed := range fuzzSeeds(t) {
+		NewJournalIndex(seed)
+	}
+	if _, err := NewJournalIndex(hostileMsg()); err == nil {
+		t.Error("hostile metadata indexed")
+	}
+}
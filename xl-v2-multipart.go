@@ -0,0 +1,160 @@
+package xlmeta
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// minPartSize is the smallest size allowed for any part but the last.
+	minPartSize = 5 << 20
+	// maxPartNumber is the highest part number of a multipart upload.
+	maxPartNumber = 10000
+)
+
+var (
+	errUploadExists     = errors.New("multipart upload already exists")
+	errUploadNotFound   = errors.New("multipart upload not found")
+	errInvalidPart      = errors.New("part not uploaded or etag mismatch")
+	errInvalidPartOrder = errors.New("parts not in ascending order")
+	errInvalidPartNum   = errors.New("part number out of range")
+	errPartTooSmall     = errors.New("part smaller than the minimum allowed size")
+)
+
+// CompletePart identifies an uploaded part when completing a multipart upload.
+type CompletePart struct {
+	Number int
+	ETag   string
+}
+
+// multipartUpload returns the journal index of uploadID, or -1.
+func (z *ObjectMetaV2) multipartUpload(uploadID string) int {
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		if e.Type == Multipart && e.Multipart != nil && e.Multipart.UploadID == uploadID {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewMultipartUpload records a new multipart upload in the journal,
+// inserted by its initiation time.
+// obj describes the object the upload completes into; its part information is ignored.
+func (z *ObjectMetaV2) NewMultipartUpload(uploadID string, obj ObjectMetaV2Object, initiated time.Time) error {
+	if z.multipartUpload(uploadID) >= 0 {
+		return errUploadExists
+	}
+	obj.DataPartInfoNumbers = nil
+	obj.DataPartInfoSizes = nil
+	obj.DataPartInfoChecksums = nil
+	obj.Inline = nil
+	z.InsertVersion(ObjectMetaV2JournalEntry{
+		Type: Multipart,
+		Multipart: &ObjectMetaV2Multipart{
+			UploadID:  uploadID,
+			Initiated: initiated.Unix(),
+			Object:    obj,
+		},
+	})
+	return nil
+}
+
+// AddMultipartPart records an uploaded part.
+// Uploading a part number again replaces the previous part.
+func (z *ObjectMetaV2) AddMultipartPart(uploadID string, number, size int, etag string, modTime time.Time) error {
+	if number < 1 || number > maxPartNumber {
+		return errInvalidPartNum
+	}
+	idx := z.multipartUpload(uploadID)
+	if idx < 0 {
+		return errUploadNotFound
+	}
+	mp := z.ObjectJournals[idx].Multipart
+	part := ObjectMetaV2MultipartPart{
+		Number:  number,
+		Size:    size,
+		ETag:    etag,
+		ModTime: modTime.Unix(),
+	}
+	i := sort.Search(len(mp.Parts), func(i int) bool { return mp.Parts[i].Number >= number })
+	if i < len(mp.Parts) && mp.Parts[i].Number == number {
+		mp.Parts[i] = part
+		return nil
+	}
+	mp.Parts = append(mp.Parts, ObjectMetaV2MultipartPart{})
+	copy(mp.Parts[i+1:], mp.Parts[i:])
+	mp.Parts[i] = part
+	return nil
+}
+
+// AbortMultipartUpload removes uploadID and its parts from the journal.
+func (z *ObjectMetaV2) AbortMultipartUpload(uploadID string) error {
+	idx := z.multipartUpload(uploadID)
+	if idx < 0 {
+		return errUploadNotFound
+	}
+	z.ObjectJournals = append(z.ObjectJournals[:idx], z.ObjectJournals[idx+1:]...)
+	return nil
+}
+
+// CompleteMultipartUpload replaces uploadID with a new object version made of parts,
+// which must be in ascending part number order.
+// Uploaded parts not listed in parts are discarded.
+// The new version is inserted in the journal by modTime and returned.
+func (z *ObjectMetaV2) CompleteMultipartUpload(uploadID string, parts []CompletePart, versionID uint64, modTime time.Time) (*ObjectMetaV2Object, error) {
+	idx := z.multipartUpload(uploadID)
+	if idx < 0 {
+		return nil, errUploadNotFound
+	}
+	mp := z.ObjectJournals[idx].Multipart
+	if len(parts) == 0 {
+		return nil, errInvalidPart
+	}
+
+	obj := mp.Object
+	obj.VersionID = versionID
+	obj.DataPartInfoNumbers = make(DeltaEncodedInt, len(parts))
+	obj.DataPartInfoSizes = make(DeltaEncodedInt, len(parts))
+	obj.StatSize = 0
+	obj.StatModTime = modTime.Unix()
+	etags := md5.New()
+	for i, p := range parts {
+		if i > 0 && p.Number <= parts[i-1].Number {
+			return nil, errInvalidPartOrder
+		}
+		j := sort.Search(len(mp.Parts), func(j int) bool { return mp.Parts[j].Number >= p.Number })
+		if j == len(mp.Parts) || mp.Parts[j].Number != p.Number || mp.Parts[j].ETag != p.ETag {
+			return nil, fmt.Errorf("%w: part %d", errInvalidPart, p.Number)
+		}
+		uploaded := mp.Parts[j]
+		if i < len(parts)-1 && uploaded.Size < minPartSize {
+			return nil, fmt.Errorf("%w: part %d", errPartTooSmall, p.Number)
+		}
+		sum, err := hex.DecodeString(uploaded.ETag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: part %d: %v", errInvalidPart, p.Number, err)
+		}
+		etags.Write(sum)
+		obj.DataPartInfoNumbers[i] = uploaded.Number
+		obj.DataPartInfoSizes[i] = uploaded.Size
+		obj.StatSize += uploaded.Size
+	}
+
+	obj.MetaUser = make(map[string][]string, len(mp.Object.MetaUser)+1)
+	for k, v := range mp.Object.MetaUser {
+		obj.MetaUser[k] = v
+	}
+	obj.MetaUser["etag"] = []string{fmt.Sprintf("%x-%d", etags.Sum(nil), len(parts))}
+
+	z.ObjectJournals = append(z.ObjectJournals[:idx], z.ObjectJournals[idx+1:]...)
+	z.InsertVersion(ObjectMetaV2JournalEntry{
+		Type:   Object,
+		Object: &obj,
+	})
+	return &obj, nil
+}
@@ -0,0 +1,189 @@
+package xlmeta
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+func testPartETag(number int) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprint(number))))
+}
+
+func TestObjectMetaV2MultipartUpload(t *testing.T) {
+	initiated := time.Unix(1000, 0)
+	xlmeta := getSampleObjectMetaV2(1, 2)
+	for i, e := range xlmeta.ObjectJournals {
+		e.Object.StatModTime = int64(i + 1)
+	}
+	template := *newObjectMetaV2Object(0)
+	if err := xlmeta.NewMultipartUpload("upload-1", template, initiated); err != nil {
+		t.Fatal(err)
+	}
+	if err := xlmeta.NewMultipartUpload("upload-1", template, initiated); err != errUploadExists {
+		t.Fatalf("want %v, got %v", errUploadExists, err)
+	}
+	if err := xlmeta.AddMultipartPart("upload-1", maxPartNumber+1, 1, testPartETag(1), initiated); err != errInvalidPartNum {
+		t.Fatalf("want %v, got %v", errInvalidPartNum, err)
+	}
+	for _, number := range []int{5, 1, 3, 3} {
+		if err := xlmeta.AddMultipartPart("upload-1", number, minPartSize+number, testPartETag(number), initiated); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The in-progress upload must survive a round trip.
+	buf, err := xlmeta.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ObjectMetaV2
+	if _, err = decoded.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	mp := decoded.ObjectJournals[2].Multipart
+	if decoded.ObjectJournals[2].Type != Multipart || mp.UploadID != "upload-1" || mp.Initiated != 1000 {
+		t.Fatalf("unexpected upload %+v", mp)
+	}
+	if len(mp.Parts) != 3 || mp.Parts[0].Number != 1 || mp.Parts[1].Number != 3 || mp.Parts[2].Number != 5 {
+		t.Fatalf("unexpected parts %+v", mp.Parts)
+	}
+
+	complete := []CompletePart{{1, testPartETag(1)}, {3, testPartETag(3)}}
+	if _, err = decoded.CompleteMultipartUpload("upload-1", []CompletePart{complete[1], complete[0]}, 7, initiated); err != errInvalidPartOrder {
+		t.Fatalf("want %v, got %v", errInvalidPartOrder, err)
+	}
+	if _, err = decoded.CompleteMultipartUpload("upload-1", []CompletePart{{1, testPartETag(2)}}, 7, initiated); !errors.Is(err, errInvalidPart) {
+		t.Fatalf("want %v, got %v", errInvalidPart, err)
+	}
+	if err = decoded.AddMultipartPart("upload-1", 2, 10, testPartETag(2), initiated); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = decoded.CompleteMultipartUpload("upload-1", []CompletePart{{2, testPartETag(2)}, {3, testPartETag(3)}}, 7, initiated); !errors.Is(err, errPartTooSmall) {
+		t.Fatalf("want %v, got %v", errPartTooSmall, err)
+	}
+
+	obj, err := decoded.CompleteMultipartUpload("upload-1", complete, 7, time.Unix(2000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.ObjectJournals) != 3 || decoded.ObjectJournals[2].Object != obj || decoded.multipartUpload("upload-1") >= 0 {
+		t.Fatalf("completed version not inserted: %+v", decoded.ObjectJournals)
+	}
+	if obj.VersionID != 7 || obj.StatSize != 2*minPartSize+4 || obj.StatModTime != 2000 || obj.DataDir != template.DataDir {
+		t.Fatalf("unexpected object %+v", obj)
+	}
+	etags := md5.New()
+	for _, p := range complete {
+		sum := md5.Sum([]byte(fmt.Sprint(p.Number)))
+		etags.Write(sum[:])
+	}
+	if etag := obj.MetaUser["etag"][0]; etag != fmt.Sprintf("%x-2", etags.Sum(nil)) {
+		t.Fatalf("unexpected etag %s", etag)
+	}
+
+	// Part numbers and sizes are stored as deltas and must decode to the original values.
+	buf, err = obj.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var completed ObjectMetaV2Object
+	if _, err = completed.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(completed.DataPartInfoNumbers, DeltaEncodedInt{1, 3}) ||
+		!reflect.DeepEqual(completed.DataPartInfoSizes, DeltaEncodedInt{minPartSize + 1, minPartSize + 3}) {
+		t.Fatalf("unexpected parts %v %v", completed.DataPartInfoNumbers, completed.DataPartInfoSizes)
+	}
+	pnum, err := obj.DataPartInfoNumbers.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deltas []int
+	sz, pnum, err := msgp.ReadArrayHeaderBytes(pnum)
+	for i := uint32(0); err == nil && i < sz; i++ {
+		var v int
+		v, pnum, err = msgp.ReadIntBytes(pnum)
+		deltas = append(deltas, v)
+	}
+	if err != nil || !reflect.DeepEqual(deltas, []int{1, 2}) {
+		t.Fatalf("unexpected deltas %v: %v", deltas, err)
+	}
+}
+
+func TestObjectMetaV2AbortMultipartUpload(t *testing.T) {
+	var xlmeta ObjectMetaV2
+	if err := xlmeta.AbortMultipartUpload("upload-1"); err != errUploadNotFound {
+		t.Fatalf("want %v, got %v", errUploadNotFound, err)
+	}
+	if err := xlmeta.NewMultipartUpload("upload-1", ObjectMetaV2Object{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := xlmeta.AddMultipartPart("upload-1", 1, 1, testPartETag(1), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := xlmeta.AbortMultipartUpload("upload-1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(xlmeta.ObjectJournals) != 0 {
+		t.Fatalf("upload not removed: %+v", xlmeta.ObjectJournals)
+	}
+	if err := xlmeta.AddMultipartPart("upload-1", 1, 1, testPartETag(1), time.Now()); err != errUploadNotFound {
+		t.Fatalf("want %v, got %v", errUploadNotFound, err)
+	}
+}
+
+func TestGetJournalEntryNSkipsUploads(t *testing.T) {
+	xlmeta := getSampleObjectMetaV2(1, 2)
+	for i, e := range xlmeta.ObjectJournals {
+		e.Object.VersionID, e.Object.StatModTime = uint64(i+1), int64(i+1)
+	}
+	template := *newObjectMetaV2Object(0)
+	for _, upload := range []struct {
+		id        string
+		initiated int64
+	}{{"upload-1", 1}, {"upload-2", 10}, {"upload-3", 11}} {
+		if err := xlmeta.NewMultipartUpload(upload.id, template, time.Unix(upload.initiated, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if last := xlmeta.ObjectJournals[len(xlmeta.ObjectJournals)-1]; last.Type != Multipart {
+		t.Fatalf("journal ends with a %d entry, not an upload", last.Type)
+	}
+	buf, err := xlmeta.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n, want := range map[int]uint64{-1: 2, 0: 1, 1: 2} {
+		var z ObjectMetaV2
+		e, err := z.GetJournalEntryN(buf, n, nil)
+		if err != nil {
+			t.Fatalf("version %d: %v", n, err)
+		}
+		if !e.IsVersion() || e.VersionID() != want {
+			t.Errorf("version %d: got %d entry %d, want version %d", n, e.Type, e.VersionID(), want)
+		}
+	}
+	var z ObjectMetaV2
+	if _, err := z.GetJournalEntryN(buf, 2, nil); err == nil {
+		t.Error("version past the last one decoded")
+	}
+
+	// Without versions, there is no latest version.
+	var uploads ObjectMetaV2
+	if err := uploads.NewMultipartUpload("upload-1", template, time.Unix(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if buf, err = uploads.MarshalMsg(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.GetJournalEntryN(buf, -1, nil); err == nil {
+		t.Error("upload decoded as the latest version")
+	}
+}
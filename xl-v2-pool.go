@@ -0,0 +1,270 @@
+package xlmeta
+
+import (
+	"sync"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+var objectMetaV2Pool = sync.Pool{
+	New: func() interface{} {
+		return &ObjectMetaV2{}
+	},
+}
+
+// GetObjectMetaV2 returns an empty ObjectMetaV2 from the pool.
+// Fill it with UnmarshalMsgReuse and return it with PutObjectMetaV2.
+func GetObjectMetaV2() *ObjectMetaV2 {
+	return objectMetaV2Pool.Get().(*ObjectMetaV2)
+}
+
+// PutObjectMetaV2 resets z and returns it to the pool.
+// z and anything obtained from it must not be used after this call.
+func PutObjectMetaV2(z *ObjectMetaV2) {
+	if z == nil {
+		return
+	}
+	z.Reset()
+	objectMetaV2Pool.Put(z)
+}
+
+// Reset clears z so it is equivalent to a zero value.
+// The journal capacity is kept and the objects of the removed
+// entries are emptied and kept for reuse by UnmarshalMsgReuse.
+func (z *ObjectMetaV2) Reset() {
+	z.releaseJournals()
+	*z = ObjectMetaV2{
+		ObjectJournals: z.ObjectJournals,
+		free:           z.free,
+	}
+}
+
+// releaseJournals empties the journal, keeping its objects on the free list.
+func (z *ObjectMetaV2) releaseJournals() {
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		if e.Object != nil {
+			e.Object.Reset()
+			z.free = append(z.free, e.Object)
+		}
+		if e.Link != nil {
+			obj := (*ObjectMetaV2Object)(e.Link)
+			obj.Reset()
+			z.free = append(z.free, obj)
+		}
+		*e = ObjectMetaV2JournalEntry{}
+	}
+	z.ObjectJournals = z.ObjectJournals[:0]
+}
+
+// Reset clears z while keeping the capacity of the erasure distribution,
+// the part slices and the metadata maps.
+func (z *ObjectMetaV2Object) Reset() {
+	for key := range z.MetaSys {
+		delete(z.MetaSys, key)
+	}
+	for key := range z.MetaUser {
+		delete(z.MetaUser, key)
+	}
+	*z = ObjectMetaV2Object{
+		DataErasureDistribution: z.DataErasureDistribution[:0],
+		DataPartInfoNumbers:     z.DataPartInfoNumbers[:0],
+		DataPartInfoSizes:       z.DataPartInfoSizes[:0],
+		MetaSys:                 z.MetaSys,
+		MetaUser:                z.MetaUser,
+	}
+}
+
+// newObject returns an empty object, reusing one released by Reset if possible.
+func (z *ObjectMetaV2) newObject() *ObjectMetaV2Object {
+	if n := len(z.free); n > 0 {
+		obj := z.free[n-1]
+		z.free[n-1] = nil
+		z.free = z.free[:n-1]
+		return obj
+	}
+	return new(ObjectMetaV2Object)
+}
+
+// UnmarshalMsgReuse is UnmarshalMsg for values that have been Reset,
+// typically obtained from GetObjectMetaV2.
+// Journal entries, objects, part slices and metadata maps released
+// by Reset are reused instead of allocated.
+// Metadata maps that are absent from bts may be left empty instead of nil.
+func (z *ObjectMetaV2) UnmarshalMsgReuse(bts []byte) (o []byte, err error) {
+	if _, err = checkMsgLengths(bts, nil); err != nil {
+		err = wrapDecodeError(err)
+		return
+	}
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "v":
+			z.Version, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Version")
+				return
+			}
+		case "fmt":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Format")
+					return
+				}
+				z.Format = Format(zb0002)
+			}
+		case "ojs":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ObjectJournals")
+				return
+			}
+			z.releaseJournals()
+			if cap(z.ObjectJournals) >= int(zb0003) {
+				z.ObjectJournals = (z.ObjectJournals)[:zb0003]
+			} else {
+				z.ObjectJournals = make([]ObjectMetaV2JournalEntry, zb0003)
+			}
+			for za0001 := range z.ObjectJournals {
+				bts, err = z.unmarshalJournalEntryReuse(&z.ObjectJournals[za0001], bts)
+				if err != nil {
+					err = msgp.WrapError(err, "ObjectJournals", za0001)
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// unmarshalJournalEntryReuse decodes a single journal entry into the zero value e,
+// taking objects from the free list of z.
+func (z *ObjectMetaV2) unmarshalJournalEntryReuse(e *ObjectMetaV2JournalEntry, bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "type":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Type")
+					return
+				}
+				e.Type = JournalType(zb0002)
+			}
+		case "delete":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				e.DeleteMarker = nil
+			} else {
+				if e.DeleteMarker == nil {
+					e.DeleteMarker = new(ObjectMetaV2DeleteMarker)
+				}
+				bts, err = e.DeleteMarker.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "DeleteMarker")
+					return
+				}
+			}
+		case "object":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				e.Object = nil
+			} else {
+				if e.Object == nil {
+					e.Object = z.newObject()
+				}
+				bts, err = e.Object.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Object")
+					return
+				}
+			}
+		case "link":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				e.Link = nil
+			} else {
+				if e.Link == nil {
+					e.Link = (*ObjectMetaV2Link)(z.newObject())
+				}
+				bts, err = e.Link.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Link")
+					return
+				}
+			}
+		case "mpart":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				e.Multipart = nil
+			} else {
+				if e.Multipart == nil {
+					e.Multipart = new(ObjectMetaV2Multipart)
+				}
+				bts, err = e.Multipart.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Multipart")
+					return
+				}
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
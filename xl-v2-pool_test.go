@@ -0,0 +1,71 @@
+package xlmeta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObjectMetaV2UnmarshalMsgReuse(t *testing.T) {
+	first := getSampleObjectMetaV2(10, 5)
+	firstBuf, err := first.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := getSampleObjectMetaV2(3, 4)
+	second.ObjectJournals[1] = ObjectMetaV2JournalEntry{
+		Type:         Delete,
+		DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: 1, ModTime: 2},
+	}
+	second.ObjectJournals[2].Object.MetaSys = nil
+	secondBuf, err := second.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want ObjectMetaV2
+	if _, err = want.UnmarshalMsg(secondBuf); err != nil {
+		t.Fatal(err)
+	}
+
+	z := GetObjectMetaV2()
+	defer PutObjectMetaV2(z)
+	if _, err = z.UnmarshalMsgReuse(firstBuf); err != nil {
+		t.Fatal(err)
+	}
+	z.Reset()
+	if !reflect.DeepEqual(z.ObjectJournals, []ObjectMetaV2JournalEntry{}) || z.Version != 0 {
+		t.Fatalf("Reset left state behind: %+v", z)
+	}
+	if _, err = z.UnmarshalMsgReuse(secondBuf); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(z.ObjectJournals) != len(want.ObjectJournals) {
+		t.Fatalf("got %d journals, want %d", len(z.ObjectJournals), len(want.ObjectJournals))
+	}
+	for i := range want.ObjectJournals {
+		got, exp := z.ObjectJournals[i], want.ObjectJournals[i]
+		if got.Type != exp.Type || !reflect.DeepEqual(got.DeleteMarker, exp.DeleteMarker) || (got.Object == nil) != (exp.Object == nil) || got.Link != nil {
+			t.Fatalf("journal %d: got %+v, want %+v", i, got, exp)
+		}
+		if exp.Object == nil {
+			continue
+		}
+		if len(got.Object.MetaSys) != len(exp.Object.MetaSys) {
+			t.Fatalf("journal %d: got MetaSys %v, want %v", i, got.Object.MetaSys, exp.Object.MetaSys)
+		}
+		gotObj, expObj := *got.Object, *exp.Object
+		gotObj.MetaSys, expObj.MetaSys = nil, nil
+		if !reflect.DeepEqual(gotObj, expObj) {
+			t.Fatalf("journal %d: got %+v, want %+v", i, gotObj, expObj)
+		}
+	}
+
+	// Decoding again without Reset must not leak the previous journals.
+	if _, err = z.UnmarshalMsgReuse(firstBuf); err != nil {
+		t.Fatal(err)
+	}
+	if len(z.ObjectJournals) != 5 || len(z.ObjectJournals[4].Object.DataPartInfoNumbers) != 10 {
+		t.Fatalf("unexpected journal after re-decode: %d", len(z.ObjectJournals))
+	}
+}
@@ -0,0 +1,382 @@
+package xlmeta
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Generate implements quick.Generator.
+func (ObjectMetaV2) Generate(rng *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(randomObjectMetaV2(rng, size))
+}
+
+// randomObjectMetaV2 returns valid metadata with up to size/5+1 journal entries
+// of every type. Objects have up to size parts, and occasionally many more.
+func randomObjectMetaV2(rng *rand.Rand, size int) ObjectMetaV2 {
+	z := ObjectMetaV2{Version: 200, Format: XL}
+	n := rng.Intn(size/5 + 2)
+	if n == 0 {
+		return z
+	}
+	z.ObjectJournals = make([]ObjectMetaV2JournalEntry, n)
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		switch rng.Intn(6) {
+		case 0:
+			e.Type = Delete
+			e.DeleteMarker = &ObjectMetaV2DeleteMarker{VersionID: rng.Uint64(), ModTime: randomModTime(rng), Replication: randomReplication(rng)}
+		case 1:
+			e.Type = Link
+			e.Link = (*ObjectMetaV2Link)(randomObjectMetaV2Object(rng, size))
+		case 2:
+			e.Type = Multipart
+			e.Multipart = randomObjectMetaV2Multipart(rng, size)
+		default:
+			e.Type = Object
+			e.Object = randomObjectMetaV2Object(rng, size)
+		}
+	}
+	return z
+}
+
+// randomModTime returns a time in seconds around the epoch, possibly negative.
+func randomModTime(rng *rand.Rand) int64 {
+	return rng.Int63n(1<<40) - 1<<39
+}
+
+func randomBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
+
+func randomString(rng *rand.Rand, n int) string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789-_/.é世"
+	r := []rune(chars)
+	s := make([]rune, n)
+	for i := range s {
+		s[i] = r[rng.Intn(len(r))]
+	}
+	return string(s)
+}
+
+// randomReplication returns the state of up to 4 targets sorted by ARN, or none.
+func randomReplication(rng *rand.Rand) []ObjectMetaV2Replication {
+	if rng.Intn(3) != 0 {
+		return nil
+	}
+	arns := make(map[string]bool)
+	for i := rng.Intn(4); i >= 0; i-- {
+		arns[randomString(rng, rng.Intn(30)+1)] = true
+	}
+	var targets []ObjectMetaV2Replication
+	for arn := range arns {
+		targets = append(targets, ObjectMetaV2Replication{
+			ARN:     arn,
+			Status:  ReplicationStatus(rng.Intn(4)),
+			ModTime: randomModTime(rng),
+			Resync:  randomString(rng, rng.Intn(2)*10),
+		})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].ARN < targets[j].ARN })
+	return targets
+}
+
+// randomPartCount returns up to size parts, and up to maxPartNumber parts once in 20.
+func randomPartCount(rng *rand.Rand, size int) int {
+	if rng.Intn(20) == 0 {
+		return rng.Intn(maxPartNumber) + 1
+	}
+	return rng.Intn(size + 1)
+}
+
+func randomObjectMetaV2Object(rng *rand.Rand, size int) *ObjectMetaV2Object {
+	m := rng.Intn(16) + 1
+	n := rng.Intn(m + 1)
+	obj := &ObjectMetaV2Object{
+		VersionID:               rng.Uint64(),
+		DataDir:                 rng.Uint64(),
+		DataErasureAlgorithm:    ReedSolomon,
+		DataErasureM:            m,
+		DataErasureN:            n,
+		DataErasureBlockSize:    1 << uint(rng.Intn(24)+1),
+		DataErasureIndex:        rng.Intn(m+n) + 1,
+		DataErasureChecksumAlgo: ChecksumAlgo(rng.Intn(3)),
+		StatModTime:             randomModTime(rng),
+	}
+	obj.DataErasureDistribution = make([]uint8, m+n)
+	for i, shard := range rng.Perm(m + n) {
+		obj.DataErasureDistribution[i] = uint8(shard + 1)
+	}
+
+	if rng.Intn(10) == 0 {
+		if err := obj.SetInlineData(randomBytes(rng, rng.Intn(1000)+1)); err != nil {
+			panic(err)
+		}
+	} else if parts := randomPartCount(rng, size); parts > 0 {
+		obj.DataPartInfoNumbers = make(DeltaEncodedInt, parts)
+		obj.DataPartInfoSizes = make(DeltaEncodedInt, parts)
+		number := 0
+		for i := 0; i < parts; i++ {
+			number += rng.Intn(3) + 1
+			obj.DataPartInfoNumbers[i] = number
+			obj.DataPartInfoSizes[i] = rng.Intn(5 << 30)
+			obj.StatSize += obj.DataPartInfoSizes[i]
+		}
+		if rng.Intn(2) == 0 {
+			obj.DataPartInfoChecksums = make([][]byte, parts)
+			for i := range obj.DataPartInfoChecksums {
+				obj.DataPartInfoChecksums[i] = randomBytes(rng, 32)
+			}
+		}
+	}
+
+	if rng.Intn(4) == 0 {
+		obj.RetentionMode = RetentionMode(rng.Intn(3))
+		obj.RetainUntil = randomModTime(rng)
+		obj.LegalHold = rng.Intn(2) == 0
+	}
+	obj.Replication = randomReplication(rng)
+	if !obj.IsInline() && rng.Intn(4) == 0 {
+		sse := &ObjectMetaV2SSE{
+			Type:      SSEType(rng.Intn(3) + 1),
+			Algorithm: "DAREv2-HMAC-SHA256",
+			SealedKey: randomBytes(rng, 64),
+			IV:        randomBytes(rng, 32),
+		}
+		if sse.Type != SSEC {
+			sse.KeyID = randomString(rng, 10)
+			sse.DataKey = randomBytes(rng, 48)
+		}
+		if sse.Type == SSEKMS && rng.Intn(2) == 0 {
+			sse.Context = randomBytes(rng, rng.Intn(40))
+		}
+		for _, size := range obj.DataPartInfoSizes {
+			sse.PartSizes = append(sse.PartSizes, int(EncryptedSize(int64(size))))
+		}
+		obj.SSE = sse
+	}
+	if !obj.IsInline() && rng.Intn(5) == 0 {
+		if err := obj.Transition(randomString(rng, 10), randomString(rng, 40), randomString(rng, rng.Intn(2)*36)); err != nil {
+			panic(err)
+		}
+		if rng.Intn(2) == 0 {
+			if err := obj.Restore(rng.Uint64()|1, time.Unix(randomModTime(rng)|1, 0)); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	// Metadata maps are nil, empty, or hold keys with possibly empty values.
+	switch rng.Intn(3) {
+	case 1:
+		obj.MetaSys = map[string][]byte{}
+		obj.MetaUser = map[string][]string{}
+	case 2:
+		keys := rng.Intn(10) + 1
+		obj.MetaSys = make(map[string][]byte, keys)
+		obj.MetaUser = make(map[string][]string, keys)
+		for i := 0; i < keys; i++ {
+			obj.MetaSys[randomString(rng, rng.Intn(20)+1)] = randomBytes(rng, rng.Intn(40))
+			values := make([]string, rng.Intn(3))
+			for j := range values {
+				values[j] = randomString(rng, rng.Intn(40))
+			}
+			obj.MetaUser[randomString(rng, rng.Intn(20)+1)] = values
+		}
+	}
+	return obj
+}
+
+func randomObjectMetaV2Multipart(rng *rand.Rand, size int) *ObjectMetaV2Multipart {
+	obj := randomObjectMetaV2Object(rng, 0)
+	obj.DataPartInfoNumbers, obj.DataPartInfoSizes, obj.DataPartInfoChecksums = nil, nil, nil
+	obj.Inline, obj.SSE, obj.StatSize = nil, nil, 0
+	obj.TransitionStatus, obj.TransitionTier, obj.TransitionedObject, obj.TransitionedVersionID, obj.RestoreExpires = TransitionNone, "", "", "", 0
+	mp := &ObjectMetaV2Multipart{
+		UploadID:  randomString(rng, 36),
+		Initiated: randomModTime(rng),
+		Object:    *obj,
+	}
+	number := 0
+	for i := randomPartCount(rng, size); i > 0; i-- {
+		number += rng.Intn(3) + 1
+		mp.Parts = append(mp.Parts, ObjectMetaV2MultipartPart{
+			Number:  number,
+			Size:    rng.Intn(5 << 30),
+			ETag:    fmt.Sprintf("%x", randomBytes(rng, 16)),
+			ModTime: randomModTime(rng),
+		})
+	}
+	return mp
+}
+
+// normalizeEmpty sets the empty slices and maps reachable from v to nil,
+// since encodings do not tell them apart. Unexported fields are skipped.
+func normalizeEmpty(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			normalizeEmpty(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				normalizeEmpty(f)
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			if v.CanSet() {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			normalizeEmpty(v.Index(i))
+		}
+	case reflect.Map:
+		if v.Len() == 0 {
+			if v.CanSet() {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return
+		}
+		// Map values are not addressable, so normalize copies.
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			normalizeEmpty(value)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
+
+// equalObjectMetaV2 returns whether a and b hold the same metadata.
+// Both are normalized.
+func equalObjectMetaV2(a, b *ObjectMetaV2) bool {
+	normalizeEmpty(reflect.ValueOf(a))
+	normalizeEmpty(reflect.ValueOf(b))
+	a.free, b.free = nil, nil
+	return reflect.DeepEqual(a.ObjectJournals, b.ObjectJournals) && a.Version == b.Version && a.Format == b.Format
+}
+
+// checkRoundTrips checks that z survives msgp and JSON round trips in any order.
+func checkRoundTrips(z ObjectMetaV2) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	for i := range z.ObjectJournals {
+		e := &z.ObjectJournals[i]
+		switch {
+		case e.Object != nil:
+			if err := e.Object.Validate(); err != nil {
+				return fmt.Errorf("generated object %d: %v", i, err)
+			}
+		case e.Link != nil:
+			if err := (*ObjectMetaV2Object)(e.Link).Validate(); err != nil {
+				return fmt.Errorf("generated link %d: %v", i, err)
+			}
+		}
+		if buf, err := e.MarshalMsg(nil); err != nil {
+			return err
+		} else if len(buf) > e.Msgsize() {
+			return fmt.Errorf("entry %d: Msgsize %d < encoded size %d", i, e.Msgsize(), len(buf))
+		}
+	}
+
+	msg, err := z.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+	if len(msg) > z.Msgsize() {
+		return fmt.Errorf("Msgsize %d < encoded size %d", z.Msgsize(), len(msg))
+	}
+	var fromMsg ObjectMetaV2
+	if _, err := fromMsg.UnmarshalMsg(msg); err != nil {
+		return fmt.Errorf("UnmarshalMsg: %v", err)
+	}
+	var streamed bytes.Buffer
+	w := msgp.NewWriter(&streamed)
+	if err := z.EncodeMsg(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if streamed.Len() != len(msg) {
+		return fmt.Errorf("EncodeMsg wrote %d bytes, MarshalMsg %d", streamed.Len(), len(msg))
+	}
+	var fromStream ObjectMetaV2
+	if err := fromStream.DecodeMsg(msgp.NewReader(&streamed)); err != nil {
+		return fmt.Errorf("DecodeMsg: %v", err)
+	}
+
+	js, err := json.Marshal(&z)
+	if err != nil {
+		return err
+	}
+	var fromJSON ObjectMetaV2
+	if err := json.Unmarshal(js, &fromJSON); err != nil {
+		return fmt.Errorf("json.Unmarshal: %v", err)
+	}
+	jsFromMsg, err := json.Marshal(&fromMsg)
+	if err != nil {
+		return err
+	}
+	var fromMsgJSON ObjectMetaV2
+	if err := json.Unmarshal(jsFromMsg, &fromMsgJSON); err != nil {
+		return fmt.Errorf("json.Unmarshal after msgp: %v", err)
+	}
+	msgFromJSON, err := fromJSON.MarshalMsg(nil)
+	if err != nil {
+		return err
+	}
+	var fromJSONMsg ObjectMetaV2
+	if _, err := fromJSONMsg.UnmarshalMsg(msgFromJSON); err != nil {
+		return fmt.Errorf("UnmarshalMsg after JSON: %v", err)
+	}
+
+	for name, got := range map[string]*ObjectMetaV2{
+		"msgp":        &fromMsg,
+		"msgp stream": &fromStream,
+		"JSON":        &fromJSON,
+		"msgp, JSON":  &fromMsgJSON,
+		"JSON, msgp":  &fromJSONMsg,
+	} {
+		want := z
+		if !equalObjectMetaV2(&want, got) {
+			return fmt.Errorf("%s round trip differs", name)
+		}
+	}
+	return nil
+}
+
+func TestObjectMetaV2RoundTripProperties(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("seed %d", seed)
+	rng := rand.New(rand.NewSource(seed))
+	cfg := &quick.Config{MaxCount: 200, Rand: rng}
+	if testing.Short() {
+		cfg.MaxCount = 20
+	}
+	var failure error
+	property := func(z ObjectMetaV2) bool {
+		failure = checkRoundTrips(z)
+		return failure == nil
+	}
+	if err := quick.Check(property, cfg); err != nil {
+		if ce, ok := err.(*quick.CheckError); ok {
+			t.Fatalf("check %d: %v", ce.Count, failure)
+		}
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,155 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var errResyncIDMissing = errors.New("resync ID missing")
+
+func (s ReplicationStatus) String() string {
+	switch s {
+	case ReplicationPending:
+		return "PENDING"
+	case ReplicationCompleted:
+		return "COMPLETED"
+	case ReplicationFailed:
+		return "FAILED"
+	case ReplicationReplica:
+		return "REPLICA"
+	}
+	return fmt.Sprintf("ReplicationStatus(%d)", uint8(s))
+}
+
+// replicationTarget returns the index of arn in targets and whether it is present.
+func replicationTarget(targets []ObjectMetaV2Replication, arn string) (int, bool) {
+	i := sort.Search(len(targets), func(i int) bool { return targets[i].ARN >= arn })
+	return i, i < len(targets) && targets[i].ARN == arn
+}
+
+// replicationStatus returns the state of arn in targets.
+func replicationStatus(targets []ObjectMetaV2Replication, arn string) (ObjectMetaV2Replication, bool) {
+	i, ok := replicationTarget(targets, arn)
+	if !ok {
+		return ObjectMetaV2Replication{}, false
+	}
+	return targets[i], true
+}
+
+// setReplicationStatus sets the status of arn in targets, keeping them
+// sorted, and returns the index of arn.
+func setReplicationStatus(targets *[]ObjectMetaV2Replication, arn string, status ReplicationStatus, now time.Time) int {
+	i, ok := replicationTarget(*targets, arn)
+	if !ok {
+		*targets = append(*targets, ObjectMetaV2Replication{})
+		copy((*targets)[i+1:], (*targets)[i:])
+		(*targets)[i] = ObjectMetaV2Replication{ARN: arn}
+	}
+	(*targets)[i].Status = status
+	(*targets)[i].ModTime = now.Unix()
+	return i
+}
+
+// needsReplication returns whether the state of arn in targets is pending or failed.
+func needsReplication(targets []ObjectMetaV2Replication, arn string) bool {
+	r, ok := replicationStatus(targets, arn)
+	return ok && (r.Status == ReplicationPending || r.Status == ReplicationFailed)
+}
+
+// ReplicationStatus returns the replication state of z for arn.
+func (z *ObjectMetaV2Object) ReplicationStatus(arn string) (ObjectMetaV2Replication, bool) {
+	return replicationStatus(z.Replication, arn)
+}
+
+// SetReplicationStatus sets the replication status of z for arn.
+// The resync marker of the target is kept.
+func (z *ObjectMetaV2Object) SetReplicationStatus(arn string, status ReplicationStatus, now time.Time) {
+	setReplicationStatus(&z.Replication, arn, status, now)
+}
+
+// NeedsReplication returns whether z must be replicated to arn,
+// either because it is pending or because the last attempt failed.
+// Versions without state for arn are not replicated; replicas never are.
+func (z *ObjectMetaV2Object) NeedsReplication(arn string) bool {
+	return needsReplication(z.Replication, arn)
+}
+
+// ReplicationStatus returns the replication state of the delete marker z for arn.
+func (z *ObjectMetaV2DeleteMarker) ReplicationStatus(arn string) (ObjectMetaV2Replication, bool) {
+	return replicationStatus(z.Replication, arn)
+}
+
+// SetReplicationStatus sets the replication status of the delete marker z for arn.
+// The resync marker of the target is kept.
+func (z *ObjectMetaV2DeleteMarker) SetReplicationStatus(arn string, status ReplicationStatus, now time.Time) {
+	setReplicationStatus(&z.Replication, arn, status, now)
+}
+
+// NeedsReplication returns whether the delete marker z must be replicated to arn,
+// as NeedsReplication of objects.
+func (z *ObjectMetaV2DeleteMarker) NeedsReplication(arn string) bool {
+	return needsReplication(z.Replication, arn)
+}
+
+// replicationObject returns the object of an object or link entry.
+func (e *ObjectMetaV2JournalEntry) replicationObject() *ObjectMetaV2Object {
+	switch {
+	case e.Type == Object && e.Object != nil:
+		return e.Object
+	case e.Type == Link && e.Link != nil:
+		return (*ObjectMetaV2Object)(e.Link)
+	}
+	return nil
+}
+
+// replicationTargets returns the replication state of a version,
+// or nil if the entry is not a version.
+func (e *ObjectMetaV2JournalEntry) replicationTargets() *[]ObjectMetaV2Replication {
+	if obj := e.replicationObject(); obj != nil {
+		return &obj.Replication
+	}
+	if e.Type == Delete && e.DeleteMarker != nil {
+		return &e.DeleteMarker.Replication
+	}
+	return nil
+}
+
+// VersionsToReplicate returns the journal indexes of the versions, delete
+// markers included, that must be replicated to arn, oldest first.
+func (z *ObjectMetaV2) VersionsToReplicate(arn string) []int {
+	var idxs []int
+	for i := range z.ObjectJournals {
+		if targets := z.ObjectJournals[i].replicationTargets(); targets != nil && needsReplication(*targets, arn) {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// Resync queues all versions, delete markers included, for replication to arn
+// again, for example after the target bucket was replaced. Each queued version
+// records resyncID so restarting the same resync skips versions it already queued.
+// Replicas are not replicated back and are left alone.
+// The number of versions queued is returned; resyncID must not be empty.
+func (z *ObjectMetaV2) Resync(arn, resyncID string, now time.Time) (int, error) {
+	if resyncID == "" {
+		return 0, errResyncIDMissing
+	}
+	n := 0
+	for i := range z.ObjectJournals {
+		targets := z.ObjectJournals[i].replicationTargets()
+		if targets == nil {
+			continue
+		}
+		r, _ := replicationStatus(*targets, arn)
+		if r.Resync == resyncID || r.Status == ReplicationReplica {
+			continue
+		}
+		j := setReplicationStatus(targets, arn, ReplicationPending, now)
+		(*targets)[j].Resync = resyncID
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,123 @@
+package xlmeta
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/tinylib/msgp/msgp"
+)
+
+func TestObjectMetaV2Replication(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	xlmeta := getSampleObjectMetaV2(1, 4)
+	for i, e := range xlmeta.ObjectJournals {
+		e.Object.VersionID = uint64(i + 1)
+		e.Object.StatModTime = now.Unix() - 1
+	}
+	xlmeta.AddDeleteMarker(5, now)
+	objs := xlmeta.ObjectJournals
+
+	objs[0].Object.SetReplicationStatus("arn:b", ReplicationCompleted, now)
+	objs[1].Object.SetReplicationStatus("arn:b", ReplicationFailed, now)
+	objs[1].Object.SetReplicationStatus("arn:a", ReplicationCompleted, now)
+	objs[2].Object.SetReplicationStatus("arn:b", ReplicationReplica, now)
+	objs[3].Object.SetReplicationStatus("arn:b", ReplicationCompleted, now)
+	objs[3].Object.SetReplicationStatus("arn:b", ReplicationPending, now.Add(time.Second))
+	objs[4].DeleteMarker.SetReplicationStatus("arn:b", ReplicationFailed, now)
+	objs[4].DeleteMarker.SetReplicationStatus("arn:a", ReplicationCompleted, now)
+
+	if r := objs[1].Object.Replication; len(r) != 2 || r[0].ARN != "arn:a" || r[1].ARN != "arn:b" {
+		t.Fatalf("targets not sorted: %+v", r)
+	}
+	if r, ok := objs[3].Object.ReplicationStatus("arn:b"); !ok || r.Status != ReplicationPending || r.ModTime != now.Unix()+1 {
+		t.Fatalf("unexpected status %+v", r)
+	}
+	if _, ok := objs[0].Object.ReplicationStatus("arn:a"); ok {
+		t.Fatal("unexpected status for unknown target")
+	}
+	if r, ok := objs[4].DeleteMarker.ReplicationStatus("arn:b"); !ok || r.Status != ReplicationFailed || !objs[4].DeleteMarker.NeedsReplication("arn:b") {
+		t.Fatalf("unexpected delete marker status %+v", r)
+	}
+	if got := xlmeta.VersionsToReplicate("arn:b"); !reflect.DeepEqual(got, []int{1, 3, 4}) {
+		t.Fatalf("want versions [1 3 4], got %v", got)
+	}
+	if got := xlmeta.VersionsToReplicate("arn:a"); len(got) != 0 {
+		t.Fatalf("want no versions, got %v", got)
+	}
+
+	buf, err := xlmeta.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ObjectMetaV2
+	if _, err = decoded.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	var streamed ObjectMetaV2
+	if err = streamed.DecodeMsg(msgp.NewReader(bytes.NewReader(buf))); err != nil {
+		t.Fatal(err)
+	}
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonBuf, err := json.Marshal(xlmeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ObjectMetaV2
+	if err = json.Unmarshal(jsonBuf, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []ObjectMetaV2{decoded, streamed, fromJSON} {
+		for i := 0; i < 4; i++ {
+			if !reflect.DeepEqual(got.ObjectJournals[i].Object.Replication, objs[i].Object.Replication) {
+				t.Fatalf("version %d: want %+v, got %+v", i, objs[i].Object.Replication, got.ObjectJournals[i].Object.Replication)
+			}
+		}
+		if !reflect.DeepEqual(got.ObjectJournals[4].DeleteMarker.Replication, objs[4].DeleteMarker.Replication) {
+			t.Fatalf("delete marker: want %+v, got %+v", objs[4].DeleteMarker.Replication, got.ObjectJournals[4].DeleteMarker.Replication)
+		}
+	}
+
+	// Versions without replication state do not encode the field.
+	plain := getSampleObjectMetaV2(1, 1)
+	plain.AddDeleteMarker(2, time.Now())
+	if buf, _ = plain.MarshalMsg(nil); bytes.Contains(buf, []byte("repl")) {
+		t.Fatal("empty replication state was encoded")
+	}
+}
+
+func TestObjectMetaV2Resync(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	xlmeta := getSampleObjectMetaV2(1, 3)
+	objs := xlmeta.ObjectJournals
+	objs[0].Object.SetReplicationStatus("arn:a", ReplicationCompleted, now)
+	objs[1].Object.SetReplicationStatus("arn:a", ReplicationReplica, now)
+	xlmeta.AddDeleteMarker(4, time.Now())
+
+	if n, err := xlmeta.Resync("arn:a", "resync-1", now); err != nil || n != 3 {
+		t.Fatalf("want 3 versions queued, got %d, %v", n, err)
+	}
+	if got := xlmeta.VersionsToReplicate("arn:a"); !reflect.DeepEqual(got, []int{0, 2, 3}) {
+		t.Fatalf("want versions [0 2 3], got %v", got)
+	}
+	if r, _ := xlmeta.ObjectJournals[3].DeleteMarker.ReplicationStatus("arn:a"); r.Resync != "resync-1" {
+		t.Fatalf("delete marker not queued: %+v", r)
+	}
+	objs[0].Object.SetReplicationStatus("arn:a", ReplicationCompleted, now)
+	if r, _ := objs[0].Object.ReplicationStatus("arn:a"); r.Resync != "resync-1" {
+		t.Fatalf("resync marker lost: %+v", r)
+	}
+
+	// Restarting the same resync does not queue versions again.
+	if n, err := xlmeta.Resync("arn:a", "resync-1", now); err != nil || n != 0 {
+		t.Fatalf("want no versions queued, got %d, %v", n, err)
+	}
+	if n, err := xlmeta.Resync("arn:a", "resync-2", now); err != nil || n != 3 {
+		t.Fatalf("want 3 versions queued, got %d, %v", n, err)
+	}
+	if _, err := xlmeta.Resync("arn:b", "", now); err != errResyncIDMissing {
+		t.Fatalf("empty resync ID: want %v, got %v", errResyncIDMissing, err)
+	}
+}
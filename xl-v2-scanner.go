@@ -0,0 +1,188 @@
+package xlmeta
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanOptions controls ScanDrive.
+type ScanOptions struct {
+	// Workers is the number of goroutines decoding metadata,
+	// GOMAXPROCS when zero. At most Workers metadata files are held
+	// in memory at once, plus as many summaries waiting to be handled.
+	Workers int
+}
+
+// ObjectSummary summarizes the metadata of an object found by ScanDrive.
+type ObjectSummary struct {
+	Bucket, Object string
+	Versions       int // Versions, delete markers included.
+	DeleteMarkers  int
+	Size           int64 // Total size of the versions.
+	// ErasureM and ErasureN are the erasure configuration of the latest
+	// version with data, zero if there is none.
+	ErasureM, ErasureN int
+	ModTime            time.Time // Of the latest version.
+	// Err is set when the metadata could not be read or decoded;
+	// the other fields are then zero.
+	Err error
+
+	usage Usage // Accounting of the object, merged into its bucket's.
+}
+
+// ScanDrive walks the drive root, holding buckets of objects stored like
+// <bucket>/<object>/xl.meta, and decodes the metadata of every object with
+// parallel workers. fn, if not nil, is called with the summary of each
+// object from a single goroutine, in no particular order; an error returned
+// by fn stops the scan. ScanDrive returns the Usage of each bucket.
+func ScanDrive(ctx context.Context, root string, opts ScanOptions, fn func(ObjectSummary) error) (map[string]*Usage, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	paths := make(chan string, workers)
+	var walkErr error
+	go func() {
+		defer close(paths)
+		walkErr = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if info.IsDir() || info.Name() != MetaFile {
+				return nil
+			}
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	summaries := make(chan ObjectSummary, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				s, ok := scanObject(root, path)
+				if !ok {
+					continue
+				}
+				select {
+				case summaries <- s:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(summaries)
+	}()
+
+	usage := make(map[string]*Usage)
+	var err error
+	for s := range summaries {
+		if err != nil {
+			continue
+		}
+		if fn != nil {
+			if err = fn(s); err != nil {
+				cancel()
+				continue
+			}
+		}
+		u := usage[s.Bucket]
+		if u == nil {
+			u = &Usage{}
+			usage[s.Bucket] = u
+		}
+		if s.Err != nil {
+			u.Errors++
+		} else {
+			u.Merge(&s.usage)
+		}
+	}
+	// The walk is done once the summaries are closed. Summaries may have
+	// been dropped if ctx was canceled after the walk.
+	if err == nil {
+		err = walkErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// scanObject summarizes the metadata file at path under root.
+// It returns false if path is not the metadata of an object in a bucket.
+func scanObject(root, path string) (s ObjectSummary, ok bool) {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return s, false
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	if len(parts) != 2 || parts[0] == "." {
+		return s, false
+	}
+	s.Bucket, s.Object = parts[0], parts[1]
+
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		s.Err = err
+		return s, true
+	}
+	var z ObjectMetaV2
+	if _, err := z.UnmarshalMsg(buf); err != nil {
+		s.Err = fmt.Errorf("%s: %w", path, err)
+		return s, true
+	}
+	z.summarize(&s)
+	return s, true
+}
+
+// summarize fills the version information and usage of s.
+func (z *ObjectMetaV2) summarize(s *ObjectSummary) {
+	s.usage.Add(z)
+	latestData := false
+	idxs := z.versionIndexes()
+	for n := len(idxs) - 1; n >= 0; n-- {
+		e := &z.ObjectJournals[idxs[n]]
+		if n == len(idxs)-1 {
+			s.ModTime = time.Unix(e.ModTime(), 0).UTC()
+		}
+		s.Versions++
+		obj := e.Object
+		switch e.Type {
+		case Delete:
+			s.DeleteMarkers++
+			continue
+		case Link:
+			obj = (*ObjectMetaV2Object)(e.Link)
+		}
+		s.Size += int64(obj.StatSize)
+		if !latestData {
+			latestData = true
+			s.ErasureM, s.ErasureN = obj.DataErasureM, obj.DataErasureN
+		}
+	}
+}
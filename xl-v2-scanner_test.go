@@ -0,0 +1,157 @@
+package xlmeta
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+// writeDrive writes the metadata of generated objects under a temporary
+// drive root, returning it with the expected summaries sorted by name.
+func writeDrive(t *testing.T) (string, []ObjectSummary) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "xl-meta-drive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []ObjectSummary
+	w := DefaultWorkload
+	w.DeleteMarkerShare = 0.3
+	for i, name := range []string{
+		"photos/2021/a.jpg",
+		"photos/2021/b.jpg",
+		"photos/c.jpg",
+		"logs/x",
+		"logs/y/z",
+		"empty-object",
+	} {
+		w.Seed = int64(i + 1)
+		z := w.Generate(i * 3)
+		s := ObjectSummary{Bucket: "bucket-a", Object: name}
+		if i%2 == 1 {
+			s.Bucket = "bucket-b"
+		}
+		z.summarize(&s)
+		want = append(want, s)
+		buf, err := z.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(root, s.Bucket, filepath.FromSlash(name), MetaFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, buf, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Part files and metadata outside of buckets are not objects.
+	for _, path := range []string{"bucket-a/photos/c.jpg/part.1", MetaFile, "bucket-a/" + MetaFile} {
+		if err := ioutil.WriteFile(filepath.Join(root, filepath.FromSlash(path)), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sortSummaries(want)
+	return root, want
+}
+
+func sortSummaries(s []ObjectSummary) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].Bucket != s[j].Bucket {
+			return s[i].Bucket < s[j].Bucket
+		}
+		return s[i].Object < s[j].Object
+	})
+}
+
+func TestScanDrive(t *testing.T) {
+	root, want := writeDrive(t)
+	defer os.RemoveAll(root)
+
+	wantUsage := make(map[string]*Usage)
+	for i := range want {
+		if wantUsage[want[i].Bucket] == nil {
+			wantUsage[want[i].Bucket] = &Usage{}
+		}
+		wantUsage[want[i].Bucket].Merge(&want[i].usage)
+	}
+	if wantUsage["bucket-a"].DeleteMarkers == 0 || wantUsage["bucket-b"].CurrentBytes == 0 {
+		t.Fatalf("sample drive lacks delete markers or data: %+v %+v", wantUsage["bucket-a"], wantUsage["bucket-b"])
+	}
+
+	for _, workers := range []int{0, 1, 4} {
+		var got []ObjectSummary
+		usage, err := ScanDrive(context.Background(), root, ScanOptions{Workers: workers}, func(s ObjectSummary) error {
+			got = append(got, s)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortSummaries(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%d workers: summaries\ngot  %+v\nwant %+v", workers, got, want)
+		}
+		if !reflect.DeepEqual(usage, wantUsage) {
+			t.Errorf("%d workers: usage\ngot  %+v\nwant %+v", workers, usage, wantUsage)
+		}
+	}
+
+	// Corrupt metadata is reported without stopping the scan.
+	if err := ioutil.WriteFile(filepath.Join(root, "bucket-b", "logs", "x", MetaFile), []byte("corrupt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var failed []string
+	usage, err := ScanDrive(context.Background(), root, ScanOptions{}, func(s ObjectSummary) error {
+		if s.Err != nil {
+			failed = append(failed, s.Bucket+"/"+s.Object)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(failed, []string{"bucket-b/logs/x"}) || usage["bucket-b"].Errors != 1 || usage["bucket-b"].Objects != wantUsage["bucket-b"].Objects-1 {
+		t.Errorf("corrupt metadata: failed %v, usage %+v", failed, usage["bucket-b"])
+	}
+
+	if _, err := ScanDrive(context.Background(), filepath.Join(root, "missing"), ScanOptions{}, nil); err == nil {
+		t.Error("missing drive scanned")
+	}
+}
+
+func TestScanDriveStop(t *testing.T) {
+	root, _ := writeDrive(t)
+	defer os.RemoveAll(root)
+
+	errStop := errors.New("stop")
+	var calls int32
+	_, err := ScanDrive(context.Background(), root, ScanOptions{Workers: 2}, func(s ObjectSummary) error {
+		atomic.AddInt32(&calls, 1)
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("want %v after 1 call, got %v after %d", errStop, err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	_, err = ScanDrive(ctx, root, ScanOptions{Workers: 2}, func(s ObjectSummary) error {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := ScanDrive(ctx, root, ScanOptions{}, nil); err != context.Canceled {
+		t.Errorf("canceled scan: want %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,227 @@
+package xlmeta
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// darePackageSize is the maximum payload of a DARE package.
+	darePackageSize = 64 << 10
+	// darePackageOverhead is the header and authentication tag size of a DARE package.
+	darePackageOverhead = 32
+)
+
+var (
+	errInvalidEncryptedSize = errors.New("invalid encrypted size")
+	errInvalidLegacySSE     = errors.New("invalid legacy encryption metadata")
+)
+
+// Legacy MetaSys keys of encrypted objects, replaced by ObjectMetaV2SSE.
+const (
+	legacySSEPrefix       = "X-Minio-Internal-Server-Side-Encryption-"
+	legacySSEIV           = legacySSEPrefix + "Iv"
+	legacySSEAlgorithm    = legacySSEPrefix + "Seal-Algorithm"
+	legacySSECSealedKey   = legacySSEPrefix + "Sealed-Key"
+	legacySSES3SealedKey  = legacySSEPrefix + "S3-Sealed-Key"
+	legacySSEKMSSealedKey = legacySSEPrefix + "Kms-Sealed-Key"
+	legacySSEKeyID        = legacySSEPrefix + "S3-Kms-Key-Id"
+	legacySSEDataKey      = legacySSEPrefix + "S3-Kms-Sealed-Key"
+	legacySSEContext      = legacySSEPrefix + "Context"
+	legacyActualSize      = "X-Minio-Internal-actual-size"
+)
+
+func (t SSEType) String() string {
+	switch t {
+	case SSENone:
+		return "none"
+	case SSES3:
+		return "SSE-S3"
+	case SSEKMS:
+		return "SSE-KMS"
+	case SSEC:
+		return "SSE-C"
+	}
+	return fmt.Sprintf("SSEType(%d)", uint8(t))
+}
+
+// EncryptedSize returns the size of size bytes once encrypted with DARE.
+func EncryptedSize(size int64) int64 {
+	return size + ceilFrac(size, darePackageSize)*darePackageOverhead
+}
+
+// DecryptedSize returns the size of size bytes of DARE packages once decrypted.
+func DecryptedSize(size int64) (int64, error) {
+	if size < 0 {
+		return 0, fmt.Errorf("%w: %d", errInvalidEncryptedSize, size)
+	}
+	packages := ceilFrac(size, darePackageSize+darePackageOverhead)
+	if last := size % (darePackageSize + darePackageOverhead); last > 0 && last <= darePackageOverhead {
+		return 0, fmt.Errorf("%w: %d", errInvalidEncryptedSize, size)
+	}
+	return size - packages*darePackageOverhead, nil
+}
+
+// EncryptedRange returns the range of an encrypted part of size decrypted bytes
+// that must be read and decrypted to serve length bytes starting at offset.
+// Whole packages are read; skip is the number of decrypted bytes to discard
+// before offset is reached.
+func EncryptedRange(offset, length, size int64) (encOffset, encLength, skip int64, err error) {
+	if offset < 0 || length < 0 || offset+length > size {
+		return 0, 0, 0, fmt.Errorf("%w: offset %d, length %d, size %d", errInvalidRange, offset, length, size)
+	}
+	if length == 0 {
+		return 0, 0, 0, nil
+	}
+	const packageSize = darePackageSize + darePackageOverhead
+	first := offset / darePackageSize
+	last := (offset + length - 1) / darePackageSize
+	encOffset = first * packageSize
+	encEnd := (last + 1) * packageSize
+	if end := EncryptedSize(size); encEnd > end {
+		encEnd = end
+	}
+	return encOffset, encEnd - encOffset, offset - first*darePackageSize, nil
+}
+
+// IsEncrypted returns whether the object data is encrypted.
+func (z *ObjectMetaV2Object) IsEncrypted() bool {
+	return z.SSE != nil && z.SSE.Type != SSENone
+}
+
+// EncryptedPartSizes returns the encrypted size of every part
+// computed from DataPartInfoSizes.
+func (z *ObjectMetaV2Object) EncryptedPartSizes() []int64 {
+	sizes := make([]int64, len(z.DataPartInfoSizes))
+	for i, size := range z.DataPartInfoSizes {
+		sizes[i] = EncryptedSize(int64(size))
+	}
+	return sizes
+}
+
+// storedPartSizes returns the size of every part as stored on the drives.
+func (z *ObjectMetaV2Object) storedPartSizes() DeltaEncodedInt {
+	if z.IsEncrypted() {
+		return z.SSE.PartSizes
+	}
+	return z.DataPartInfoSizes
+}
+
+// validateSSE checks that the encrypted part sizes match the part sizes.
+func (z *ObjectMetaV2Object) validateSSE() error {
+	if !z.IsEncrypted() {
+		return nil
+	}
+	if z.SSE.Type > SSEC {
+		return fmt.Errorf("%w: unknown encryption %s", errInvalidParts, z.SSE.Type)
+	}
+	if len(z.SSE.PartSizes) != len(z.DataPartInfoSizes) {
+		return fmt.Errorf("%w: %d encrypted part sizes, %d part sizes", errInvalidParts, len(z.SSE.PartSizes), len(z.DataPartInfoSizes))
+	}
+	for i, size := range z.DataPartInfoSizes {
+		if enc := int64(z.SSE.PartSizes[i]); enc != EncryptedSize(int64(size)) {
+			return fmt.Errorf("%w: part %d has encrypted size %d for %d bytes", errInvalidParts, z.DataPartInfoNumbers[i], enc, size)
+		}
+	}
+	return nil
+}
+
+// MigrateLegacySSE moves the encryption state stored in legacy MetaSys keys
+// to z.SSE. Legacy objects store encrypted part sizes in DataPartInfoSizes;
+// they are moved to z.SSE.PartSizes and replaced by the decrypted sizes.
+// It returns whether z was migrated. Objects without legacy keys are unchanged.
+func (z *ObjectMetaV2Object) MigrateLegacySSE() (bool, error) {
+	if z.SSE != nil {
+		return false, nil
+	}
+	var sse ObjectMetaV2SSE
+	var sealedKey string
+	for _, k := range []struct {
+		typ SSEType
+		key string
+	}{{SSEC, legacySSECSealedKey}, {SSES3, legacySSES3SealedKey}, {SSEKMS, legacySSEKMSSealedKey}} {
+		if _, ok := z.MetaSys[k.key]; ok {
+			if sealedKey != "" {
+				return false, fmt.Errorf("%w: both %s and %s set", errInvalidLegacySSE, sealedKey, k.key)
+			}
+			sse.Type, sealedKey = k.typ, k.key
+		}
+	}
+	if sealedKey == "" {
+		return false, nil
+	}
+	decode := func(key string) ([]byte, error) {
+		v, ok := z.MetaSys[key]
+		if !ok {
+			return nil, nil
+		}
+		b, err := base64.StdEncoding.DecodeString(string(v))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", errInvalidLegacySSE, key, err)
+		}
+		return b, nil
+	}
+
+	sse.Algorithm = string(z.MetaSys[legacySSEAlgorithm])
+	var err error
+	if sse.SealedKey, err = decode(sealedKey); err != nil {
+		return false, err
+	}
+	if sse.IV, err = decode(legacySSEIV); err != nil {
+		return false, err
+	}
+	if len(sse.SealedKey) == 0 || len(sse.IV) == 0 || sse.Algorithm == "" {
+		return false, fmt.Errorf("%w: sealed key, IV and algorithm are required", errInvalidLegacySSE)
+	}
+	if sse.Type != SSEC {
+		sse.KeyID = string(z.MetaSys[legacySSEKeyID])
+		if sse.DataKey, err = decode(legacySSEDataKey); err != nil {
+			return false, err
+		}
+		if sse.KeyID == "" || len(sse.DataKey) == 0 {
+			return false, fmt.Errorf("%w: KMS key ID and data key are required", errInvalidLegacySSE)
+		}
+	}
+	if sse.Type == SSEKMS {
+		if sse.Context, err = decode(legacySSEContext); err != nil {
+			return false, err
+		}
+	}
+
+	sizes := make(DeltaEncodedInt, len(z.DataPartInfoSizes))
+	total := 0
+	for i, enc := range z.DataPartInfoSizes {
+		size, err := DecryptedSize(int64(enc))
+		if err != nil {
+			return false, fmt.Errorf("%w: part %d: %v", errInvalidLegacySSE, z.DataPartInfoNumbers[i], err)
+		}
+		sizes[i] = int(size)
+		total += int(size)
+	}
+	sse.PartSizes = append(DeltaEncodedInt(nil), z.DataPartInfoSizes...)
+	z.DataPartInfoSizes = sizes
+	z.StatSize = total
+	z.SSE = &sse
+	for key := range z.MetaSys {
+		if strings.HasPrefix(key, legacySSEPrefix) || key == legacyActualSize {
+			delete(z.MetaSys, key)
+		}
+	}
+	return true, nil
+}
+
+// MigrateLegacySSE migrates the legacy encryption metadata of every version
+// and multipart upload of z. It returns the number of objects migrated.
+func (z *ObjectMetaV2) MigrateLegacySSE() (int, error) {
+	n := 0
+	err := z.journalObjects(func(obj *ObjectMetaV2Object) error {
+		migrated, err := obj.MigrateLegacySSE()
+		if migrated {
+			n++
+		}
+		return err
+	})
+	return n, err
+}
@@ -0,0 +1,188 @@
+package xlmeta
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func TestEncryptedSize(t *testing.T) {
+	testCases := []struct {
+		size, encrypted int64
+	}{
+		{0, 0},
+		{1, 33},
+		{64 << 10, 64<<10 + 32},
+		{64<<10 + 1, 64<<10 + 1 + 64},
+		{5 << 20, 5<<20 + 80*32},
+	}
+	for _, tc := range testCases {
+		if got := EncryptedSize(tc.size); got != tc.encrypted {
+			t.Errorf("EncryptedSize(%d): want %d, got %d", tc.size, tc.encrypted, got)
+		}
+		if got, err := DecryptedSize(tc.encrypted); err != nil || got != tc.size {
+			t.Errorf("DecryptedSize(%d): want %d, got %d, %v", tc.encrypted, tc.size, got, err)
+		}
+	}
+	for _, size := range []int64{-1, 1, 32, 64<<10 + 32 + 32} {
+		if _, err := DecryptedSize(size); !errors.Is(err, errInvalidEncryptedSize) {
+			t.Errorf("DecryptedSize(%d): want %v, got %v", size, errInvalidEncryptedSize, err)
+		}
+	}
+	property := func(size uint32) bool {
+		got, err := DecryptedSize(EncryptedSize(int64(size)))
+		return err == nil && got == int64(size)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptedRange(t *testing.T) {
+	const pkg = 64<<10 + 32
+	testCases := []struct {
+		offset, length, size       int64
+		encOffset, encLength, skip int64
+	}{
+		{0, 0, 100, 0, 0, 0},
+		{0, 1, 100, 0, 132, 0},
+		{10, 20, 100, 0, 132, 10},
+		{64 << 10, 1, 1 << 20, pkg, pkg, 0},
+		{64<<10 - 1, 2, 1 << 20, 0, 2 * pkg, 64<<10 - 1},
+		{64<<10 + 5, 10, 64<<10 + 100, pkg, 132, 5},
+	}
+	for _, tc := range testCases {
+		encOffset, encLength, skip, err := EncryptedRange(tc.offset, tc.length, tc.size)
+		if err != nil || encOffset != tc.encOffset || encLength != tc.encLength || skip != tc.skip {
+			t.Errorf("EncryptedRange(%d, %d, %d): want %d, %d, %d, got %d, %d, %d, %v",
+				tc.offset, tc.length, tc.size, tc.encOffset, tc.encLength, tc.skip, encOffset, encLength, skip, err)
+		}
+	}
+	if _, _, _, err := EncryptedRange(90, 20, 100); !errors.Is(err, errInvalidRange) {
+		t.Fatalf("want %v, got %v", errInvalidRange, err)
+	}
+}
+
+func TestObjectMetaV2MigrateLegacySSE(t *testing.T) {
+	b64 := func(s string) []byte { return []byte(base64.StdEncoding.EncodeToString([]byte(s))) }
+	legacy := map[string][]byte{
+		legacySSEIV:        b64("iv"),
+		legacySSEAlgorithm: []byte("DAREv2-HMAC-SHA256"),
+		legacySSEKeyID:     []byte("my-key"),
+		legacySSEDataKey:   b64("data-key"),
+		legacySSEContext:   b64(`{"bucket":"object"}`),
+		legacyActualSize:   []byte("1000"),
+	}
+	testCases := []struct {
+		sealedKey string
+		want      ObjectMetaV2SSE
+	}{
+		{legacySSECSealedKey, ObjectMetaV2SSE{Type: SSEC}},
+		{legacySSES3SealedKey, ObjectMetaV2SSE{Type: SSES3, KeyID: "my-key", DataKey: []byte("data-key")}},
+		{legacySSEKMSSealedKey, ObjectMetaV2SSE{Type: SSEKMS, KeyID: "my-key", DataKey: []byte("data-key"), Context: []byte(`{"bucket":"object"}`)}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want.Type.String(), func(t *testing.T) {
+			xlmeta := getSampleObjectMetaV2(3, 2)
+			for _, e := range xlmeta.ObjectJournals {
+				for k, v := range legacy {
+					e.Object.MetaSys[k] = v
+				}
+				e.Object.MetaSys[tc.sealedKey] = b64("object-key")
+				e.Object.DataPartInfoSizes = DeltaEncodedInt{100 + 32, 64<<10 + 32, 64<<10 + 1 + 64}
+			}
+			if n, err := xlmeta.MigrateLegacySSE(); err != nil || n != 2 {
+				t.Fatalf("want 2 objects migrated, got %d, %v", n, err)
+			}
+			if n, err := xlmeta.MigrateLegacySSE(); err != nil || n != 0 {
+				t.Fatalf("want no objects migrated again, got %d, %v", n, err)
+			}
+
+			want := tc.want
+			want.Algorithm = "DAREv2-HMAC-SHA256"
+			want.SealedKey = []byte("object-key")
+			want.IV = []byte("iv")
+			want.PartSizes = DeltaEncodedInt{100 + 32, 64<<10 + 32, 64<<10 + 1 + 64}
+			obj := xlmeta.ObjectJournals[1].Object
+			if !reflect.DeepEqual(*obj.SSE, want) {
+				t.Fatalf("want %+v, got %+v", want, *obj.SSE)
+			}
+			if !reflect.DeepEqual(obj.DataPartInfoSizes, DeltaEncodedInt{100, 64 << 10, 64<<10 + 1}) || obj.StatSize != 100+2*(64<<10)+1 {
+				t.Fatalf("unexpected decrypted sizes %v, %d", obj.DataPartInfoSizes, obj.StatSize)
+			}
+			if len(obj.MetaSys) != 2 {
+				t.Fatalf("legacy keys left: %q", obj.MetaSys)
+			}
+			if err := obj.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := obj.PartsSize(); got != 100+32+2*(64<<10+32)+1+32 {
+				t.Fatalf("PartsSize of encrypted object: got %d", got)
+			}
+			for i, size := range obj.EncryptedPartSizes() {
+				if size != int64(want.PartSizes[i]) {
+					t.Fatalf("part %d: want encrypted size %d, got %d", i, want.PartSizes[i], size)
+				}
+			}
+
+			buf, err := xlmeta.MarshalMsg(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded ObjectMetaV2
+			if _, err = decoded.UnmarshalMsg(buf); err != nil {
+				t.Fatal(err)
+			}
+			var json = jsoniter.ConfigCompatibleWithStandardLibrary
+			jsonBuf, err := json.Marshal(xlmeta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fromJSON ObjectMetaV2
+			if err = json.Unmarshal(jsonBuf, &fromJSON); err != nil {
+				t.Fatal(err)
+			}
+			for _, got := range []ObjectMetaV2{decoded, fromJSON} {
+				if !reflect.DeepEqual(*got.ObjectJournals[1].Object.SSE, want) {
+					t.Fatalf("want %+v, got %+v", want, *got.ObjectJournals[1].Object.SSE)
+				}
+			}
+
+			obj.SSE.PartSizes[0]++
+			if err := obj.Validate(); !errors.Is(err, errInvalidParts) {
+				t.Fatalf("want %v, got %v", errInvalidParts, err)
+			}
+		})
+	}
+}
+
+func TestObjectMetaV2ObjectMigrateLegacySSEInvalid(t *testing.T) {
+	testCases := map[string]map[string][]byte{
+		"no iv":           {legacySSECSealedKey: []byte("a2V5"), legacySSEAlgorithm: []byte("DARE-SHA256")},
+		"bad base64":      {legacySSECSealedKey: []byte("!"), legacySSEIV: []byte("aXY="), legacySSEAlgorithm: []byte("DARE-SHA256")},
+		"two sealed keys": {legacySSECSealedKey: []byte("a2V5"), legacySSES3SealedKey: []byte("a2V5")},
+		"no kms key":      {legacySSES3SealedKey: []byte("a2V5"), legacySSEIV: []byte("aXY="), legacySSEAlgorithm: []byte("DARE-SHA256")},
+		"bad part size":   {legacySSECSealedKey: []byte("a2V5"), legacySSEIV: []byte("aXY="), legacySSEAlgorithm: []byte("DARE-SHA256"), "size": nil},
+	}
+	for name, metaSys := range testCases {
+		obj := newObjectMetaV2Object(1)
+		obj.MetaSys = metaSys
+		if _, bad := metaSys["size"]; bad {
+			obj.DataPartInfoSizes[0] = 64<<10 + 32 + 16
+		}
+		if migrated, err := obj.MigrateLegacySSE(); migrated || !errors.Is(err, errInvalidLegacySSE) {
+			t.Errorf("%s: want %v, got %v", name, errInvalidLegacySSE, err)
+		}
+		if obj.SSE != nil || obj.MetaSys[legacySSECSealedKey] == nil && obj.MetaSys[legacySSES3SealedKey] == nil {
+			t.Errorf("%s: failed migration modified the object", name)
+		}
+	}
+	obj := newObjectMetaV2Object(1)
+	if migrated, err := obj.MigrateLegacySSE(); migrated || err != nil || obj.SSE != nil {
+		t.Fatalf("unencrypted object migrated: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package xlmeta
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	errDataTransitioned  = errors.New("object data is stored in a remote tier")
+	errNotTransitioned   = errors.New("version is not transitioned")
+	errInvalidTransition = errors.New("invalid transition")
+)
+
+func (s TransitionStatus) String() string {
+	switch s {
+	case TransitionNone:
+		return "NONE"
+	case TransitionComplete:
+		return "COMPLETE"
+	}
+	return fmt.Sprintf("TransitionStatus(%d)", uint8(s))
+}
+
+// IsTransitioned returns whether the data of z has been moved to a remote tier.
+func (z *ObjectMetaV2Object) IsTransitioned() bool {
+	return z.TransitionStatus == TransitionComplete
+}
+
+// IsRestored returns whether a local copy of the transitioned data of z
+// can be read at now.
+func (z *ObjectMetaV2Object) IsRestored(now time.Time) bool {
+	return z.IsTransitioned() && z.RestoreExpires != 0 && now.Unix() < z.RestoreExpires
+}
+
+// hasLocalParts returns whether the parts of z are stored on the drives.
+// Transitioned versions only have local parts while restored,
+// until ExpireRestore removes them.
+func (z *ObjectMetaV2Object) hasLocalParts() bool {
+	return !z.IsTransitioned() || z.RestoreExpires != 0
+}
+
+// Transition records that the data of z has been moved to remoteObject,
+// at remoteVersion if the tier is versioned, in tier. The storage class of z
+// becomes the tier name. DataDir and the part checksums are cleared, the
+// caller removes the local data; the parts are kept to describe the remote
+// data. Inline data is never transitioned.
+func (z *ObjectMetaV2Object) Transition(tier, remoteObject, remoteVersion string) error {
+	if z.IsInline() {
+		return errDataInline
+	}
+	if z.IsTransitioned() {
+		return fmt.Errorf("%w: version %d is already in tier %q", errInvalidTransition, z.VersionID, z.TransitionTier)
+	}
+	if tier == "" || remoteObject == "" {
+		return fmt.Errorf("%w: tier %q, remote object %q", errInvalidTransition, tier, remoteObject)
+	}
+	z.StorageClass = tier
+	z.TransitionStatus = TransitionComplete
+	z.TransitionTier = tier
+	z.TransitionedObject = remoteObject
+	z.TransitionedVersionID = remoteVersion
+	z.DataDir = 0
+	z.DataPartInfoChecksums = nil
+	z.RestoreExpires = 0
+	return nil
+}
+
+// Restore records that the transitioned data of z has been copied back
+// to dataDir, where it is kept until expires. Restoring a restored version
+// replaces its data directory and expiry.
+func (z *ObjectMetaV2Object) Restore(dataDir uint64, expires time.Time) error {
+	if !z.IsTransitioned() {
+		return errNotTransitioned
+	}
+	if dataDir == 0 {
+		return fmt.Errorf("%w: restore of version %d without a data directory", errInvalidTransition, z.VersionID)
+	}
+	z.DataDir = dataDir
+	z.RestoreExpires = expires.Unix()
+	return nil
+}
+
+// ExpireRestore removes the restored copy of z if it has expired at now,
+// returning the data directory the caller must remove.
+func (z *ObjectMetaV2Object) ExpireRestore(now time.Time) (dataDir uint64, expired bool) {
+	if !z.IsTransitioned() || z.RestoreExpires == 0 || now.Unix() < z.RestoreExpires {
+		return 0, false
+	}
+	dataDir = z.DataDir
+	z.DataDir = 0
+	z.DataPartInfoChecksums = nil
+	z.RestoreExpires = 0
+	return dataDir, true
+}
+
+// validateTransition checks the transition state of z.
+func (z *ObjectMetaV2Object) validateTransition() error {
+	switch z.TransitionStatus {
+	case TransitionNone:
+		if z.TransitionTier != "" || z.TransitionedObject != "" || z.TransitionedVersionID != "" || z.RestoreExpires != 0 {
+			return fmt.Errorf("%w: remote object without transition", errInvalidTransition)
+		}
+		return nil
+	case TransitionComplete:
+	default:
+		return fmt.Errorf("%w: unknown status %d", errInvalidTransition, z.TransitionStatus)
+	}
+	if z.TransitionTier == "" || z.TransitionedObject == "" {
+		return fmt.Errorf("%w: tier %q, remote object %q", errInvalidTransition, z.TransitionTier, z.TransitionedObject)
+	}
+	if z.IsInline() {
+		return fmt.Errorf("%w: transitioned version with inline data", errInvalidTransition)
+	}
+	if z.hasLocalParts() {
+		if z.DataDir == 0 {
+			return fmt.Errorf("%w: restored version without a data directory", errInvalidTransition)
+		}
+		return nil
+	}
+	if z.DataDir != 0 || len(z.DataPartInfoChecksums) != 0 {
+		return fmt.Errorf("%w: transitioned version with local parts", errInvalidTransition)
+	}
+	return nil
+}
+
+// transitionObject returns the object of the version versionID,
+// which must not be a delete marker.
+func (z *ObjectMetaV2) transitionObject(versionID uint64) (*ObjectMetaV2Object, error) {
+	idx := z.FindVersion(versionID)
+	if idx < 0 {
+		return nil, errVersionNotFound
+	}
+	obj := z.ObjectJournals[idx].replicationObject()
+	if obj == nil {
+		return nil, fmt.Errorf("%w: version %d is a delete marker", errInvalidTransition, versionID)
+	}
+	return obj, nil
+}
+
+// TransitionVersion transitions the version versionID, see Transition.
+func (z *ObjectMetaV2) TransitionVersion(versionID uint64, tier, remoteObject, remoteVersion string) error {
+	obj, err := z.transitionObject(versionID)
+	if err != nil {
+		return err
+	}
+	return obj.Transition(tier, remoteObject, remoteVersion)
+}
+
+// RestoreVersion restores the transitioned version versionID, see Restore.
+func (z *ObjectMetaV2) RestoreVersion(versionID uint64, dataDir uint64, expires time.Time) error {
+	obj, err := z.transitionObject(versionID)
+	if err != nil {
+		return err
+	}
+	return obj.Restore(dataDir, expires)
+}
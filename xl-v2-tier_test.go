@@ -0,0 +1,172 @@
+package xlmeta
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestObjectMetaV2ObjectTransition(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	obj := newObjectMetaV2Object(4)
+	obj.DataErasureChecksumAlgo = SHA256
+	dir := writeTestParts(t, obj)
+	defer os.RemoveAll(dir)
+	if err := obj.ChecksumParts(dir); err != nil {
+		t.Fatal(err)
+	}
+	dataDir, size, rawSize := obj.DataDir, obj.StatSize, obj.RawSize()
+
+	if err := obj.Restore(1, now); err != errNotTransitioned {
+		t.Fatalf("restore before transition: want %v, got %v", errNotTransitioned, err)
+	}
+	if err := obj.Transition("", "remote", ""); !errors.Is(err, errInvalidTransition) {
+		t.Fatalf("transition without tier: want %v, got %v", errInvalidTransition, err)
+	}
+	if err := obj.Transition("WARM", "bucket/remote", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if !obj.IsTransitioned() || obj.IsRestored(now) || obj.StorageClass != "WARM" || obj.DataDir != 0 || obj.DataPartInfoChecksums != nil {
+		t.Fatalf("transitioned version: %+v", obj)
+	}
+	if len(obj.DataPartInfoNumbers) != 4 || obj.StatSize != size {
+		t.Fatalf("remote parts not kept: %v, size %d", obj.DataPartInfoNumbers, obj.StatSize)
+	}
+	if err := obj.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.ReadLayout(0, 1); err != errDataTransitioned {
+		t.Errorf("read layout: want %v, got %v", errDataTransitioned, err)
+	}
+	if err := obj.VerifyParts(dir); err != errDataTransitioned {
+		t.Errorf("verify parts: want %v, got %v", errDataTransitioned, err)
+	}
+	if obj.RawSize() != 0 {
+		t.Errorf("raw size of transitioned version: %d", obj.RawSize())
+	}
+	if err := obj.Transition("COLD", "bucket/remote", ""); !errors.Is(err, errInvalidTransition) {
+		t.Errorf("second transition: want %v, got %v", errInvalidTransition, err)
+	}
+
+	if err := obj.Restore(0, now); !errors.Is(err, errInvalidTransition) {
+		t.Errorf("restore without data directory: want %v, got %v", errInvalidTransition, err)
+	}
+	expires := now.Add(24 * time.Hour)
+	if err := obj.Restore(dataDir, expires); err != nil {
+		t.Fatal(err)
+	}
+	if !obj.IsRestored(now) || obj.IsRestored(expires) {
+		t.Errorf("restored until %v: restored at %v %v, at expiry %v", expires, now, obj.IsRestored(now), obj.IsRestored(expires))
+	}
+	if err := obj.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := obj.ReadLayout(0, 1); err != nil {
+		t.Errorf("read layout of restored version: %v", err)
+	}
+	if err := obj.ChecksumParts(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := obj.VerifyParts(dir); err != nil {
+		t.Errorf("verify restored parts: %v", err)
+	}
+	if obj.RawSize() != rawSize {
+		t.Errorf("raw size of restored version: got %d, want %d", obj.RawSize(), rawSize)
+	}
+
+	if _, expired := obj.ExpireRestore(now); expired {
+		t.Fatal("restore expired early")
+	}
+	if got, expired := obj.ExpireRestore(expires); !expired || got != dataDir {
+		t.Fatalf("expire restore: got %d, %v, want %d", got, expired, dataDir)
+	}
+	if !obj.IsTransitioned() || obj.DataDir != 0 || obj.DataPartInfoChecksums != nil || obj.RestoreExpires != 0 {
+		t.Fatalf("expired restore: %+v", obj)
+	}
+	if err := obj.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	inline := newObjectMetaV2Object(0)
+	if err := inline.SetInlineData([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := inline.Transition("WARM", "remote", ""); err != errDataInline {
+		t.Errorf("inline transition: want %v, got %v", errDataInline, err)
+	}
+}
+
+func TestObjectMetaV2ObjectValidateTransition(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(obj *ObjectMetaV2Object)
+	}{
+		{"tier without transition", func(obj *ObjectMetaV2Object) { obj.TransitionTier = "WARM" }},
+		{"restore without transition", func(obj *ObjectMetaV2Object) { obj.RestoreExpires = 1 }},
+		{"unknown status", func(obj *ObjectMetaV2Object) { obj.TransitionStatus = 7 }},
+		{"no tier", func(obj *ObjectMetaV2Object) {
+			obj.TransitionStatus, obj.TransitionedObject, obj.DataDir = TransitionComplete, "remote", 0
+		}},
+		{"no remote object", func(obj *ObjectMetaV2Object) {
+			obj.TransitionStatus, obj.TransitionTier, obj.DataDir = TransitionComplete, "WARM", 0
+		}},
+		{"local data directory", func(obj *ObjectMetaV2Object) {
+			obj.TransitionStatus, obj.TransitionTier, obj.TransitionedObject = TransitionComplete, "WARM", "remote"
+		}},
+		{"local checksums", func(obj *ObjectMetaV2Object) {
+			obj.TransitionStatus, obj.TransitionTier, obj.TransitionedObject, obj.DataDir = TransitionComplete, "WARM", "remote", 0
+			obj.DataPartInfoChecksums = make([][]byte, len(obj.DataPartInfoNumbers))
+		}},
+		{"restored without data directory", func(obj *ObjectMetaV2Object) {
+			obj.TransitionStatus, obj.TransitionTier, obj.TransitionedObject, obj.DataDir = TransitionComplete, "WARM", "remote", 0
+			obj.RestoreExpires = 1
+		}},
+	}
+	for _, tc := range testCases {
+		obj := newObjectMetaV2Object(2)
+		tc.modify(obj)
+		if err := obj.Validate(); !errors.Is(err, errInvalidTransition) {
+			t.Errorf("%s: want %v, got %v", tc.name, errInvalidTransition, err)
+		}
+	}
+}
+
+func TestObjectMetaV2TransitionVersion(t *testing.T) {
+	z := lifecycleJournal(0, -1, 2)
+	now := day(3)
+	if err := z.TransitionVersion(9, "WARM", "remote", ""); err != errVersionNotFound {
+		t.Errorf("missing version: want %v, got %v", errVersionNotFound, err)
+	}
+	if err := z.TransitionVersion(2, "WARM", "remote", ""); !errors.Is(err, errInvalidTransition) {
+		t.Errorf("delete marker: want %v, got %v", errInvalidTransition, err)
+	}
+	if err := z.RestoreVersion(1, 1, now); err != errNotTransitioned {
+		t.Errorf("restore: want %v, got %v", errNotTransitioned, err)
+	}
+	if err := z.TransitionVersion(1, "WARM", "remote", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.RestoreVersion(1, 42, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	obj := z.ObjectJournals[0].Object
+	if !obj.IsRestored(now) || obj.DataDir != 42 || obj.TransitionedVersionID != "v1" {
+		t.Fatalf("restored version: %+v", obj)
+	}
+	if z.ObjectJournals[2].Object.IsTransitioned() {
+		t.Error("other version transitioned")
+	}
+
+	buf, err := z.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ObjectMetaV2
+	if _, err := got.UnmarshalMsg(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !equalObjectMetaV2(&got, z) {
+		t.Errorf("round trip:\ngot  %+v\nwant %+v", got.ObjectJournals[0].Object, obj)
+	}
+}
@@ -0,0 +1,219 @@
+package xlmeta
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SizeClass is a range of object sizes in a SizeHistogram.
+type SizeClass struct {
+	Name     string
+	Min, Max int64 // Sizes in [Min, Max) belong to the class; Max is 0 for the last one.
+}
+
+// SizeClasses are the classes of SizeHistogram, as used by S3 dashboards.
+var SizeClasses = [...]SizeClass{
+	{"LESS_THAN_1024_B", 0, 1024},
+	{"BETWEEN_1024_B_AND_1_MB", 1024, 1 << 20},
+	{"BETWEEN_1_MB_AND_10_MB", 1 << 20, 10 << 20},
+	{"BETWEEN_10_MB_AND_64_MB", 10 << 20, 64 << 20},
+	{"BETWEEN_64_MB_AND_128_MB", 64 << 20, 128 << 20},
+	{"BETWEEN_128_MB_AND_512_MB", 128 << 20, 512 << 20},
+	{"GREATER_THAN_512_MB", 512 << 20, 0},
+}
+
+// SizeHistogram counts objects by the SizeClasses of their size.
+// It is written in JSON as an object of counts by class name.
+type SizeHistogram [len(SizeClasses)]int64
+
+// Add counts an object of size bytes.
+func (h *SizeHistogram) Add(size int64) {
+	for i, c := range SizeClasses {
+		if size < c.Max || c.Max == 0 {
+			h[i]++
+			return
+		}
+	}
+}
+
+// MarshalJSON implements json.Marshaler
+func (h SizeHistogram) MarshalJSON() ([]byte, error) {
+	m := make(map[string]int64, len(h))
+	for i, c := range SizeClasses {
+		m[c.Name] = h[i]
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (h *SizeHistogram) UnmarshalJSON(b []byte) error {
+	var m map[string]int64
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*h = SizeHistogram{}
+	for i, c := range SizeClasses {
+		h[i] = m[c.Name]
+	}
+	return nil
+}
+
+// ErasureConfig is the erasure configuration versions are stored with.
+// It is written in JSON as "EC:<M>+<N>:<block size>".
+type ErasureConfig struct {
+	M, N, BlockSize int
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (c ErasureConfig) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("EC:%d+%d:%d", c.M, c.N, c.BlockSize)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (c *ErasureConfig) UnmarshalText(b []byte) error {
+	if _, err := fmt.Sscanf(string(b), "EC:%d+%d:%d", &c.M, &c.N, &c.BlockSize); err != nil {
+		return fmt.Errorf("%w: %q", errInvalidErasure, b)
+	}
+	return nil
+}
+
+// ErasureUsage is the storage used by the versions of an erasure configuration.
+type ErasureUsage struct {
+	Versions int64 `json:"versions"`
+	Bytes    int64 `json:"bytes"`    // Object size.
+	RawBytes int64 `json:"rawBytes"` // Size of data and parity on all drives.
+}
+
+// StandardStorageClass is the storage class of versions stored without one.
+const StandardStorageClass = "STANDARD"
+
+// ClassUsage is the storage used by the versions of a storage class.
+type ClassUsage struct {
+	Versions int64 `json:"versions"`
+	Bytes    int64 `json:"bytes"`    // Object size.
+	RawBytes int64 `json:"rawBytes"` // Size of data and parity on all drives, none for unrestored tiered data.
+}
+
+// Usage accounts the storage of objects by version state, erasure
+// configuration and storage class. Usages of sets of objects are combined
+// with Merge.
+type Usage struct {
+	Objects         int64 `json:"objects"`  // Objects with versions.
+	Versions        int64 `json:"versions"` // Versions with data, delete markers excluded.
+	DeleteMarkers   int64 `json:"deleteMarkers"`
+	CurrentBytes    int64 `json:"currentBytes"`    // Size of the latest versions with data.
+	NoncurrentBytes int64 `json:"noncurrentBytes"` // Size of the older versions.
+	// Erasure is the usage of each erasure configuration, all versions included.
+	Erasure map[ErasureConfig]ErasureUsage `json:"erasure,omitempty"`
+	// StorageClass is the usage of each storage class, all versions included.
+	// Versions without a storage class count as StandardStorageClass and
+	// transitioned versions as their tier.
+	StorageClass map[string]ClassUsage `json:"storageClass,omitempty"`
+	// Sizes counts objects whose latest version has data by its size.
+	Sizes SizeHistogram `json:"sizes"`
+	// Errors counts objects whose metadata could not be read.
+	Errors int64 `json:"errors,omitempty"`
+}
+
+// Add accounts the versions of an object.
+func (u *Usage) Add(z *ObjectMetaV2) {
+	idxs := z.versionIndexes()
+	if len(idxs) == 0 {
+		return
+	}
+	u.Objects++
+	for n, idx := range idxs {
+		e := &z.ObjectJournals[idx]
+		current := n == len(idxs)-1
+		obj := e.Object
+		switch e.Type {
+		case Delete:
+			u.DeleteMarkers++
+			continue
+		case Link:
+			obj = (*ObjectMetaV2Object)(e.Link)
+		}
+		size := int64(obj.StatSize)
+		u.Versions++
+		if current {
+			u.CurrentBytes += size
+			u.Sizes.Add(size)
+		} else {
+			u.NoncurrentBytes += size
+		}
+		if u.Erasure == nil {
+			u.Erasure = make(map[ErasureConfig]ErasureUsage)
+		}
+		c := ErasureConfig{M: obj.DataErasureM, N: obj.DataErasureN, BlockSize: obj.DataErasureBlockSize}
+		eu := u.Erasure[c]
+		eu.Versions++
+		eu.Bytes += size
+		raw := obj.RawSize()
+		eu.RawBytes += raw
+		u.Erasure[c] = eu
+
+		if u.StorageClass == nil {
+			u.StorageClass = make(map[string]ClassUsage)
+		}
+		class := obj.StorageClass
+		if class == "" {
+			class = StandardStorageClass
+		}
+		cu := u.StorageClass[class]
+		cu.Versions++
+		cu.Bytes += size
+		cu.RawBytes += raw
+		u.StorageClass[class] = cu
+	}
+}
+
+// Merge adds the usage o to u.
+func (u *Usage) Merge(o *Usage) {
+	u.Objects += o.Objects
+	u.Versions += o.Versions
+	u.DeleteMarkers += o.DeleteMarkers
+	u.CurrentBytes += o.CurrentBytes
+	u.NoncurrentBytes += o.NoncurrentBytes
+	u.Errors += o.Errors
+	for i := range u.Sizes {
+		u.Sizes[i] += o.Sizes[i]
+	}
+	for c, oe := range o.Erasure {
+		if u.Erasure == nil {
+			u.Erasure = make(map[ErasureConfig]ErasureUsage, len(o.Erasure))
+		}
+		eu := u.Erasure[c]
+		eu.Versions += oe.Versions
+		eu.Bytes += oe.Bytes
+		eu.RawBytes += oe.RawBytes
+		u.Erasure[c] = eu
+	}
+	for class, oc := range o.StorageClass {
+		if u.StorageClass == nil {
+			u.StorageClass = make(map[string]ClassUsage, len(o.StorageClass))
+		}
+		cu := u.StorageClass[class]
+		cu.Versions += oc.Versions
+		cu.Bytes += oc.Bytes
+		cu.RawBytes += oc.RawBytes
+		u.StorageClass[class] = cu
+	}
+}
+
+// RawSize returns the bytes of data and parity stored for z on all drives.
+// Inline data is stored in the metadata of every drive; transitioned data
+// is only stored while restored.
+func (z *ObjectMetaV2Object) RawSize() int64 {
+	drives := int64(z.DataErasureM + z.DataErasureN)
+	if z.IsInline() {
+		return int64(z.Inline.Len()) * drives
+	}
+	if !z.hasLocalParts() || z.DataErasureM <= 0 || z.DataErasureBlockSize <= 0 {
+		return 0
+	}
+	var size int64
+	for _, s := range z.ShardFileSizes() {
+		size += s
+	}
+	return size * drives
+}
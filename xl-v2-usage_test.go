@@ -0,0 +1,159 @@
+package xlmeta
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// usageObject returns an object version of size bytes in one part
+// stored with erasure configuration m+n and 1 MiB blocks.
+func usageObject(id uint64, size, m, n int) ObjectMetaV2JournalEntry {
+	obj := newObjectMetaV2Object(1)
+	obj.VersionID, obj.StatModTime = id, int64(id)
+	obj.DataErasureM, obj.DataErasureN, obj.DataErasureBlockSize = m, n, 1<<20
+	obj.DataErasureDistribution = obj.DataErasureDistribution[:m+n]
+	obj.DataPartInfoSizes[0], obj.StatSize = size, size
+	return ObjectMetaV2JournalEntry{Type: Object, Object: obj}
+}
+
+func TestUsage(t *testing.T) {
+	inline := usageObject(3, 0, 2, 2)
+	if err := inline.Object.SetInlineData(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	z := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{
+		usageObject(1, 100, 4, 2),
+		{Type: Delete, DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: 2, ModTime: 2}},
+		inline,
+		{Type: Multipart, Multipart: &ObjectMetaV2Multipart{UploadID: "upload"}},
+		usageObject(4, 5<<20, 8, 8),
+	}}
+	deleted := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{
+		usageObject(5, 2000, 4, 2),
+		{Type: Delete, DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: 6, ModTime: 6}},
+	}}
+
+	var u Usage
+	u.Add(&z)
+	u.Add(&deleted)
+	u.Add(&ObjectMetaV2{})
+	want := Usage{
+		Objects:         2,
+		Versions:        4,
+		DeleteMarkers:   2,
+		CurrentBytes:    5 << 20,
+		NoncurrentBytes: 100 + 10 + 2000,
+		Erasure: map[ErasureConfig]ErasureUsage{
+			// Shard files of ceil(100/4) and ceil(2000/4) bytes on 6 drives.
+			{4, 2, 1 << 20}: {Versions: 2, Bytes: 2100, RawBytes: (25 + 500) * 6},
+			// Inline data in the metadata of 4 drives.
+			{2, 2, 1 << 20}: {Versions: 1, Bytes: 10, RawBytes: 10 * 4},
+			// 5 blocks of 1 MiB in 8 shards, on 16 drives.
+			{8, 8, 1 << 20}: {Versions: 1, Bytes: 5 << 20, RawBytes: 5 * (1 << 20) / 8 * 16},
+		},
+		StorageClass: map[string]ClassUsage{
+			StandardStorageClass: {Versions: 4, Bytes: 2100 + 10 + 5<<20, RawBytes: (25+500)*6 + 10*4 + 5*(1<<20)/8*16},
+		},
+	}
+	want.Sizes[2] = 1
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("got  %+v\nwant %+v", u, want)
+	}
+
+	// Merging usages of separate objects equals adding them together.
+	var a, b, merged Usage
+	a.Add(&z)
+	b.Add(&deleted)
+	b.Errors = 1
+	merged.Merge(&b)
+	merged.Merge(&a)
+	want.Errors = 1
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged\ngot  %+v\nwant %+v", merged, want)
+	}
+}
+
+func TestUsageStorageClass(t *testing.T) {
+	reduced := usageObject(2, 100, 4, 2)
+	reduced.Object.StorageClass = "REDUCED_REDUNDANCY"
+	z := ObjectMetaV2{ObjectJournals: []ObjectMetaV2JournalEntry{
+		usageObject(1, 1000, 4, 2),
+		reduced,
+		usageObject(3, 2000, 4, 2),
+	}}
+	usage := func() map[string]ClassUsage {
+		var u Usage
+		u.Add(&z)
+		return u.StorageClass
+	}
+	want := map[string]ClassUsage{
+		StandardStorageClass: {Versions: 2, Bytes: 3000, RawBytes: (250 + 500) * 6},
+		"REDUCED_REDUNDANCY": {Versions: 1, Bytes: 100, RawBytes: 25 * 6},
+	}
+	if got := usage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	// Transitioned versions count in their tier, without local data
+	// unless restored.
+	if err := z.ObjectJournals[0].Object.Transition("WARM-TIER", "remote-1", ""); err != nil {
+		t.Fatal(err)
+	}
+	want[StandardStorageClass] = ClassUsage{Versions: 1, Bytes: 2000, RawBytes: 500 * 6}
+	want["WARM-TIER"] = ClassUsage{Versions: 1, Bytes: 1000}
+	if got := usage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("transitioned\ngot  %+v\nwant %+v", got, want)
+	}
+	if err := z.ObjectJournals[0].Object.Restore(0x1234, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	want["WARM-TIER"] = ClassUsage{Versions: 1, Bytes: 1000, RawBytes: 250 * 6}
+	if got := usage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSizeHistogram(t *testing.T) {
+	var h SizeHistogram
+	for _, size := range []int64{0, 1023, 1024, 1<<20 - 1, 1 << 20, 10 << 20, 64 << 20, 128 << 20, 512<<20 - 1, 512 << 20, 5 << 40} {
+		h.Add(size)
+	}
+	if want := (SizeHistogram{2, 2, 1, 1, 1, 2, 2}); h != want {
+		t.Errorf("got %v, want %v", h, want)
+	}
+}
+
+func TestUsageJSON(t *testing.T) {
+	var u Usage
+	z := DefaultWorkload.Generate(50)
+	u.Add(&z)
+	u.Errors = 3
+	for _, api := range []jsoniter.API{jsoniter.ConfigCompatibleWithStandardLibrary, jsoniter.ConfigFastest} {
+		buf, err := api.Marshal(&u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range []string{`"EC:8+8:10485760":{`, `"LESS_THAN_1024_B":1`, `"currentBytes":`, `"storageClass":{"STANDARD":{`} {
+			if !strings.Contains(string(buf), s) {
+				t.Errorf("%s missing from %s", s, buf)
+			}
+		}
+		var got Usage
+		if err := json.Unmarshal(buf, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, u) {
+			t.Errorf("usage differs after a JSON round trip\ngot  %+v\nwant %+v", got, u)
+		}
+	}
+
+	var c ErasureConfig
+	if err := c.UnmarshalText([]byte("RS:8+8")); err == nil {
+		t.Error("invalid erasure configuration parsed")
+	}
+}
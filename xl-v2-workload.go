@@ -0,0 +1,223 @@
+package xlmeta
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// DistributionKind selects how a Distribution draws values.
+type DistributionKind uint8
+
+const (
+	DistFixed      DistributionKind = iota // Always Min.
+	DistUniform                            // Uniform between Min and Max.
+	DistLogUniform                         // Log-uniform between Min and Max, for values spanning orders of magnitude.
+)
+
+// Distribution draws integers between Min and Max, inclusive.
+type Distribution struct {
+	Kind     DistributionKind
+	Min, Max int64
+}
+
+// Fixed returns a Distribution that always draws v.
+func Fixed(v int64) Distribution {
+	return Distribution{Kind: DistFixed, Min: v, Max: v}
+}
+
+// Sample draws a value from d.
+func (d Distribution) Sample(rng *rand.Rand) int64 {
+	if d.Max <= d.Min {
+		return d.Min
+	}
+	switch d.Kind {
+	case DistUniform:
+		return d.Min + rng.Int63n(d.Max-d.Min+1)
+	case DistLogUniform:
+		lo, hi := math.Log(float64(d.Min)+1), math.Log(float64(d.Max)+1)
+		v := int64(math.Exp(lo+rng.Float64()*(hi-lo))) - 1
+		if v < d.Min {
+			return d.Min
+		}
+		if v > d.Max {
+			return d.Max
+		}
+		return v
+	}
+	return d.Min
+}
+
+// Workload describes the versions of a population of objects.
+// The same seed generates the same metadata.
+type Workload struct {
+	Seed int64
+
+	// Share of versions that are delete markers.
+	DeleteMarkerShare float64
+	// Share of object versions uploaded in parts of PartSize bytes,
+	// the last part being smaller. Other objects have a single part of
+	// ObjectSize bytes.
+	MultipartShare float64
+	ObjectSize     Distribution
+	PartCount      Distribution
+	PartSize       Distribution
+
+	// Number of user metadata keys besides content-type and etag.
+	UserMetaKeys Distribution
+
+	ErasureM, ErasureN int
+	BlockSize          int
+
+	// ModTime of the latest version, and seconds between versions.
+	ModTime         time.Time
+	VersionInterval Distribution
+}
+
+// DefaultWorkload models a versioned bucket of mostly small objects
+// with occasional large multipart uploads.
+var DefaultWorkload = Workload{
+	Seed:              1,
+	DeleteMarkerShare: 0.1,
+	MultipartShare:    0.1,
+	ObjectSize:        Distribution{Kind: DistLogUniform, Min: 1, Max: 128 << 20},
+	PartCount:         Distribution{Kind: DistLogUniform, Min: 2, Max: maxPartNumber},
+	PartSize:          Distribution{Kind: DistLogUniform, Min: minPartSize, Max: 512 << 20},
+	UserMetaKeys:      Distribution{Kind: DistLogUniform, Min: 0, Max: 20},
+	ErasureM:          8,
+	ErasureN:          8,
+	BlockSize:         10 << 20,
+	ModTime:           time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	VersionInterval:   Distribution{Kind: DistLogUniform, Min: 1, Max: 30 * 24 * 3600},
+}
+
+var contentTypes = []string{
+	"application/octet-stream",
+	"application/json",
+	"application/zip",
+	"text/plain",
+	"text/csv",
+	"image/jpeg",
+	"image/png",
+	"video/mp4",
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID(rng *rand.Rand) (u [16]byte) {
+	rng.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// uuidID returns the low 64 bits of a random UUID, as stored in VersionID and DataDir.
+func uuidID(rng *rand.Rand) uint64 {
+	u := newUUID(rng)
+	return binary.BigEndian.Uint64(u[8:])
+}
+
+// Generate returns metadata with nversions versions, oldest first.
+func (w Workload) Generate(nversions int) ObjectMetaV2 {
+	rng := rand.New(rand.NewSource(w.Seed))
+	z := ObjectMetaV2{Version: 200, Format: XL}
+	z.ObjectJournals = make([]ObjectMetaV2JournalEntry, nversions)
+	modTime := w.ModTime.Unix()
+	for i := nversions - 1; i >= 0; i-- {
+		if rng.Float64() < w.DeleteMarkerShare {
+			z.ObjectJournals[i] = ObjectMetaV2JournalEntry{
+				Type:         Delete,
+				DeleteMarker: &ObjectMetaV2DeleteMarker{VersionID: uuidID(rng), ModTime: modTime},
+			}
+		} else {
+			z.ObjectJournals[i] = ObjectMetaV2JournalEntry{
+				Type:   Object,
+				Object: w.object(rng, modTime),
+			}
+		}
+		modTime -= w.VersionInterval.Sample(rng)
+	}
+	return z
+}
+
+// object returns an object version modified at modTime.
+func (w Workload) object(rng *rand.Rand, modTime int64) *ObjectMetaV2Object {
+	drives := w.ErasureM + w.ErasureN
+	obj := &ObjectMetaV2Object{
+		VersionID:               uuidID(rng),
+		DataDir:                 uuidID(rng),
+		DataErasureAlgorithm:    ReedSolomon,
+		DataErasureM:            w.ErasureM,
+		DataErasureN:            w.ErasureN,
+		DataErasureBlockSize:    w.BlockSize,
+		DataErasureIndex:        rng.Intn(drives) + 1,
+		DataErasureChecksumAlgo: HighwayHash256S,
+		DataErasureDistribution: make([]uint8, drives),
+		StatModTime:             modTime,
+	}
+	for i, shard := range rng.Perm(drives) {
+		obj.DataErasureDistribution[i] = uint8(shard + 1)
+	}
+
+	parts := 1
+	if rng.Float64() < w.MultipartShare {
+		parts = int(w.PartCount.Sample(rng))
+	}
+	obj.DataPartInfoNumbers = make(DeltaEncodedInt, parts)
+	obj.DataPartInfoSizes = make(DeltaEncodedInt, parts)
+	if parts == 1 && w.MultipartShare < 1 {
+		obj.DataPartInfoNumbers[0] = 1
+		obj.DataPartInfoSizes[0] = int(w.ObjectSize.Sample(rng))
+	} else if parts > 0 {
+		partSize := int(w.PartSize.Sample(rng))
+		if partSize < 0 {
+			partSize = 0
+		}
+		for i := range obj.DataPartInfoNumbers {
+			obj.DataPartInfoNumbers[i] = i + 1
+			obj.DataPartInfoSizes[i] = partSize
+		}
+		// The last part is smaller, unless parts are empty.
+		if partSize > 0 {
+			obj.DataPartInfoSizes[parts-1] = 1 + rng.Intn(partSize)
+		}
+	}
+	for _, size := range obj.DataPartInfoSizes {
+		obj.StatSize += size
+	}
+
+	etag := make([]byte, md5.Size)
+	rng.Read(etag)
+	etagValue := hex.EncodeToString(etag)
+	if parts > 1 {
+		etagValue += "-" + strconv.Itoa(parts)
+	}
+	keys := int(w.UserMetaKeys.Sample(rng))
+	obj.MetaUser = make(map[string][]string, keys+2)
+	obj.MetaUser["content-type"] = []string{contentTypes[rng.Intn(len(contentTypes))]}
+	obj.MetaUser["etag"] = []string{etagValue}
+	for i := 0; i < keys; i++ {
+		value := make([]byte, 1+rng.Intn(32))
+		rng.Read(value)
+		obj.MetaUser[fmt.Sprintf("X-Amz-Meta-Key-%d", i)] = []string{hex.EncodeToString(value)}
+	}
+	obj.MetaSys = map[string][]byte{
+		"minio-release": []byte("DEVELOPMENT.GOGET"),
+		"mac":           []byte("hmac-sha256: xxxxxxxxxxxxxxxxxxxxxxx"),
+	}
+	return obj
+}
+
+// SampleWorkload returns DefaultWorkload without delete markers
+// and with object versions of nparts parts each.
+func SampleWorkload(nparts int) Workload {
+	w := DefaultWorkload
+	w.DeleteMarkerShare = 0
+	w.MultipartShare = 1
+	w.PartCount = Fixed(int64(nparts))
+	return w
+}
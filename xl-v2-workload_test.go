@@ -0,0 +1,131 @@
+package xlmeta
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDistributionSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	testCases := []Distribution{
+		Fixed(7),
+		{Kind: DistUniform, Min: 0, Max: 10},
+		{Kind: DistUniform, Min: -5, Max: 5},
+		{Kind: DistLogUniform, Min: 0, Max: 20},
+		{Kind: DistLogUniform, Min: 1, Max: 1 << 40},
+		{Kind: DistLogUniform, Min: 3, Max: 3},
+	}
+	for _, d := range testCases {
+		seen := make(map[int64]bool)
+		for i := 0; i < 10000; i++ {
+			v := d.Sample(rng)
+			if v < d.Min || v > d.Max {
+				t.Fatalf("%+v: sampled %d", d, v)
+			}
+			seen[v] = true
+		}
+		if want := d.Max - d.Min + 1; want <= 11 && int64(len(seen)) != want {
+			t.Errorf("%+v: sampled %d distinct values, want %d", d, len(seen), want)
+		}
+	}
+
+	// Log-uniform values are spread across orders of magnitude.
+	d := Distribution{Kind: DistLogUniform, Min: 1, Max: 1 << 30}
+	small := 0
+	for i := 0; i < 10000; i++ {
+		if d.Sample(rng) < 1<<15 {
+			small++
+		}
+	}
+	if small < 4000 || small > 6000 {
+		t.Errorf("log-uniform: %d of 10000 samples below the geometric mean", small)
+	}
+}
+
+func TestWorkloadGenerate(t *testing.T) {
+	w := DefaultWorkload
+	z := w.Generate(2000)
+	if !reflect.DeepEqual(z, w.Generate(2000)) {
+		t.Fatal("same seed generated different metadata")
+	}
+	w.Seed++
+	if reflect.DeepEqual(z, w.Generate(2000)) {
+		t.Fatal("different seeds generated the same metadata")
+	}
+
+	var deleteMarkers, multipart int
+	ids := make(map[uint64]bool)
+	var prev int64
+	for i, e := range z.ObjectJournals {
+		var id uint64
+		var modTime int64
+		switch e.Type {
+		case Delete:
+			deleteMarkers++
+			id, modTime = e.DeleteMarker.VersionID, e.DeleteMarker.ModTime
+		case Object:
+			if err := e.Object.Validate(); err != nil {
+				t.Fatalf("version %d: %v", i, err)
+			}
+			if len(e.Object.DataPartInfoNumbers) > 1 {
+				multipart++
+			}
+			total := 0
+			for _, size := range e.Object.DataPartInfoSizes {
+				total += size
+			}
+			if total != e.Object.StatSize {
+				t.Fatalf("version %d: size %d, parts add up to %d", i, e.Object.StatSize, total)
+			}
+			if len(e.Object.MetaUser["etag"]) != 1 || len(e.Object.MetaUser["content-type"]) != 1 {
+				t.Fatalf("version %d: missing etag or content-type", i)
+			}
+			id, modTime = e.Object.VersionID, e.Object.StatModTime
+		default:
+			t.Fatalf("version %d: unexpected type %d", i, e.Type)
+		}
+		if ids[id] {
+			t.Fatalf("version %d: duplicate version ID", i)
+		}
+		ids[id] = true
+		if i > 0 && modTime < prev {
+			t.Fatalf("version %d: modified before the version it replaces", i)
+		}
+		prev = modTime
+	}
+	if prev != DefaultWorkload.ModTime.Unix() {
+		t.Errorf("latest version modified at %d, want %d", prev, DefaultWorkload.ModTime.Unix())
+	}
+	if deleteMarkers < 150 || deleteMarkers > 250 {
+		t.Errorf("%d delete markers in 2000 versions, want about 200", deleteMarkers)
+	}
+	if objects := len(z.ObjectJournals) - deleteMarkers; multipart < objects/20 || multipart > objects/5 {
+		t.Errorf("%d multipart objects in %d objects, want about 10%%", multipart, objects)
+	}
+}
+
+func TestWorkloadEmptyParts(t *testing.T) {
+	w := SampleWorkload(3)
+	w.PartSize = Fixed(0)
+	z := w.Generate(5)
+	for i, e := range z.ObjectJournals {
+		if obj := e.Object; len(obj.DataPartInfoSizes) != 3 || obj.StatSize != 0 {
+			t.Fatalf("version %d: want 3 empty parts, got sizes %v", i, obj.DataPartInfoSizes)
+		}
+	}
+}
+
+func TestGetSampleObjectMetaV2(t *testing.T) {
+	for _, nparts := range []int{0, 1, 3, 100} {
+		z := getSampleObjectMetaV2(nparts, 20)
+		if len(z.ObjectJournals) != 20 {
+			t.Fatalf("%d versions, want 20", len(z.ObjectJournals))
+		}
+		for i, e := range z.ObjectJournals {
+			if e.Type != Object || len(e.Object.DataPartInfoNumbers) != nparts || e.Object.DataErasureM != 8 {
+				t.Fatalf("version %d: want an object of %d parts with 8 data shards", i, nparts)
+			}
+		}
+	}
+}
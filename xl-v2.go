@@ -1,4 +1,4 @@
-package main
+package xlmeta
 
 import (
 	"errors"
@@ -21,9 +21,10 @@ const (
 type JournalType uint8
 
 const (
-	Object JournalType = 0
-	Delete JournalType = 1
-	Link   JournalType = 2
+	Object    JournalType = 0
+	Delete    JournalType = 1
+	Link      JournalType = 2
+	Multipart JournalType = 3
 )
 
 type ErasureAlgo uint8
@@ -36,11 +37,70 @@ type ChecksumAlgo uint8
 
 const (
 	HighwayHash256S ChecksumAlgo = iota
+	SHA256
+	CRC32C
 )
 
+type RetentionMode uint8
+
+const (
+	RetentionNone RetentionMode = iota
+	RetentionGovernance
+	RetentionCompliance
+)
+
+// TransitionStatus is the state of the transition of a version to a remote tier.
+type TransitionStatus uint8
+
+const (
+	TransitionNone TransitionStatus = iota
+	TransitionComplete
+)
+
+type ReplicationStatus uint8
+
+const (
+	ReplicationPending ReplicationStatus = iota
+	ReplicationCompleted
+	ReplicationFailed
+	ReplicationReplica
+)
+
+// ObjectMetaV2Replication is the replication state of a version for a single target.
+type ObjectMetaV2Replication struct {
+	ARN     string            `json:"arn" msg:"arn"`
+	Status  ReplicationStatus `json:"st" msg:"st"`
+	ModTime int64             `json:"mtime" msg:"mtime"`                       // Time of the last status change.
+	Resync  string            `json:"resync,omitempty" msg:"resync,omitempty"` // ID of the last resync that queued the version.
+}
+
+type SSEType uint8
+
+const (
+	SSENone SSEType = iota
+	SSES3
+	SSEKMS
+	SSEC
+)
+
+// ObjectMetaV2SSE holds the server-side encryption state of an object.
+// Object data is encrypted with DARE using an object key sealed with
+// the client key (SSE-C) or a KMS data key (SSE-S3 and SSE-KMS).
+type ObjectMetaV2SSE struct {
+	Type      SSEType         `json:"type" msg:"type"`
+	Algorithm string          `json:"algo" msg:"algo"`                     // Algorithm that sealed the object key.
+	SealedKey []byte          `json:"okey" msg:"okey"`                     // Sealed object key.
+	IV        []byte          `json:"iv" msg:"iv"`                         // IV used to seal the object key.
+	KeyID     string          `json:"kid,omitempty" msg:"kid,omitempty"`   // KMS key ID, SSE-S3 and SSE-KMS only.
+	DataKey   []byte          `json:"dkey,omitempty" msg:"dkey,omitempty"` // KMS sealed data key, SSE-S3 and SSE-KMS only.
+	Context   []byte          `json:"ctx,omitempty" msg:"ctx,omitempty"`   // KMS context, SSE-KMS only.
+	PartSizes DeltaEncodedInt `json:"psz" msg:"psz"`                       // Encrypted size of each part as stored.
+}
+
 type ObjectMetaV2DeleteMarker struct {
-	VersionID uint64 `json:"id" msg:"id"`
-	ModTime   int64  `json:"mtime" msg:"mtime"`
+	VersionID   uint64                    `json:"id" msg:"id"`
+	ModTime     int64                     `json:"mtime" msg:"mtime"`
+	Replication []ObjectMetaV2Replication `json:"repl,omitempty" msg:"repl,omitempty"` // Per target replication state, sorted by ARN.
 }
 
 // DeltaEncodedInt is an integer array that will be serialized as delta-encoded values.
@@ -48,36 +108,70 @@ type ObjectMetaV2DeleteMarker struct {
 type DeltaEncodedInt []int
 
 type ObjectMetaV2Object struct {
-	VersionID               uint64              `json:"id" msg:"id"`
-	DataDir                 uint64              `json:"dd" msg:"dd"`
-	DataErasureAlgorithm    ErasureAlgo         `json:"ealgo" msg:"ealgo"`
-	DataErasureM            int                 `json:"m" msg:"m"`
-	DataErasureN            int                 `json:"n" msg:"n"`
-	DataErasureBlockSize    int                 `json:"bsize" msg:"bsize"`
-	DataErasureIndex        int                 `json:"index" msg:"index"`
-	DataErasureDistribution []uint8             `json:"dist" msg:"dist"`
-	DataErasureChecksumAlgo ChecksumAlgo        `json:"calgo" msg:"clago"`
-	DataPartInfoNumbers     DeltaEncodedInt     `json:"pnum" msg:"pnum"`
-	DataPartInfoSizes       DeltaEncodedInt     `json:"psz" msg:"psz"`
-	StatSize                int                 `json:"size" msg:"size"`
-	StatModTime             int64               `json:"mtime" msg:"mtime"`
-	MetaSys                 map[string][]byte   `json:"msys" msg:"msys,omitempty"`
-	MetaUser                map[string][]string `json:"muser" msg:"muser,omitempty"`
+	VersionID               uint64                    `json:"id" msg:"id"`
+	DataDir                 uint64                    `json:"dd" msg:"dd"`
+	DataErasureAlgorithm    ErasureAlgo               `json:"ealgo" msg:"ealgo"`
+	DataErasureM            int                       `json:"m" msg:"m"`
+	DataErasureN            int                       `json:"n" msg:"n"`
+	DataErasureBlockSize    int                       `json:"bsize" msg:"bsize"`
+	DataErasureIndex        int                       `json:"index" msg:"index"`
+	DataErasureDistribution []uint8                   `json:"dist" msg:"dist"`
+	DataErasureChecksumAlgo ChecksumAlgo              `json:"calgo" msg:"clago"`
+	DataPartInfoNumbers     DeltaEncodedInt           `json:"pnum" msg:"pnum"`
+	DataPartInfoSizes       DeltaEncodedInt           `json:"psz" msg:"psz"`
+	DataPartInfoChecksums   [][]byte                  `json:"pcsum,omitempty" msg:"pcsum,omitempty"`   // Checksum of each part file, using DataErasureChecksumAlgo.
+	Inline                  *InlineData               `json:"inline,omitempty" msg:"inline,omitempty"` // Object data, when stored without a DataDir.
+	RetentionMode           RetentionMode             `json:"rmode,omitempty" msg:"rmode,omitempty"`
+	RetainUntil             int64                     `json:"runtil,omitempty" msg:"runtil,omitempty"`
+	LegalHold               bool                      `json:"lhold,omitempty" msg:"lhold,omitempty"`
+	Replication             []ObjectMetaV2Replication `json:"repl,omitempty" msg:"repl,omitempty"`   // Per target replication state, sorted by ARN.
+	SSE                     *ObjectMetaV2SSE          `json:"sse,omitempty" msg:"sse,omitempty"`     // Server-side encryption, DataPartInfoSizes are then decrypted sizes.
+	StorageClass            string                    `json:"sc,omitempty" msg:"sc,omitempty"`       // Empty for the default storage class.
+	TransitionStatus        TransitionStatus          `json:"tst,omitempty" msg:"tst,omitempty"`     // TransitionComplete when the data is stored in TransitionTier.
+	TransitionTier          string                    `json:"ttier,omitempty" msg:"ttier,omitempty"` // Name of the remote tier.
+	TransitionedObject      string                    `json:"tobj,omitempty" msg:"tobj,omitempty"`   // Key of the data in the remote tier.
+	TransitionedVersionID   string                    `json:"tvid,omitempty" msg:"tvid,omitempty"`   // Version of the data in the remote tier, if versioned.
+	RestoreExpires          int64                     `json:"rexp,omitempty" msg:"rexp,omitempty"`   // Expiry of the local copy of restored transitioned data.
+	StatSize                int                       `json:"size" msg:"size"`
+	StatModTime             int64                     `json:"mtime" msg:"mtime"`
+	MetaSys                 map[string][]byte         `json:"msys" msg:"msys,omitempty"`
+	MetaUser                map[string][]string       `json:"muser" msg:"muser,omitempty"`
 }
 
 type ObjectMetaV2Link ObjectMetaV2Object
 
+type ObjectMetaV2MultipartPart struct {
+	Number  int    `json:"n" msg:"n"`
+	Size    int    `json:"size" msg:"size"`
+	ETag    string `json:"etag" msg:"etag"`
+	ModTime int64  `json:"mtime" msg:"mtime"`
+}
+
+// ObjectMetaV2Multipart is an in-progress multipart upload.
+type ObjectMetaV2Multipart struct {
+	UploadID  string                      `json:"uid" msg:"uid"`
+	Initiated int64                       `json:"init" msg:"init"`
+	Parts     []ObjectMetaV2MultipartPart `json:"parts" msg:"parts"`   // Uploaded parts, sorted by part number.
+	Object    ObjectMetaV2Object          `json:"object" msg:"object"` // Object the upload completes into, without part information.
+}
+
 type ObjectMetaV2JournalEntry struct {
 	Type         JournalType               `json:"type" msg:"type"`
 	DeleteMarker *ObjectMetaV2DeleteMarker `json:"delete,omitempty" msg:"delete,omitempty"`
 	Object       *ObjectMetaV2Object       `json:"object,omitempty" msg:"object,omitempty"`
 	Link         *ObjectMetaV2Link         `json:"link,omitempty" msg:"link,omitempty"`
+	Multipart    *ObjectMetaV2Multipart    `json:"mpart,omitempty" msg:"mpart,omitempty"`
 }
 
+// ObjectMetaV2 is the content of xl.meta.
+// Its msgp methods are written by hand to check lengths before decoding.
+//msgp:ignore ObjectMetaV2
 type ObjectMetaV2 struct {
 	Version        int64                      `json:"v" msg:"v"`     // Version of the current `object.json`.
 	Format         Format                     `json:"fmt" msg:"fmt"` // Format of the current `object.json`.
 	ObjectJournals []ObjectMetaV2JournalEntry `json:"ojs" msg:"ojs"`
+
+	free []*ObjectMetaV2Object // emptied objects kept by Reset for reuse.
 }
 
 func newObjectMetaV2Object(nparts int) *ObjectMetaV2Object {
@@ -134,34 +228,25 @@ func newObjectMetaV2Object(nparts int) *ObjectMetaV2Object {
 	return obj
 }
 
-func newObjectMetaV2JournalEntry(nparts int) ObjectMetaV2JournalEntry {
-	journal := ObjectMetaV2JournalEntry{
-		Type:   Object,
-		Object: newObjectMetaV2Object(nparts),
-	}
-	return journal
-}
-
-func newObjectMetaV2(nparts int, nversions int) ObjectMetaV2 {
-	ObjectMeta := ObjectMetaV2{}
-	ObjectMeta.Format = XL
-	ObjectMeta.Version = 200
-	ObjectMeta.ObjectJournals = make([]ObjectMetaV2JournalEntry, nversions)
-	for i := 0; i < nversions; i++ {
-		ObjectMeta.ObjectJournals[i] = newObjectMetaV2JournalEntry(nparts)
-	}
-	return ObjectMeta
-}
-
+// getSampleObjectMetaV2 returns nversions object versions of nparts parts each,
+// generated by DefaultWorkload without delete markers.
 func getSampleObjectMetaV2(nparts int, nversions int) ObjectMetaV2 {
-	return newObjectMetaV2(nparts, nversions)
+	return SampleWorkload(nparts).Generate(nversions)
 }
 
-// GetJournalEntryN returns journal entry n.
+// GetJournalEntryN returns version n of the journal, oldest first.
+// Multipart uploads are not versions and are skipped.
 // z will be filled with the global information, but z.Journals will not be filled.
-// Specify version -1 to get the last version.
+// Specify version -1 to get the latest version.
 // An optional destination can be supplied.
+// Declared lengths are checked against the size of bts; no limits are applied.
 func (z *ObjectMetaV2) GetJournalEntryN(bts []byte, n int, dst *ObjectMetaV2JournalEntry) (journal *ObjectMetaV2JournalEntry, err error) {
+	return z.GetJournalEntryNWithOptions(bts, n, dst, DecodeOptions{})
+}
+
+// GetJournalEntryNWithOptions is GetJournalEntryN with the limits of opts.
+// Only the entries up to n are checked; MaxAlloc applies to each entry.
+func (z *ObjectMetaV2) GetJournalEntryNWithOptions(bts []byte, n int, dst *ObjectMetaV2JournalEntry, opts DecodeOptions) (journal *ObjectMetaV2JournalEntry, err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -194,37 +279,36 @@ func (z *ObjectMetaV2) GetJournalEntryN(bts []byte, n int, dst *ObjectMetaV2Jour
 				}
 				z.Format = Format(zb0002)
 			}
-		case "journals":
+		case "ojs":
 			var zb0003 uint32
 			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "ObjectJournals")
 				return
 			}
-			if n < 0 {
-				// last entry
-				n = int(zb0003) - 1
-			}
-			if n > int(zb0003)-1 {
-				err = msgp.WrapError(errors.New("requested object index not found"), "ObjectJournals", zb0003)
+			if err = exceeds("MaxVersions", int64(opts.MaxVersions), int64(zb0003)); err != nil {
 				return
 			}
 			if dst == nil {
 				dst = &ObjectMetaV2JournalEntry{}
 			}
-			for n >= 0 {
-				// Actually decoding is faster than skipping....
-				bts, err = dst.UnmarshalMsg(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "ObjectJournals")
-					return
-				}
-				if n == 0 {
-					journal = dst
-					return
-				}
-				n--
+			var entry []byte
+			entry, err = journalVersionN(bts, zb0003, n, &opts)
+			if err != nil {
+				err = wrapDecodeError(err, "ObjectJournals")
+				return
+			}
+			if entry == nil {
+				err = msgp.WrapError(errors.New("requested object index not found"), "ObjectJournals", zb0003)
+				return
 			}
+			_, err = dst.UnmarshalMsg(entry)
+			if err != nil {
+				err = msgp.WrapError(err, "ObjectJournals")
+				return
+			}
+			journal = dst
+			return
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -236,6 +320,59 @@ func (z *ObjectMetaV2) GetJournalEntryN(bts []byte, n int, dst *ObjectMetaV2Jour
 	return
 }
 
+// journalVersionN returns the serialized entry of version n of the count
+// journal entries at the start of bts, -1 being the latest version, or nil
+// if there is no such version. The entries up to it are checked with opts.
+func journalVersionN(bts []byte, count uint32, n int, opts *DecodeOptions) ([]byte, error) {
+	entries := bts
+	var last []byte
+	for i := uint32(0); i < count; i++ {
+		entry := bts
+		var err error
+		if bts, err = checkEntryLengths(bts, opts); err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			last = entry[:len(entry)-len(bts)]
+			continue
+		}
+		_, version, err := entryModTime(entry[:len(entry)-len(bts)])
+		if err != nil {
+			return nil, msgp.WrapError(err, i)
+		}
+		if version {
+			if n == 0 {
+				return entry, nil
+			}
+			n--
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+	// The latest version is almost always the last entry; look for it
+	// again only when the journal ends with multipart uploads.
+	if _, version, err := entryModTime(last); err != nil || version {
+		return last, err
+	}
+	var latest []byte
+	for i := uint32(0); i < count; i++ {
+		entry := entries
+		var err error
+		if entries, err = msgp.Skip(entries); err != nil {
+			return nil, err
+		}
+		_, version, err := entryModTime(entry[:len(entry)-len(entries)])
+		if err != nil {
+			return nil, msgp.WrapError(err, i)
+		}
+		if version {
+			latest = entry
+		}
+	}
+	return latest, nil
+}
+
 // DecodeMsg implements msgp.Decodable
 func (z *DeltaEncodedInt) DecodeMsg(dc *msgp.Reader) (err error) {
 	var zb0002 uint32
@@ -244,21 +381,24 @@ func (z *DeltaEncodedInt) DecodeMsg(dc *msgp.Reader) (err error) {
 		err = msgp.WrapError(err)
 		return
 	}
+	// The stream length is unknown, so grow with the values actually read.
 	if cap((*z)) >= int(zb0002) {
-		(*z) = (*z)[:zb0002]
+		(*z) = (*z)[:0]
+	} else if zb0002 <= 1<<10 {
+		(*z) = make(DeltaEncodedInt, 0, zb0002)
 	} else {
-		(*z) = make(DeltaEncodedInt, zb0002)
+		(*z) = make(DeltaEncodedInt, 0, 1<<10)
 	}
 	var c int
-	for zb0001 := range *z {
+	for zb0001 := 0; zb0001 < int(zb0002); zb0001++ {
 		var v int
 		v, err = dc.ReadInt()
-		c += v
-		(*z)[zb0001] = c
 		if err != nil {
 			err = msgp.WrapError(err, zb0001)
 			return
 		}
+		c += v
+		(*z) = append((*z), c)
 	}
 	return
 }
@@ -304,6 +444,11 @@ func (z *DeltaEncodedInt) UnmarshalMsg(bts []byte) (o []byte, err error) {
 		err = msgp.WrapError(err)
 		return
 	}
+	// Every value takes at least one byte.
+	if uint64(zb0002) > uint64(len(bts)) {
+		err = msgp.WrapError(errMsgLength)
+		return
+	}
 	if cap((*z)) >= int(zb0002) {
 		(*z) = (*z)[:zb0002]
 	} else {
@@ -313,7 +458,8 @@ func (z *DeltaEncodedInt) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	for zb0001 := range *z {
 		var v int
 		v, bts, err = msgp.ReadIntBytes(bts)
-		(*z)[zb0001] = c + v
+		c += v
+		(*z)[zb0001] = c
 		if err != nil {
 			err = msgp.WrapError(err, zb0001)
 			return
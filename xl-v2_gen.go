@@ -1,4 +1,4 @@
-package main
+package xlmeta
 
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 
@@ -215,7 +215,7 @@ func (z JournalType) Msgsize() (s int) {
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *ObjectMetaV2) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *ObjectMetaV2DeleteMarker) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -232,38 +232,34 @@ func (z *ObjectMetaV2) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "v":
-			z.Version, err = dc.ReadInt64()
+		case "id":
+			z.VersionID, err = dc.ReadUint64()
 			if err != nil {
-				err = msgp.WrapError(err, "Version")
+				err = msgp.WrapError(err, "VersionID")
 				return
 			}
-		case "fmt":
-			{
-				var zb0002 uint8
-				zb0002, err = dc.ReadUint8()
-				if err != nil {
-					err = msgp.WrapError(err, "Format")
-					return
-				}
-				z.Format = Format(zb0002)
+		case "mtime":
+			z.ModTime, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "ModTime")
+				return
 			}
-		case "ojs":
-			var zb0003 uint32
-			zb0003, err = dc.ReadArrayHeader()
+		case "repl":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
 			if err != nil {
-				err = msgp.WrapError(err, "ObjectJournals")
+				err = msgp.WrapError(err, "Replication")
 				return
 			}
-			if cap(z.ObjectJournals) >= int(zb0003) {
-				z.ObjectJournals = (z.ObjectJournals)[:zb0003]
+			if cap(z.Replication) >= int(zb0002) {
+				z.Replication = (z.Replication)[:zb0002]
 			} else {
-				z.ObjectJournals = make([]ObjectMetaV2JournalEntry, zb0003)
+				z.Replication = make([]ObjectMetaV2Replication, zb0002)
 			}
-			for za0001 := range z.ObjectJournals {
-				err = z.ObjectJournals[za0001].DecodeMsg(dc)
+			for za0001 := range z.Replication {
+				err = z.Replication[za0001].DecodeMsg(dc)
 				if err != nil {
-					err = msgp.WrapError(err, "ObjectJournals", za0001)
+					err = msgp.WrapError(err, "Replication", za0001)
 					return
 				}
 			}
@@ -279,179 +275,57 @@ func (z *ObjectMetaV2) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *ObjectMetaV2) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 3
-	// write "v"
-	err = en.Append(0x83, 0xa1, 0x76)
-	if err != nil {
-		return
+func (z *ObjectMetaV2DeleteMarker) EncodeMsg(en *msgp.Writer) (err error) {
+	// omitempty: check for empty values
+	zb0001Len := uint32(3)
+	var zb0001Mask uint8 /* 3 bits */
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4
 	}
-	err = en.WriteInt64(z.Version)
+	// variable map header, size zb0001Len
+	err = en.Append(0x80 | uint8(zb0001Len))
 	if err != nil {
-		err = msgp.WrapError(err, "Version")
 		return
 	}
-	// write "fmt"
-	err = en.Append(0xa3, 0x66, 0x6d, 0x74)
-	if err != nil {
+	if zb0001Len == 0 {
 		return
 	}
-	err = en.WriteUint8(uint8(z.Format))
+	// write "id"
+	err = en.Append(0xa2, 0x69, 0x64)
 	if err != nil {
-		err = msgp.WrapError(err, "Format")
 		return
 	}
-	// write "ojs"
-	err = en.Append(0xa3, 0x6f, 0x6a, 0x73)
+	err = en.WriteUint64(z.VersionID)
 	if err != nil {
+		err = msgp.WrapError(err, "VersionID")
 		return
 	}
-	err = en.WriteArrayHeader(uint32(len(z.ObjectJournals)))
+	// write "mtime"
+	err = en.Append(0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
 	if err != nil {
-		err = msgp.WrapError(err, "ObjectJournals")
 		return
 	}
-	for za0001 := range z.ObjectJournals {
-		err = z.ObjectJournals[za0001].EncodeMsg(en)
-		if err != nil {
-			err = msgp.WrapError(err, "ObjectJournals", za0001)
-			return
-		}
-	}
-	return
-}
-
-// MarshalMsg implements msgp.Marshaler
-func (z *ObjectMetaV2) MarshalMsg(b []byte) (o []byte, err error) {
-	o = msgp.Require(b, z.Msgsize())
-	// map header, size 3
-	// string "v"
-	o = append(o, 0x83, 0xa1, 0x76)
-	o = msgp.AppendInt64(o, z.Version)
-	// string "fmt"
-	o = append(o, 0xa3, 0x66, 0x6d, 0x74)
-	o = msgp.AppendUint8(o, uint8(z.Format))
-	// string "ojs"
-	o = append(o, 0xa3, 0x6f, 0x6a, 0x73)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.ObjectJournals)))
-	for za0001 := range z.ObjectJournals {
-		o, err = z.ObjectJournals[za0001].MarshalMsg(o)
-		if err != nil {
-			err = msgp.WrapError(err, "ObjectJournals", za0001)
-			return
-		}
-	}
-	return
-}
-
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *ObjectMetaV2) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	var field []byte
-	_ = field
-	var zb0001 uint32
-	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	err = en.WriteInt64(z.ModTime)
 	if err != nil {
-		err = msgp.WrapError(err)
+		err = msgp.WrapError(err, "ModTime")
 		return
 	}
-	for zb0001 > 0 {
-		zb0001--
-		field, bts, err = msgp.ReadMapKeyZC(bts)
+	if (zb0001Mask & 0x4) == 0 { // if not empty
+		// write "repl"
+		err = en.Append(0xa4, 0x72, 0x65, 0x70, 0x6c)
 		if err != nil {
-			err = msgp.WrapError(err)
 			return
 		}
-		switch msgp.UnsafeString(field) {
-		case "v":
-			z.Version, bts, err = msgp.ReadInt64Bytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "Version")
-				return
-			}
-		case "fmt":
-			{
-				var zb0002 uint8
-				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "Format")
-					return
-				}
-				z.Format = Format(zb0002)
-			}
-		case "ojs":
-			var zb0003 uint32
-			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "ObjectJournals")
-				return
-			}
-			if cap(z.ObjectJournals) >= int(zb0003) {
-				z.ObjectJournals = (z.ObjectJournals)[:zb0003]
-			} else {
-				z.ObjectJournals = make([]ObjectMetaV2JournalEntry, zb0003)
-			}
-			for za0001 := range z.ObjectJournals {
-				bts, err = z.ObjectJournals[za0001].UnmarshalMsg(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "ObjectJournals", za0001)
-					return
-				}
-			}
-		default:
-			bts, err = msgp.Skip(bts)
-			if err != nil {
-				err = msgp.WrapError(err)
-				return
-			}
-		}
-	}
-	o = bts
-	return
-}
-
-// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *ObjectMetaV2) Msgsize() (s int) {
-	s = 1 + 2 + msgp.Int64Size + 4 + msgp.Uint8Size + 4 + msgp.ArrayHeaderSize
-	for za0001 := range z.ObjectJournals {
-		s += z.ObjectJournals[za0001].Msgsize()
-	}
-	return
-}
-
-// DecodeMsg implements msgp.Decodable
-func (z *ObjectMetaV2DeleteMarker) DecodeMsg(dc *msgp.Reader) (err error) {
-	var field []byte
-	_ = field
-	var zb0001 uint32
-	zb0001, err = dc.ReadMapHeader()
-	if err != nil {
-		err = msgp.WrapError(err)
-		return
-	}
-	for zb0001 > 0 {
-		zb0001--
-		field, err = dc.ReadMapKeyPtr()
+		err = en.WriteArrayHeader(uint32(len(z.Replication)))
 		if err != nil {
-			err = msgp.WrapError(err)
+			err = msgp.WrapError(err, "Replication")
 			return
 		}
-		switch msgp.UnsafeString(field) {
-		case "id":
-			z.VersionID, err = dc.ReadUint64()
-			if err != nil {
-				err = msgp.WrapError(err, "VersionID")
-				return
-			}
-		case "mtime":
-			z.ModTime, err = dc.ReadInt64()
-			if err != nil {
-				err = msgp.WrapError(err, "ModTime")
-				return
-			}
-		default:
-			err = dc.Skip()
+		for za0001 := range z.Replication {
+			err = z.Replication[za0001].EncodeMsg(en)
 			if err != nil {
-				err = msgp.WrapError(err)
+				err = msgp.WrapError(err, "Replication", za0001)
 				return
 			}
 		}
@@ -459,42 +333,39 @@ func (z *ObjectMetaV2DeleteMarker) DecodeMsg(dc *msgp.Reader) (err error) {
 	return
 }
 
-// EncodeMsg implements msgp.Encodable
-func (z ObjectMetaV2DeleteMarker) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 2
-	// write "id"
-	err = en.Append(0x82, 0xa2, 0x69, 0x64)
-	if err != nil {
-		return
-	}
-	err = en.WriteUint64(z.VersionID)
-	if err != nil {
-		err = msgp.WrapError(err, "VersionID")
-		return
-	}
-	// write "mtime"
-	err = en.Append(0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
-	if err != nil {
-		return
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2DeleteMarker) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(3)
+	var zb0001Mask uint8 /* 3 bits */
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4
 	}
-	err = en.WriteInt64(z.ModTime)
-	if err != nil {
-		err = msgp.WrapError(err, "ModTime")
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len == 0 {
 		return
 	}
-	return
-}
-
-// MarshalMsg implements msgp.Marshaler
-func (z ObjectMetaV2DeleteMarker) MarshalMsg(b []byte) (o []byte, err error) {
-	o = msgp.Require(b, z.Msgsize())
-	// map header, size 2
 	// string "id"
-	o = append(o, 0x82, 0xa2, 0x69, 0x64)
+	o = append(o, 0xa2, 0x69, 0x64)
 	o = msgp.AppendUint64(o, z.VersionID)
 	// string "mtime"
 	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
 	o = msgp.AppendInt64(o, z.ModTime)
+	if (zb0001Mask & 0x4) == 0 { // if not empty
+		// string "repl"
+		o = append(o, 0xa4, 0x72, 0x65, 0x70, 0x6c)
+		o = msgp.AppendArrayHeader(o, uint32(len(z.Replication)))
+		for za0001 := range z.Replication {
+			o, err = z.Replication[za0001].MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication", za0001)
+				return
+			}
+		}
+	}
 	return
 }
 
@@ -528,6 +399,25 @@ func (z *ObjectMetaV2DeleteMarker) UnmarshalMsg(bts []byte) (o []byte, err error
 				err = msgp.WrapError(err, "ModTime")
 				return
 			}
+		case "repl":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication")
+				return
+			}
+			if cap(z.Replication) >= int(zb0002) {
+				z.Replication = (z.Replication)[:zb0002]
+			} else {
+				z.Replication = make([]ObjectMetaV2Replication, zb0002)
+			}
+			for za0001 := range z.Replication {
+				bts, err = z.Replication[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Replication", za0001)
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -541,8 +431,11 @@ func (z *ObjectMetaV2DeleteMarker) UnmarshalMsg(bts []byte) (o []byte, err error
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z ObjectMetaV2DeleteMarker) Msgsize() (s int) {
-	s = 1 + 3 + msgp.Uint64Size + 6 + msgp.Int64Size
+func (z *ObjectMetaV2DeleteMarker) Msgsize() (s int) {
+	s = 1 + 3 + msgp.Uint64Size + 6 + msgp.Int64Size + 5 + msgp.ArrayHeaderSize
+	for za0001 := range z.Replication {
+		s += z.Replication[za0001].Msgsize()
+	}
 	return
 }
 
@@ -586,40 +479,11 @@ func (z *ObjectMetaV2JournalEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 				if z.DeleteMarker == nil {
 					z.DeleteMarker = new(ObjectMetaV2DeleteMarker)
 				}
-				var zb0003 uint32
-				zb0003, err = dc.ReadMapHeader()
+				err = z.DeleteMarker.DecodeMsg(dc)
 				if err != nil {
 					err = msgp.WrapError(err, "DeleteMarker")
 					return
 				}
-				for zb0003 > 0 {
-					zb0003--
-					field, err = dc.ReadMapKeyPtr()
-					if err != nil {
-						err = msgp.WrapError(err, "DeleteMarker")
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "id":
-						z.DeleteMarker.VersionID, err = dc.ReadUint64()
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker", "VersionID")
-							return
-						}
-					case "mtime":
-						z.DeleteMarker.ModTime, err = dc.ReadInt64()
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker", "ModTime")
-							return
-						}
-					default:
-						err = dc.Skip()
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker")
-							return
-						}
-					}
-				}
 			}
 		case "object":
 			if dc.IsNil() {
@@ -657,6 +521,24 @@ func (z *ObjectMetaV2JournalEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 					return
 				}
 			}
+		case "mpart":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "Multipart")
+					return
+				}
+				z.Multipart = nil
+			} else {
+				if z.Multipart == nil {
+					z.Multipart = new(ObjectMetaV2Multipart)
+				}
+				err = z.Multipart.DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Multipart")
+					return
+				}
+			}
 		default:
 			err = dc.Skip()
 			if err != nil {
@@ -671,8 +553,8 @@ func (z *ObjectMetaV2JournalEntry) DecodeMsg(dc *msgp.Reader) (err error) {
 // EncodeMsg implements msgp.Encodable
 func (z *ObjectMetaV2JournalEntry) EncodeMsg(en *msgp.Writer) (err error) {
 	// omitempty: check for empty values
-	zb0001Len := uint32(4)
-	var zb0001Mask uint8 /* 4 bits */
+	zb0001Len := uint32(5)
+	var zb0001Mask uint8 /* 5 bits */
 	if z.DeleteMarker == nil {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -685,6 +567,10 @@ func (z *ObjectMetaV2JournalEntry) EncodeMsg(en *msgp.Writer) (err error) {
 		zb0001Len--
 		zb0001Mask |= 0x8
 	}
+	if z.Multipart == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
 	// variable map header, size zb0001Len
 	err = en.Append(0x80 | uint8(zb0001Len))
 	if err != nil {
@@ -715,25 +601,9 @@ func (z *ObjectMetaV2JournalEntry) EncodeMsg(en *msgp.Writer) (err error) {
 				return
 			}
 		} else {
-			// map header, size 2
-			// write "id"
-			err = en.Append(0x82, 0xa2, 0x69, 0x64)
-			if err != nil {
-				return
-			}
-			err = en.WriteUint64(z.DeleteMarker.VersionID)
+			err = z.DeleteMarker.EncodeMsg(en)
 			if err != nil {
-				err = msgp.WrapError(err, "DeleteMarker", "VersionID")
-				return
-			}
-			// write "mtime"
-			err = en.Append(0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
-			if err != nil {
-				return
-			}
-			err = en.WriteInt64(z.DeleteMarker.ModTime)
-			if err != nil {
-				err = msgp.WrapError(err, "DeleteMarker", "ModTime")
+				err = msgp.WrapError(err, "DeleteMarker")
 				return
 			}
 		}
@@ -776,6 +646,25 @@ func (z *ObjectMetaV2JournalEntry) EncodeMsg(en *msgp.Writer) (err error) {
 			}
 		}
 	}
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// write "mpart"
+		err = en.Append(0xa5, 0x6d, 0x70, 0x61, 0x72, 0x74)
+		if err != nil {
+			return
+		}
+		if z.Multipart == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			err = z.Multipart.EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "Multipart")
+				return
+			}
+		}
+	}
 	return
 }
 
@@ -783,8 +672,8 @@ func (z *ObjectMetaV2JournalEntry) EncodeMsg(en *msgp.Writer) (err error) {
 func (z *ObjectMetaV2JournalEntry) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(4)
-	var zb0001Mask uint8 /* 4 bits */
+	zb0001Len := uint32(5)
+	var zb0001Mask uint8 /* 5 bits */
 	if z.DeleteMarker == nil {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -797,6 +686,10 @@ func (z *ObjectMetaV2JournalEntry) MarshalMsg(b []byte) (o []byte, err error) {
 		zb0001Len--
 		zb0001Mask |= 0x8
 	}
+	if z.Multipart == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
 	// variable map header, size zb0001Len
 	o = append(o, 0x80|uint8(zb0001Len))
 	if zb0001Len == 0 {
@@ -811,13 +704,11 @@ func (z *ObjectMetaV2JournalEntry) MarshalMsg(b []byte) (o []byte, err error) {
 		if z.DeleteMarker == nil {
 			o = msgp.AppendNil(o)
 		} else {
-			// map header, size 2
-			// string "id"
-			o = append(o, 0x82, 0xa2, 0x69, 0x64)
-			o = msgp.AppendUint64(o, z.DeleteMarker.VersionID)
-			// string "mtime"
-			o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
-			o = msgp.AppendInt64(o, z.DeleteMarker.ModTime)
+			o, err = z.DeleteMarker.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "DeleteMarker")
+				return
+			}
 		}
 	}
 	if (zb0001Mask & 0x4) == 0 { // if not empty
@@ -846,6 +737,19 @@ func (z *ObjectMetaV2JournalEntry) MarshalMsg(b []byte) (o []byte, err error) {
 			}
 		}
 	}
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// string "mpart"
+		o = append(o, 0xa5, 0x6d, 0x70, 0x61, 0x72, 0x74)
+		if z.Multipart == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			o, err = z.Multipart.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Multipart")
+				return
+			}
+		}
+	}
 	return
 }
 
@@ -888,40 +792,11 @@ func (z *ObjectMetaV2JournalEntry) UnmarshalMsg(bts []byte) (o []byte, err error
 				if z.DeleteMarker == nil {
 					z.DeleteMarker = new(ObjectMetaV2DeleteMarker)
 				}
-				var zb0003 uint32
-				zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+				bts, err = z.DeleteMarker.UnmarshalMsg(bts)
 				if err != nil {
 					err = msgp.WrapError(err, "DeleteMarker")
 					return
 				}
-				for zb0003 > 0 {
-					zb0003--
-					field, bts, err = msgp.ReadMapKeyZC(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "DeleteMarker")
-						return
-					}
-					switch msgp.UnsafeString(field) {
-					case "id":
-						z.DeleteMarker.VersionID, bts, err = msgp.ReadUint64Bytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker", "VersionID")
-							return
-						}
-					case "mtime":
-						z.DeleteMarker.ModTime, bts, err = msgp.ReadInt64Bytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker", "ModTime")
-							return
-						}
-					default:
-						bts, err = msgp.Skip(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "DeleteMarker")
-							return
-						}
-					}
-				}
 			}
 		case "object":
 			if msgp.IsNil(bts) {
@@ -957,6 +832,23 @@ func (z *ObjectMetaV2JournalEntry) UnmarshalMsg(bts []byte) (o []byte, err error
 					return
 				}
 			}
+		case "mpart":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Multipart = nil
+			} else {
+				if z.Multipart == nil {
+					z.Multipart = new(ObjectMetaV2Multipart)
+				}
+				bts, err = z.Multipart.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Multipart")
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -975,7 +867,7 @@ func (z *ObjectMetaV2JournalEntry) Msgsize() (s int) {
 	if z.DeleteMarker == nil {
 		s += msgp.NilSize
 	} else {
-		s += 1 + 3 + msgp.Uint64Size + 6 + msgp.Int64Size
+		s += z.DeleteMarker.Msgsize()
 	}
 	s += 7
 	if z.Object == nil {
@@ -989,6 +881,12 @@ func (z *ObjectMetaV2JournalEntry) Msgsize() (s int) {
 	} else {
 		s += z.Link.Msgsize()
 	}
+	s += 6
+	if z.Multipart == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Multipart.Msgsize()
+	}
 	return
 }
 
@@ -1097,10 +995,146 @@ func (z *ObjectMetaV2Link) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "DataPartInfoSizes")
 				return
 			}
-		case "size":
-			z.StatSize, err = dc.ReadInt()
+		case "pcsum":
+			var zb0005 uint32
+			zb0005, err = dc.ReadArrayHeader()
 			if err != nil {
-				err = msgp.WrapError(err, "StatSize")
+				err = msgp.WrapError(err, "DataPartInfoChecksums")
+				return
+			}
+			if cap(z.DataPartInfoChecksums) >= int(zb0005) {
+				z.DataPartInfoChecksums = (z.DataPartInfoChecksums)[:zb0005]
+			} else {
+				z.DataPartInfoChecksums = make([][]byte, zb0005)
+			}
+			for za0002 := range z.DataPartInfoChecksums {
+				z.DataPartInfoChecksums[za0002], err = dc.ReadBytes(z.DataPartInfoChecksums[za0002])
+				if err != nil {
+					err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+					return
+				}
+			}
+		case "inline":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+				z.Inline = nil
+			} else {
+				if z.Inline == nil {
+					z.Inline = new(InlineData)
+				}
+				err = z.Inline.DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+			}
+		case "rmode":
+			{
+				var zb0006 uint8
+				zb0006, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "RetentionMode")
+					return
+				}
+				z.RetentionMode = RetentionMode(zb0006)
+			}
+		case "runtil":
+			z.RetainUntil, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "RetainUntil")
+				return
+			}
+		case "lhold":
+			z.LegalHold, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "LegalHold")
+				return
+			}
+		case "repl":
+			var zb0007 uint32
+			zb0007, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Replication")
+				return
+			}
+			if cap(z.Replication) >= int(zb0007) {
+				z.Replication = (z.Replication)[:zb0007]
+			} else {
+				z.Replication = make([]ObjectMetaV2Replication, zb0007)
+			}
+			for za0003 := range z.Replication {
+				err = z.Replication[za0003].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Replication", za0003)
+					return
+				}
+			}
+		case "sse":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+				z.SSE = nil
+			} else {
+				if z.SSE == nil {
+					z.SSE = new(ObjectMetaV2SSE)
+				}
+				err = z.SSE.DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+			}
+		case "sc":
+			z.StorageClass, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "StorageClass")
+				return
+			}
+		case "tst":
+			{
+				var zb0008 uint8
+				zb0008, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "TransitionStatus")
+					return
+				}
+				z.TransitionStatus = TransitionStatus(zb0008)
+			}
+		case "ttier":
+			z.TransitionTier, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionTier")
+				return
+			}
+		case "tobj":
+			z.TransitionedObject, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedObject")
+				return
+			}
+		case "tvid":
+			z.TransitionedVersionID, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedVersionID")
+				return
+			}
+		case "rexp":
+			z.RestoreExpires, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "RestoreExpires")
+				return
+			}
+		case "size":
+			z.StatSize, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "StatSize")
 				return
 			}
 		case "mtime":
@@ -1110,77 +1144,77 @@ func (z *ObjectMetaV2Link) DecodeMsg(dc *msgp.Reader) (err error) {
 				return
 			}
 		case "msys":
-			var zb0005 uint32
-			zb0005, err = dc.ReadMapHeader()
+			var zb0009 uint32
+			zb0009, err = dc.ReadMapHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "MetaSys")
 				return
 			}
 			if z.MetaSys == nil {
-				z.MetaSys = make(map[string][]byte, zb0005)
+				z.MetaSys = make(map[string][]byte, zb0009)
 			} else if len(z.MetaSys) > 0 {
 				for key := range z.MetaSys {
 					delete(z.MetaSys, key)
 				}
 			}
-			for zb0005 > 0 {
-				zb0005--
-				var za0002 string
-				var za0003 []byte
-				za0002, err = dc.ReadString()
+			for zb0009 > 0 {
+				zb0009--
+				var za0004 string
+				var za0005 []byte
+				za0004, err = dc.ReadString()
 				if err != nil {
 					err = msgp.WrapError(err, "MetaSys")
 					return
 				}
-				za0003, err = dc.ReadBytes(za0003)
+				za0005, err = dc.ReadBytes(za0005)
 				if err != nil {
-					err = msgp.WrapError(err, "MetaSys", za0002)
+					err = msgp.WrapError(err, "MetaSys", za0004)
 					return
 				}
-				z.MetaSys[za0002] = za0003
+				z.MetaSys[za0004] = za0005
 			}
 		case "muser":
-			var zb0006 uint32
-			zb0006, err = dc.ReadMapHeader()
+			var zb0010 uint32
+			zb0010, err = dc.ReadMapHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "MetaUser")
 				return
 			}
 			if z.MetaUser == nil {
-				z.MetaUser = make(map[string][]string, zb0006)
+				z.MetaUser = make(map[string][]string, zb0010)
 			} else if len(z.MetaUser) > 0 {
 				for key := range z.MetaUser {
 					delete(z.MetaUser, key)
 				}
 			}
-			for zb0006 > 0 {
-				zb0006--
-				var za0004 string
-				var za0005 []string
-				za0004, err = dc.ReadString()
+			for zb0010 > 0 {
+				zb0010--
+				var za0006 string
+				var za0007 []string
+				za0006, err = dc.ReadString()
 				if err != nil {
 					err = msgp.WrapError(err, "MetaUser")
 					return
 				}
-				var zb0007 uint32
-				zb0007, err = dc.ReadArrayHeader()
+				var zb0011 uint32
+				zb0011, err = dc.ReadArrayHeader()
 				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004)
+					err = msgp.WrapError(err, "MetaUser", za0006)
 					return
 				}
-				if cap(za0005) >= int(zb0007) {
-					za0005 = (za0005)[:zb0007]
+				if cap(za0007) >= int(zb0011) {
+					za0007 = (za0007)[:zb0011]
 				} else {
-					za0005 = make([]string, zb0007)
+					za0007 = make([]string, zb0011)
 				}
-				for za0006 := range za0005 {
-					za0005[za0006], err = dc.ReadString()
+				for za0008 := range za0007 {
+					za0007[za0008], err = dc.ReadString()
 					if err != nil {
-						err = msgp.WrapError(err, "MetaUser", za0004, za0006)
+						err = msgp.WrapError(err, "MetaUser", za0006, za0008)
 						return
 					}
 				}
-				z.MetaUser[za0004] = za0005
+				z.MetaUser[za0006] = za0007
 			}
 		default:
 			err = dc.Skip()
@@ -1196,18 +1230,70 @@ func (z *ObjectMetaV2Link) DecodeMsg(dc *msgp.Reader) (err error) {
 // EncodeMsg implements msgp.Encodable
 func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 	// omitempty: check for empty values
-	zb0001Len := uint32(15)
-	var zb0001Mask uint16 /* 15 bits */
-	if z.MetaSys == nil {
+	zb0001Len := uint32(28)
+	var zb0001Mask uint32 /* 28 bits */
+	if z.DataPartInfoChecksums == nil {
+		zb0001Len--
+		zb0001Mask |= 0x800
+	}
+	if z.Inline == nil {
+		zb0001Len--
+		zb0001Mask |= 0x1000
+	}
+	if z.RetentionMode == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x2000
 	}
-	if z.MetaUser == nil {
+	if z.RetainUntil == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x4000
 	}
+	if z.LegalHold == false {
+		zb0001Len--
+		zb0001Mask |= 0x8000
+	}
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10000
+	}
+	if z.SSE == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20000
+	}
+	if z.StorageClass == "" {
+		zb0001Len--
+		zb0001Mask |= 0x40000
+	}
+	if z.TransitionStatus == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x80000
+	}
+	if z.TransitionTier == "" {
+		zb0001Len--
+		zb0001Mask |= 0x100000
+	}
+	if z.TransitionedObject == "" {
+		zb0001Len--
+		zb0001Mask |= 0x200000
+	}
+	if z.TransitionedVersionID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x400000
+	}
+	if z.RestoreExpires == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x800000
+	}
+	if z.MetaSys == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4000000
+	}
+	if z.MetaUser == nil {
+		zb0001Len--
+		zb0001Mask |= 0x8000000
+	}
 	// variable map header, size zb0001Len
-	err = en.Append(0x80 | uint8(zb0001Len))
+	err = en.WriteMapHeader(zb0001Len)
 	if err != nil {
 		return
 	}
@@ -1331,6 +1417,190 @@ func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "DataPartInfoSizes")
 		return
 	}
+	if (zb0001Mask & 0x800) == 0 { // if not empty
+		// write "pcsum"
+		err = en.Append(0xa5, 0x70, 0x63, 0x73, 0x75, 0x6d)
+		if err != nil {
+			return
+		}
+		err = en.WriteArrayHeader(uint32(len(z.DataPartInfoChecksums)))
+		if err != nil {
+			err = msgp.WrapError(err, "DataPartInfoChecksums")
+			return
+		}
+		for za0002 := range z.DataPartInfoChecksums {
+			err = en.WriteBytes(z.DataPartInfoChecksums[za0002])
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x1000) == 0 { // if not empty
+		// write "inline"
+		err = en.Append(0xa6, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65)
+		if err != nil {
+			return
+		}
+		if z.Inline == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			err = z.Inline.EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "Inline")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x2000) == 0 { // if not empty
+		// write "rmode"
+		err = en.Append(0xa5, 0x72, 0x6d, 0x6f, 0x64, 0x65)
+		if err != nil {
+			return
+		}
+		err = en.WriteUint8(uint8(z.RetentionMode))
+		if err != nil {
+			err = msgp.WrapError(err, "RetentionMode")
+			return
+		}
+	}
+	if (zb0001Mask & 0x4000) == 0 { // if not empty
+		// write "runtil"
+		err = en.Append(0xa6, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6c)
+		if err != nil {
+			return
+		}
+		err = en.WriteInt64(z.RetainUntil)
+		if err != nil {
+			err = msgp.WrapError(err, "RetainUntil")
+			return
+		}
+	}
+	if (zb0001Mask & 0x8000) == 0 { // if not empty
+		// write "lhold"
+		err = en.Append(0xa5, 0x6c, 0x68, 0x6f, 0x6c, 0x64)
+		if err != nil {
+			return
+		}
+		err = en.WriteBool(z.LegalHold)
+		if err != nil {
+			err = msgp.WrapError(err, "LegalHold")
+			return
+		}
+	}
+	if (zb0001Mask & 0x10000) == 0 { // if not empty
+		// write "repl"
+		err = en.Append(0xa4, 0x72, 0x65, 0x70, 0x6c)
+		if err != nil {
+			return
+		}
+		err = en.WriteArrayHeader(uint32(len(z.Replication)))
+		if err != nil {
+			err = msgp.WrapError(err, "Replication")
+			return
+		}
+		for za0003 := range z.Replication {
+			err = z.Replication[za0003].EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication", za0003)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x20000) == 0 { // if not empty
+		// write "sse"
+		err = en.Append(0xa3, 0x73, 0x73, 0x65)
+		if err != nil {
+			return
+		}
+		if z.SSE == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			err = z.SSE.EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "SSE")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x40000) == 0 { // if not empty
+		// write "sc"
+		err = en.Append(0xa2, 0x73, 0x63)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.StorageClass)
+		if err != nil {
+			err = msgp.WrapError(err, "StorageClass")
+			return
+		}
+	}
+	if (zb0001Mask & 0x80000) == 0 { // if not empty
+		// write "tst"
+		err = en.Append(0xa3, 0x74, 0x73, 0x74)
+		if err != nil {
+			return
+		}
+		err = en.WriteUint8(uint8(z.TransitionStatus))
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionStatus")
+			return
+		}
+	}
+	if (zb0001Mask & 0x100000) == 0 { // if not empty
+		// write "ttier"
+		err = en.Append(0xa5, 0x74, 0x74, 0x69, 0x65, 0x72)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionTier)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionTier")
+			return
+		}
+	}
+	if (zb0001Mask & 0x200000) == 0 { // if not empty
+		// write "tobj"
+		err = en.Append(0xa4, 0x74, 0x6f, 0x62, 0x6a)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionedObject)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionedObject")
+			return
+		}
+	}
+	if (zb0001Mask & 0x400000) == 0 { // if not empty
+		// write "tvid"
+		err = en.Append(0xa4, 0x74, 0x76, 0x69, 0x64)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionedVersionID)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionedVersionID")
+			return
+		}
+	}
+	if (zb0001Mask & 0x800000) == 0 { // if not empty
+		// write "rexp"
+		err = en.Append(0xa4, 0x72, 0x65, 0x78, 0x70)
+		if err != nil {
+			return
+		}
+		err = en.WriteInt64(z.RestoreExpires)
+		if err != nil {
+			err = msgp.WrapError(err, "RestoreExpires")
+			return
+		}
+	}
 	// write "size"
 	err = en.Append(0xa4, 0x73, 0x69, 0x7a, 0x65)
 	if err != nil {
@@ -1351,7 +1621,7 @@ func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "StatModTime")
 		return
 	}
-	if (zb0001Mask & 0x2000) == 0 { // if not empty
+	if (zb0001Mask & 0x4000000) == 0 { // if not empty
 		// write "msys"
 		err = en.Append(0xa4, 0x6d, 0x73, 0x79, 0x73)
 		if err != nil {
@@ -1362,20 +1632,20 @@ func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 			err = msgp.WrapError(err, "MetaSys")
 			return
 		}
-		for za0002, za0003 := range z.MetaSys {
-			err = en.WriteString(za0002)
+		for za0004, za0005 := range z.MetaSys {
+			err = en.WriteString(za0004)
 			if err != nil {
 				err = msgp.WrapError(err, "MetaSys")
 				return
 			}
-			err = en.WriteBytes(za0003)
+			err = en.WriteBytes(za0005)
 			if err != nil {
-				err = msgp.WrapError(err, "MetaSys", za0002)
+				err = msgp.WrapError(err, "MetaSys", za0004)
 				return
 			}
 		}
 	}
-	if (zb0001Mask & 0x4000) == 0 { // if not empty
+	if (zb0001Mask & 0x8000000) == 0 { // if not empty
 		// write "muser"
 		err = en.Append(0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
 		if err != nil {
@@ -1386,21 +1656,21 @@ func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 			err = msgp.WrapError(err, "MetaUser")
 			return
 		}
-		for za0004, za0005 := range z.MetaUser {
-			err = en.WriteString(za0004)
+		for za0006, za0007 := range z.MetaUser {
+			err = en.WriteString(za0006)
 			if err != nil {
 				err = msgp.WrapError(err, "MetaUser")
 				return
 			}
-			err = en.WriteArrayHeader(uint32(len(za0005)))
+			err = en.WriteArrayHeader(uint32(len(za0007)))
 			if err != nil {
-				err = msgp.WrapError(err, "MetaUser", za0004)
+				err = msgp.WrapError(err, "MetaUser", za0006)
 				return
 			}
-			for za0006 := range za0005 {
-				err = en.WriteString(za0005[za0006])
+			for za0008 := range za0007 {
+				err = en.WriteString(za0007[za0008])
 				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004, za0006)
+					err = msgp.WrapError(err, "MetaUser", za0006, za0008)
 					return
 				}
 			}
@@ -1413,39 +1683,91 @@ func (z *ObjectMetaV2Link) EncodeMsg(en *msgp.Writer) (err error) {
 func (z *ObjectMetaV2Link) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(15)
-	var zb0001Mask uint16 /* 15 bits */
-	if z.MetaSys == nil {
+	zb0001Len := uint32(28)
+	var zb0001Mask uint32 /* 28 bits */
+	if z.DataPartInfoChecksums == nil {
+		zb0001Len--
+		zb0001Mask |= 0x800
+	}
+	if z.Inline == nil {
+		zb0001Len--
+		zb0001Mask |= 0x1000
+	}
+	if z.RetentionMode == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x2000
 	}
-	if z.MetaUser == nil {
+	if z.RetainUntil == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x4000
 	}
-	// variable map header, size zb0001Len
-	o = append(o, 0x80|uint8(zb0001Len))
-	if zb0001Len == 0 {
-		return
+	if z.LegalHold == false {
+		zb0001Len--
+		zb0001Mask |= 0x8000
 	}
-	// string "id"
-	o = append(o, 0xa2, 0x69, 0x64)
-	o = msgp.AppendUint64(o, z.VersionID)
-	// string "dd"
-	o = append(o, 0xa2, 0x64, 0x64)
-	o = msgp.AppendUint64(o, z.DataDir)
-	// string "ealgo"
-	o = append(o, 0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
-	o = msgp.AppendUint8(o, uint8(z.DataErasureAlgorithm))
-	// string "m"
-	o = append(o, 0xa1, 0x6d)
-	o = msgp.AppendInt(o, z.DataErasureM)
-	// string "n"
-	o = append(o, 0xa1, 0x6e)
-	o = msgp.AppendInt(o, z.DataErasureN)
-	// string "bsize"
-	o = append(o, 0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
-	o = msgp.AppendInt(o, z.DataErasureBlockSize)
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10000
+	}
+	if z.SSE == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20000
+	}
+	if z.StorageClass == "" {
+		zb0001Len--
+		zb0001Mask |= 0x40000
+	}
+	if z.TransitionStatus == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x80000
+	}
+	if z.TransitionTier == "" {
+		zb0001Len--
+		zb0001Mask |= 0x100000
+	}
+	if z.TransitionedObject == "" {
+		zb0001Len--
+		zb0001Mask |= 0x200000
+	}
+	if z.TransitionedVersionID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x400000
+	}
+	if z.RestoreExpires == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x800000
+	}
+	if z.MetaSys == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4000000
+	}
+	if z.MetaUser == nil {
+		zb0001Len--
+		zb0001Mask |= 0x8000000
+	}
+	// variable map header, size zb0001Len
+	o = msgp.AppendMapHeader(o, zb0001Len)
+	if zb0001Len == 0 {
+		return
+	}
+	// string "id"
+	o = append(o, 0xa2, 0x69, 0x64)
+	o = msgp.AppendUint64(o, z.VersionID)
+	// string "dd"
+	o = append(o, 0xa2, 0x64, 0x64)
+	o = msgp.AppendUint64(o, z.DataDir)
+	// string "ealgo"
+	o = append(o, 0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
+	o = msgp.AppendUint8(o, uint8(z.DataErasureAlgorithm))
+	// string "m"
+	o = append(o, 0xa1, 0x6d)
+	o = msgp.AppendInt(o, z.DataErasureM)
+	// string "n"
+	o = append(o, 0xa1, 0x6e)
+	o = msgp.AppendInt(o, z.DataErasureN)
+	// string "bsize"
+	o = append(o, 0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
+	o = msgp.AppendInt(o, z.DataErasureBlockSize)
 	// string "index"
 	o = append(o, 0xa5, 0x69, 0x6e, 0x64, 0x65, 0x78)
 	o = msgp.AppendInt(o, z.DataErasureIndex)
@@ -1472,30 +1794,121 @@ func (z *ObjectMetaV2Link) MarshalMsg(b []byte) (o []byte, err error) {
 		err = msgp.WrapError(err, "DataPartInfoSizes")
 		return
 	}
+	if (zb0001Mask & 0x800) == 0 { // if not empty
+		// string "pcsum"
+		o = append(o, 0xa5, 0x70, 0x63, 0x73, 0x75, 0x6d)
+		o = msgp.AppendArrayHeader(o, uint32(len(z.DataPartInfoChecksums)))
+		for za0002 := range z.DataPartInfoChecksums {
+			o = msgp.AppendBytes(o, z.DataPartInfoChecksums[za0002])
+		}
+	}
+	if (zb0001Mask & 0x1000) == 0 { // if not empty
+		// string "inline"
+		o = append(o, 0xa6, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65)
+		if z.Inline == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			o, err = z.Inline.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Inline")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x2000) == 0 { // if not empty
+		// string "rmode"
+		o = append(o, 0xa5, 0x72, 0x6d, 0x6f, 0x64, 0x65)
+		o = msgp.AppendUint8(o, uint8(z.RetentionMode))
+	}
+	if (zb0001Mask & 0x4000) == 0 { // if not empty
+		// string "runtil"
+		o = append(o, 0xa6, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6c)
+		o = msgp.AppendInt64(o, z.RetainUntil)
+	}
+	if (zb0001Mask & 0x8000) == 0 { // if not empty
+		// string "lhold"
+		o = append(o, 0xa5, 0x6c, 0x68, 0x6f, 0x6c, 0x64)
+		o = msgp.AppendBool(o, z.LegalHold)
+	}
+	if (zb0001Mask & 0x10000) == 0 { // if not empty
+		// string "repl"
+		o = append(o, 0xa4, 0x72, 0x65, 0x70, 0x6c)
+		o = msgp.AppendArrayHeader(o, uint32(len(z.Replication)))
+		for za0003 := range z.Replication {
+			o, err = z.Replication[za0003].MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication", za0003)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x20000) == 0 { // if not empty
+		// string "sse"
+		o = append(o, 0xa3, 0x73, 0x73, 0x65)
+		if z.SSE == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			o, err = z.SSE.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "SSE")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x40000) == 0 { // if not empty
+		// string "sc"
+		o = append(o, 0xa2, 0x73, 0x63)
+		o = msgp.AppendString(o, z.StorageClass)
+	}
+	if (zb0001Mask & 0x80000) == 0 { // if not empty
+		// string "tst"
+		o = append(o, 0xa3, 0x74, 0x73, 0x74)
+		o = msgp.AppendUint8(o, uint8(z.TransitionStatus))
+	}
+	if (zb0001Mask & 0x100000) == 0 { // if not empty
+		// string "ttier"
+		o = append(o, 0xa5, 0x74, 0x74, 0x69, 0x65, 0x72)
+		o = msgp.AppendString(o, z.TransitionTier)
+	}
+	if (zb0001Mask & 0x200000) == 0 { // if not empty
+		// string "tobj"
+		o = append(o, 0xa4, 0x74, 0x6f, 0x62, 0x6a)
+		o = msgp.AppendString(o, z.TransitionedObject)
+	}
+	if (zb0001Mask & 0x400000) == 0 { // if not empty
+		// string "tvid"
+		o = append(o, 0xa4, 0x74, 0x76, 0x69, 0x64)
+		o = msgp.AppendString(o, z.TransitionedVersionID)
+	}
+	if (zb0001Mask & 0x800000) == 0 { // if not empty
+		// string "rexp"
+		o = append(o, 0xa4, 0x72, 0x65, 0x78, 0x70)
+		o = msgp.AppendInt64(o, z.RestoreExpires)
+	}
 	// string "size"
 	o = append(o, 0xa4, 0x73, 0x69, 0x7a, 0x65)
 	o = msgp.AppendInt(o, z.StatSize)
 	// string "mtime"
 	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
 	o = msgp.AppendInt64(o, z.StatModTime)
-	if (zb0001Mask & 0x2000) == 0 { // if not empty
+	if (zb0001Mask & 0x4000000) == 0 { // if not empty
 		// string "msys"
 		o = append(o, 0xa4, 0x6d, 0x73, 0x79, 0x73)
 		o = msgp.AppendMapHeader(o, uint32(len(z.MetaSys)))
-		for za0002, za0003 := range z.MetaSys {
-			o = msgp.AppendString(o, za0002)
-			o = msgp.AppendBytes(o, za0003)
+		for za0004, za0005 := range z.MetaSys {
+			o = msgp.AppendString(o, za0004)
+			o = msgp.AppendBytes(o, za0005)
 		}
 	}
-	if (zb0001Mask & 0x4000) == 0 { // if not empty
+	if (zb0001Mask & 0x8000000) == 0 { // if not empty
 		// string "muser"
 		o = append(o, 0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
 		o = msgp.AppendMapHeader(o, uint32(len(z.MetaUser)))
-		for za0004, za0005 := range z.MetaUser {
-			o = msgp.AppendString(o, za0004)
-			o = msgp.AppendArrayHeader(o, uint32(len(za0005)))
-			for za0006 := range za0005 {
-				o = msgp.AppendString(o, za0005[za0006])
+		for za0006, za0007 := range z.MetaUser {
+			o = msgp.AppendString(o, za0006)
+			o = msgp.AppendArrayHeader(o, uint32(len(za0007)))
+			for za0008 := range za0007 {
+				o = msgp.AppendString(o, za0007[za0008])
 			}
 		}
 	}
@@ -1607,6 +2020,140 @@ func (z *ObjectMetaV2Link) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "DataPartInfoSizes")
 				return
 			}
+		case "pcsum":
+			var zb0005 uint32
+			zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoChecksums")
+				return
+			}
+			if cap(z.DataPartInfoChecksums) >= int(zb0005) {
+				z.DataPartInfoChecksums = (z.DataPartInfoChecksums)[:zb0005]
+			} else {
+				z.DataPartInfoChecksums = make([][]byte, zb0005)
+			}
+			for za0002 := range z.DataPartInfoChecksums {
+				z.DataPartInfoChecksums[za0002], bts, err = msgp.ReadBytesBytes(bts, z.DataPartInfoChecksums[za0002])
+				if err != nil {
+					err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+					return
+				}
+			}
+		case "inline":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Inline = nil
+			} else {
+				if z.Inline == nil {
+					z.Inline = new(InlineData)
+				}
+				bts, err = z.Inline.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+			}
+		case "rmode":
+			{
+				var zb0006 uint8
+				zb0006, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "RetentionMode")
+					return
+				}
+				z.RetentionMode = RetentionMode(zb0006)
+			}
+		case "runtil":
+			z.RetainUntil, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RetainUntil")
+				return
+			}
+		case "lhold":
+			z.LegalHold, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LegalHold")
+				return
+			}
+		case "repl":
+			var zb0007 uint32
+			zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication")
+				return
+			}
+			if cap(z.Replication) >= int(zb0007) {
+				z.Replication = (z.Replication)[:zb0007]
+			} else {
+				z.Replication = make([]ObjectMetaV2Replication, zb0007)
+			}
+			for za0003 := range z.Replication {
+				bts, err = z.Replication[za0003].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Replication", za0003)
+					return
+				}
+			}
+		case "sse":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.SSE = nil
+			} else {
+				if z.SSE == nil {
+					z.SSE = new(ObjectMetaV2SSE)
+				}
+				bts, err = z.SSE.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+			}
+		case "sc":
+			z.StorageClass, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "StorageClass")
+				return
+			}
+		case "tst":
+			{
+				var zb0008 uint8
+				zb0008, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TransitionStatus")
+					return
+				}
+				z.TransitionStatus = TransitionStatus(zb0008)
+			}
+		case "ttier":
+			z.TransitionTier, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionTier")
+				return
+			}
+		case "tobj":
+			z.TransitionedObject, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedObject")
+				return
+			}
+		case "tvid":
+			z.TransitionedVersionID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedVersionID")
+				return
+			}
+		case "rexp":
+			z.RestoreExpires, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RestoreExpires")
+				return
+			}
 		case "size":
 			z.StatSize, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
@@ -1620,77 +2167,77 @@ func (z *ObjectMetaV2Link) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		case "msys":
-			var zb0005 uint32
-			zb0005, bts, err = msgp.ReadMapHeaderBytes(bts)
+			var zb0009 uint32
+			zb0009, bts, err = msgp.ReadMapHeaderBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "MetaSys")
 				return
 			}
 			if z.MetaSys == nil {
-				z.MetaSys = make(map[string][]byte, zb0005)
+				z.MetaSys = make(map[string][]byte, zb0009)
 			} else if len(z.MetaSys) > 0 {
 				for key := range z.MetaSys {
 					delete(z.MetaSys, key)
 				}
 			}
-			for zb0005 > 0 {
-				var za0002 string
-				var za0003 []byte
-				zb0005--
-				za0002, bts, err = msgp.ReadStringBytes(bts)
+			for zb0009 > 0 {
+				var za0004 string
+				var za0005 []byte
+				zb0009--
+				za0004, bts, err = msgp.ReadStringBytes(bts)
 				if err != nil {
 					err = msgp.WrapError(err, "MetaSys")
 					return
 				}
-				za0003, bts, err = msgp.ReadBytesBytes(bts, za0003)
+				za0005, bts, err = msgp.ReadBytesBytes(bts, za0005)
 				if err != nil {
-					err = msgp.WrapError(err, "MetaSys", za0002)
+					err = msgp.WrapError(err, "MetaSys", za0004)
 					return
 				}
-				z.MetaSys[za0002] = za0003
+				z.MetaSys[za0004] = za0005
 			}
 		case "muser":
-			var zb0006 uint32
-			zb0006, bts, err = msgp.ReadMapHeaderBytes(bts)
+			var zb0010 uint32
+			zb0010, bts, err = msgp.ReadMapHeaderBytes(bts)
 			if err != nil {
 				err = msgp.WrapError(err, "MetaUser")
 				return
 			}
 			if z.MetaUser == nil {
-				z.MetaUser = make(map[string][]string, zb0006)
+				z.MetaUser = make(map[string][]string, zb0010)
 			} else if len(z.MetaUser) > 0 {
 				for key := range z.MetaUser {
 					delete(z.MetaUser, key)
 				}
 			}
-			for zb0006 > 0 {
-				var za0004 string
-				var za0005 []string
-				zb0006--
-				za0004, bts, err = msgp.ReadStringBytes(bts)
+			for zb0010 > 0 {
+				var za0006 string
+				var za0007 []string
+				zb0010--
+				za0006, bts, err = msgp.ReadStringBytes(bts)
 				if err != nil {
 					err = msgp.WrapError(err, "MetaUser")
 					return
 				}
-				var zb0007 uint32
-				zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				var zb0011 uint32
+				zb0011, bts, err = msgp.ReadArrayHeaderBytes(bts)
 				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004)
+					err = msgp.WrapError(err, "MetaUser", za0006)
 					return
 				}
-				if cap(za0005) >= int(zb0007) {
-					za0005 = (za0005)[:zb0007]
+				if cap(za0007) >= int(zb0011) {
+					za0007 = (za0007)[:zb0011]
 				} else {
-					za0005 = make([]string, zb0007)
+					za0007 = make([]string, zb0011)
 				}
-				for za0006 := range za0005 {
-					za0005[za0006], bts, err = msgp.ReadStringBytes(bts)
+				for za0008 := range za0007 {
+					za0007[za0008], bts, err = msgp.ReadStringBytes(bts)
 					if err != nil {
-						err = msgp.WrapError(err, "MetaUser", za0004, za0006)
+						err = msgp.WrapError(err, "MetaUser", za0006, za0008)
 						return
 					}
 				}
-				z.MetaUser[za0004] = za0005
+				z.MetaUser[za0006] = za0007
 			}
 		default:
 			bts, err = msgp.Skip(bts)
@@ -1706,20 +2253,40 @@ func (z *ObjectMetaV2Link) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *ObjectMetaV2Link) Msgsize() (s int) {
-	s = 1 + 3 + msgp.Uint64Size + 3 + msgp.Uint64Size + 6 + msgp.Uint8Size + 2 + msgp.IntSize + 2 + msgp.IntSize + 6 + msgp.IntSize + 6 + msgp.IntSize + 5 + msgp.ArrayHeaderSize + (len(z.DataErasureDistribution) * (msgp.Uint8Size)) + 6 + msgp.Uint8Size + 5 + z.DataPartInfoNumbers.Msgsize() + 4 + z.DataPartInfoSizes.Msgsize() + 5 + msgp.IntSize + 6 + msgp.Int64Size + 5 + msgp.MapHeaderSize
+	s = 3 + 3 + msgp.Uint64Size + 3 + msgp.Uint64Size + 6 + msgp.Uint8Size + 2 + msgp.IntSize + 2 + msgp.IntSize + 6 + msgp.IntSize + 6 + msgp.IntSize + 5 + msgp.ArrayHeaderSize + (len(z.DataErasureDistribution) * (msgp.Uint8Size)) + 6 + msgp.Uint8Size + 5 + z.DataPartInfoNumbers.Msgsize() + 4 + z.DataPartInfoSizes.Msgsize() + 6 + msgp.ArrayHeaderSize
+	for za0002 := range z.DataPartInfoChecksums {
+		s += msgp.BytesPrefixSize + len(z.DataPartInfoChecksums[za0002])
+	}
+	s += 7
+	if z.Inline == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Inline.Msgsize()
+	}
+	s += 6 + msgp.Uint8Size + 7 + msgp.Int64Size + 6 + msgp.BoolSize + 5 + msgp.ArrayHeaderSize
+	for za0003 := range z.Replication {
+		s += z.Replication[za0003].Msgsize()
+	}
+	s += 4
+	if z.SSE == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.SSE.Msgsize()
+	}
+	s += 3 + msgp.StringPrefixSize + len(z.StorageClass) + 4 + msgp.Uint8Size + 6 + msgp.StringPrefixSize + len(z.TransitionTier) + 5 + msgp.StringPrefixSize + len(z.TransitionedObject) + 5 + msgp.StringPrefixSize + len(z.TransitionedVersionID) + 5 + msgp.Int64Size + 5 + msgp.IntSize + 6 + msgp.Int64Size + 5 + msgp.MapHeaderSize
 	if z.MetaSys != nil {
-		for za0002, za0003 := range z.MetaSys {
-			_ = za0003
-			s += msgp.StringPrefixSize + len(za0002) + msgp.BytesPrefixSize + len(za0003)
+		for za0004, za0005 := range z.MetaSys {
+			_ = za0005
+			s += msgp.StringPrefixSize + len(za0004) + msgp.BytesPrefixSize + len(za0005)
 		}
 	}
 	s += 6 + msgp.MapHeaderSize
 	if z.MetaUser != nil {
-		for za0004, za0005 := range z.MetaUser {
-			_ = za0005
-			s += msgp.StringPrefixSize + len(za0004) + msgp.ArrayHeaderSize
-			for za0006 := range za0005 {
-				s += msgp.StringPrefixSize + len(za0005[za0006])
+		for za0006, za0007 := range z.MetaUser {
+			_ = za0007
+			s += msgp.StringPrefixSize + len(za0006) + msgp.ArrayHeaderSize
+			for za0008 := range za0007 {
+				s += msgp.StringPrefixSize + len(za0007[za0008])
 			}
 		}
 	}
@@ -1727,7 +2294,7 @@ func (z *ObjectMetaV2Link) Msgsize() (s int) {
 }
 
 // DecodeMsg implements msgp.Decodable
-func (z *ObjectMetaV2Object) DecodeMsg(dc *msgp.Reader) (err error) {
+func (z *ObjectMetaV2Multipart) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
@@ -1744,182 +2311,47 @@ func (z *ObjectMetaV2Object) DecodeMsg(dc *msgp.Reader) (err error) {
 			return
 		}
 		switch msgp.UnsafeString(field) {
-		case "id":
-			z.VersionID, err = dc.ReadUint64()
-			if err != nil {
-				err = msgp.WrapError(err, "VersionID")
-				return
-			}
-		case "dd":
-			z.DataDir, err = dc.ReadUint64()
-			if err != nil {
-				err = msgp.WrapError(err, "DataDir")
-				return
-			}
-		case "ealgo":
-			{
-				var zb0002 uint8
-				zb0002, err = dc.ReadUint8()
-				if err != nil {
-					err = msgp.WrapError(err, "DataErasureAlgorithm")
-					return
-				}
-				z.DataErasureAlgorithm = ErasureAlgo(zb0002)
-			}
-		case "m":
-			z.DataErasureM, err = dc.ReadInt()
-			if err != nil {
-				err = msgp.WrapError(err, "DataErasureM")
-				return
-			}
-		case "n":
-			z.DataErasureN, err = dc.ReadInt()
-			if err != nil {
-				err = msgp.WrapError(err, "DataErasureN")
-				return
-			}
-		case "bsize":
-			z.DataErasureBlockSize, err = dc.ReadInt()
+		case "uid":
+			z.UploadID, err = dc.ReadString()
 			if err != nil {
-				err = msgp.WrapError(err, "DataErasureBlockSize")
+				err = msgp.WrapError(err, "UploadID")
 				return
 			}
-		case "index":
-			z.DataErasureIndex, err = dc.ReadInt()
+		case "init":
+			z.Initiated, err = dc.ReadInt64()
 			if err != nil {
-				err = msgp.WrapError(err, "DataErasureIndex")
+				err = msgp.WrapError(err, "Initiated")
 				return
 			}
-		case "dist":
-			var zb0003 uint32
-			zb0003, err = dc.ReadArrayHeader()
+		case "parts":
+			var zb0002 uint32
+			zb0002, err = dc.ReadArrayHeader()
 			if err != nil {
-				err = msgp.WrapError(err, "DataErasureDistribution")
+				err = msgp.WrapError(err, "Parts")
 				return
 			}
-			if cap(z.DataErasureDistribution) >= int(zb0003) {
-				z.DataErasureDistribution = (z.DataErasureDistribution)[:zb0003]
+			if cap(z.Parts) >= int(zb0002) {
+				z.Parts = (z.Parts)[:zb0002]
 			} else {
-				z.DataErasureDistribution = make([]uint8, zb0003)
-			}
-			for za0001 := range z.DataErasureDistribution {
-				z.DataErasureDistribution[za0001], err = dc.ReadUint8()
-				if err != nil {
-					err = msgp.WrapError(err, "DataErasureDistribution", za0001)
-					return
-				}
+				z.Parts = make([]ObjectMetaV2MultipartPart, zb0002)
 			}
-		case "clago":
-			{
-				var zb0004 uint8
-				zb0004, err = dc.ReadUint8()
+			for za0001 := range z.Parts {
+				err = z.Parts[za0001].DecodeMsg(dc)
 				if err != nil {
-					err = msgp.WrapError(err, "DataErasureChecksumAlgo")
+					err = msgp.WrapError(err, "Parts", za0001)
 					return
 				}
-				z.DataErasureChecksumAlgo = ChecksumAlgo(zb0004)
-			}
-		case "pnum":
-			err = z.DataPartInfoNumbers.DecodeMsg(dc)
-			if err != nil {
-				err = msgp.WrapError(err, "DataPartInfoNumbers")
-				return
-			}
-		case "psz":
-			err = z.DataPartInfoSizes.DecodeMsg(dc)
-			if err != nil {
-				err = msgp.WrapError(err, "DataPartInfoSizes")
-				return
-			}
-		case "size":
-			z.StatSize, err = dc.ReadInt()
-			if err != nil {
-				err = msgp.WrapError(err, "StatSize")
-				return
 			}
-		case "mtime":
-			z.StatModTime, err = dc.ReadInt64()
+		case "object":
+			err = z.Object.DecodeMsg(dc)
 			if err != nil {
-				err = msgp.WrapError(err, "StatModTime")
+				err = msgp.WrapError(err, "Object")
 				return
 			}
-		case "msys":
-			var zb0005 uint32
-			zb0005, err = dc.ReadMapHeader()
+		default:
+			err = dc.Skip()
 			if err != nil {
-				err = msgp.WrapError(err, "MetaSys")
-				return
-			}
-			if z.MetaSys == nil {
-				z.MetaSys = make(map[string][]byte, zb0005)
-			} else if len(z.MetaSys) > 0 {
-				for key := range z.MetaSys {
-					delete(z.MetaSys, key)
-				}
-			}
-			for zb0005 > 0 {
-				zb0005--
-				var za0002 string
-				var za0003 []byte
-				za0002, err = dc.ReadString()
-				if err != nil {
-					err = msgp.WrapError(err, "MetaSys")
-					return
-				}
-				za0003, err = dc.ReadBytes(za0003)
-				if err != nil {
-					err = msgp.WrapError(err, "MetaSys", za0002)
-					return
-				}
-				z.MetaSys[za0002] = za0003
-			}
-		case "muser":
-			var zb0006 uint32
-			zb0006, err = dc.ReadMapHeader()
-			if err != nil {
-				err = msgp.WrapError(err, "MetaUser")
-				return
-			}
-			if z.MetaUser == nil {
-				z.MetaUser = make(map[string][]string, zb0006)
-			} else if len(z.MetaUser) > 0 {
-				for key := range z.MetaUser {
-					delete(z.MetaUser, key)
-				}
-			}
-			for zb0006 > 0 {
-				zb0006--
-				var za0004 string
-				var za0005 []string
-				za0004, err = dc.ReadString()
-				if err != nil {
-					err = msgp.WrapError(err, "MetaUser")
-					return
-				}
-				var zb0007 uint32
-				zb0007, err = dc.ReadArrayHeader()
-				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004)
-					return
-				}
-				if cap(za0005) >= int(zb0007) {
-					za0005 = (za0005)[:zb0007]
-				} else {
-					za0005 = make([]string, zb0007)
-				}
-				for za0006 := range za0005 {
-					za0005[za0006], err = dc.ReadString()
-					if err != nil {
-						err = msgp.WrapError(err, "MetaUser", za0004, za0006)
-						return
-					}
-				}
-				z.MetaUser[za0004] = za0005
-			}
-		default:
-			err = dc.Skip()
-			if err != nil {
-				err = msgp.WrapError(err)
+				err = msgp.WrapError(err)
 				return
 			}
 		}
@@ -1928,151 +2360,249 @@ func (z *ObjectMetaV2Object) DecodeMsg(dc *msgp.Reader) (err error) {
 }
 
 // EncodeMsg implements msgp.Encodable
-func (z *ObjectMetaV2Object) EncodeMsg(en *msgp.Writer) (err error) {
-	// omitempty: check for empty values
-	zb0001Len := uint32(15)
-	var zb0001Mask uint16 /* 15 bits */
-	if z.MetaSys == nil {
-		zb0001Len--
-		zb0001Mask |= 0x2000
-	}
-	if z.MetaUser == nil {
-		zb0001Len--
-		zb0001Mask |= 0x4000
-	}
-	// variable map header, size zb0001Len
-	err = en.Append(0x80 | uint8(zb0001Len))
-	if err != nil {
-		return
-	}
-	if zb0001Len == 0 {
-		return
-	}
-	// write "id"
-	err = en.Append(0xa2, 0x69, 0x64)
-	if err != nil {
-		return
-	}
-	err = en.WriteUint64(z.VersionID)
+func (z *ObjectMetaV2Multipart) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 4
+	// write "uid"
+	err = en.Append(0x84, 0xa3, 0x75, 0x69, 0x64)
 	if err != nil {
-		err = msgp.WrapError(err, "VersionID")
 		return
 	}
-	// write "dd"
-	err = en.Append(0xa2, 0x64, 0x64)
+	err = en.WriteString(z.UploadID)
 	if err != nil {
+		err = msgp.WrapError(err, "UploadID")
 		return
 	}
-	err = en.WriteUint64(z.DataDir)
+	// write "init"
+	err = en.Append(0xa4, 0x69, 0x6e, 0x69, 0x74)
 	if err != nil {
-		err = msgp.WrapError(err, "DataDir")
 		return
 	}
-	// write "ealgo"
-	err = en.Append(0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
+	err = en.WriteInt64(z.Initiated)
 	if err != nil {
+		err = msgp.WrapError(err, "Initiated")
 		return
 	}
-	err = en.WriteUint8(uint8(z.DataErasureAlgorithm))
+	// write "parts"
+	err = en.Append(0xa5, 0x70, 0x61, 0x72, 0x74, 0x73)
 	if err != nil {
-		err = msgp.WrapError(err, "DataErasureAlgorithm")
 		return
 	}
-	// write "m"
-	err = en.Append(0xa1, 0x6d)
+	err = en.WriteArrayHeader(uint32(len(z.Parts)))
 	if err != nil {
+		err = msgp.WrapError(err, "Parts")
 		return
 	}
-	err = en.WriteInt(z.DataErasureM)
-	if err != nil {
-		err = msgp.WrapError(err, "DataErasureM")
-		return
+	for za0001 := range z.Parts {
+		err = z.Parts[za0001].EncodeMsg(en)
+		if err != nil {
+			err = msgp.WrapError(err, "Parts", za0001)
+			return
+		}
 	}
-	// write "n"
-	err = en.Append(0xa1, 0x6e)
+	// write "object"
+	err = en.Append(0xa6, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74)
 	if err != nil {
 		return
 	}
-	err = en.WriteInt(z.DataErasureN)
+	err = z.Object.EncodeMsg(en)
 	if err != nil {
-		err = msgp.WrapError(err, "DataErasureN")
+		err = msgp.WrapError(err, "Object")
 		return
 	}
-	// write "bsize"
-	err = en.Append(0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
-	if err != nil {
-		return
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2Multipart) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "uid"
+	o = append(o, 0x84, 0xa3, 0x75, 0x69, 0x64)
+	o = msgp.AppendString(o, z.UploadID)
+	// string "init"
+	o = append(o, 0xa4, 0x69, 0x6e, 0x69, 0x74)
+	o = msgp.AppendInt64(o, z.Initiated)
+	// string "parts"
+	o = append(o, 0xa5, 0x70, 0x61, 0x72, 0x74, 0x73)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Parts)))
+	for za0001 := range z.Parts {
+		o, err = z.Parts[za0001].MarshalMsg(o)
+		if err != nil {
+			err = msgp.WrapError(err, "Parts", za0001)
+			return
+		}
 	}
-	err = en.WriteInt(z.DataErasureBlockSize)
+	// string "object"
+	o = append(o, 0xa6, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74)
+	o, err = z.Object.MarshalMsg(o)
 	if err != nil {
-		err = msgp.WrapError(err, "DataErasureBlockSize")
+		err = msgp.WrapError(err, "Object")
 		return
 	}
-	// write "index"
-	err = en.Append(0xa5, 0x69, 0x6e, 0x64, 0x65, 0x78)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ObjectMetaV2Multipart) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
 	if err != nil {
+		err = msgp.WrapError(err)
 		return
 	}
-	err = en.WriteInt(z.DataErasureIndex)
-	if err != nil {
-		err = msgp.WrapError(err, "DataErasureIndex")
-		return
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "uid":
+			z.UploadID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "UploadID")
+				return
+			}
+		case "init":
+			z.Initiated, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Initiated")
+				return
+			}
+		case "parts":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Parts")
+				return
+			}
+			if cap(z.Parts) >= int(zb0002) {
+				z.Parts = (z.Parts)[:zb0002]
+			} else {
+				z.Parts = make([]ObjectMetaV2MultipartPart, zb0002)
+			}
+			for za0001 := range z.Parts {
+				bts, err = z.Parts[za0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Parts", za0001)
+					return
+				}
+			}
+		case "object":
+			bts, err = z.Object.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Object")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
 	}
-	// write "dist"
-	err = en.Append(0xa4, 0x64, 0x69, 0x73, 0x74)
-	if err != nil {
-		return
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2Multipart) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.UploadID) + 5 + msgp.Int64Size + 6 + msgp.ArrayHeaderSize
+	for za0001 := range z.Parts {
+		s += z.Parts[za0001].Msgsize()
 	}
-	err = en.WriteArrayHeader(uint32(len(z.DataErasureDistribution)))
+	s += 7 + z.Object.Msgsize()
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *ObjectMetaV2MultipartPart) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
 	if err != nil {
-		err = msgp.WrapError(err, "DataErasureDistribution")
+		err = msgp.WrapError(err)
 		return
 	}
-	for za0001 := range z.DataErasureDistribution {
-		err = en.WriteUint8(z.DataErasureDistribution[za0001])
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
 		if err != nil {
-			err = msgp.WrapError(err, "DataErasureDistribution", za0001)
+			err = msgp.WrapError(err)
 			return
 		}
+		switch msgp.UnsafeString(field) {
+		case "n":
+			z.Number, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Number")
+				return
+			}
+		case "size":
+			z.Size, err = dc.ReadInt()
+			if err != nil {
+				err = msgp.WrapError(err, "Size")
+				return
+			}
+		case "etag":
+			z.ETag, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "ETag")
+				return
+			}
+		case "mtime":
+			z.ModTime, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "ModTime")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
 	}
-	// write "clago"
-	err = en.Append(0xa5, 0x63, 0x6c, 0x61, 0x67, 0x6f)
-	if err != nil {
-		return
-	}
-	err = en.WriteUint8(uint8(z.DataErasureChecksumAlgo))
-	if err != nil {
-		err = msgp.WrapError(err, "DataErasureChecksumAlgo")
-		return
-	}
-	// write "pnum"
-	err = en.Append(0xa4, 0x70, 0x6e, 0x75, 0x6d)
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *ObjectMetaV2MultipartPart) EncodeMsg(en *msgp.Writer) (err error) {
+	// map header, size 4
+	// write "n"
+	err = en.Append(0x84, 0xa1, 0x6e)
 	if err != nil {
 		return
 	}
-	err = z.DataPartInfoNumbers.EncodeMsg(en)
+	err = en.WriteInt(z.Number)
 	if err != nil {
-		err = msgp.WrapError(err, "DataPartInfoNumbers")
+		err = msgp.WrapError(err, "Number")
 		return
 	}
-	// write "psz"
-	err = en.Append(0xa3, 0x70, 0x73, 0x7a)
+	// write "size"
+	err = en.Append(0xa4, 0x73, 0x69, 0x7a, 0x65)
 	if err != nil {
 		return
 	}
-	err = z.DataPartInfoSizes.EncodeMsg(en)
+	err = en.WriteInt(z.Size)
 	if err != nil {
-		err = msgp.WrapError(err, "DataPartInfoSizes")
+		err = msgp.WrapError(err, "Size")
 		return
 	}
-	// write "size"
-	err = en.Append(0xa4, 0x73, 0x69, 0x7a, 0x65)
+	// write "etag"
+	err = en.Append(0xa4, 0x65, 0x74, 0x61, 0x67)
 	if err != nil {
 		return
 	}
-	err = en.WriteInt(z.StatSize)
+	err = en.WriteString(z.ETag)
 	if err != nil {
-		err = msgp.WrapError(err, "StatSize")
+		err = msgp.WrapError(err, "ETag")
 		return
 	}
 	// write "mtime"
@@ -2080,188 +2610,119 @@ func (z *ObjectMetaV2Object) EncodeMsg(en *msgp.Writer) (err error) {
 	if err != nil {
 		return
 	}
-	err = en.WriteInt64(z.StatModTime)
+	err = en.WriteInt64(z.ModTime)
 	if err != nil {
-		err = msgp.WrapError(err, "StatModTime")
+		err = msgp.WrapError(err, "ModTime")
 		return
 	}
-	if (zb0001Mask & 0x2000) == 0 { // if not empty
-		// write "msys"
-		err = en.Append(0xa4, 0x6d, 0x73, 0x79, 0x73)
-		if err != nil {
-			return
-		}
-		err = en.WriteMapHeader(uint32(len(z.MetaSys)))
-		if err != nil {
-			err = msgp.WrapError(err, "MetaSys")
-			return
-		}
-		for za0002, za0003 := range z.MetaSys {
-			err = en.WriteString(za0002)
-			if err != nil {
-				err = msgp.WrapError(err, "MetaSys")
-				return
-			}
-			err = en.WriteBytes(za0003)
-			if err != nil {
-				err = msgp.WrapError(err, "MetaSys", za0002)
-				return
-			}
-		}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2MultipartPart) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// map header, size 4
+	// string "n"
+	o = append(o, 0x84, 0xa1, 0x6e)
+	o = msgp.AppendInt(o, z.Number)
+	// string "size"
+	o = append(o, 0xa4, 0x73, 0x69, 0x7a, 0x65)
+	o = msgp.AppendInt(o, z.Size)
+	// string "etag"
+	o = append(o, 0xa4, 0x65, 0x74, 0x61, 0x67)
+	o = msgp.AppendString(o, z.ETag)
+	// string "mtime"
+	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
+	o = msgp.AppendInt64(o, z.ModTime)
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ObjectMetaV2MultipartPart) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
 	}
-	if (zb0001Mask & 0x4000) == 0 { // if not empty
-		// write "muser"
-		err = en.Append(0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
-		if err != nil {
-			return
-		}
-		err = en.WriteMapHeader(uint32(len(z.MetaUser)))
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
 		if err != nil {
-			err = msgp.WrapError(err, "MetaUser")
+			err = msgp.WrapError(err)
 			return
 		}
-		for za0004, za0005 := range z.MetaUser {
-			err = en.WriteString(za0004)
+		switch msgp.UnsafeString(field) {
+		case "n":
+			z.Number, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "MetaUser")
+				err = msgp.WrapError(err, "Number")
 				return
 			}
-			err = en.WriteArrayHeader(uint32(len(za0005)))
+		case "size":
+			z.Size, bts, err = msgp.ReadIntBytes(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "MetaUser", za0004)
+				err = msgp.WrapError(err, "Size")
 				return
 			}
-			for za0006 := range za0005 {
-				err = en.WriteString(za0005[za0006])
-				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004, za0006)
-					return
-				}
+		case "etag":
+			z.ETag, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ETag")
+				return
+			}
+		case "mtime":
+			z.ModTime, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ModTime")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
 			}
 		}
 	}
+	o = bts
 	return
 }
 
-// MarshalMsg implements msgp.Marshaler
-func (z *ObjectMetaV2Object) MarshalMsg(b []byte) (o []byte, err error) {
-	o = msgp.Require(b, z.Msgsize())
-	// omitempty: check for empty values
-	zb0001Len := uint32(15)
-	var zb0001Mask uint16 /* 15 bits */
-	if z.MetaSys == nil {
-		zb0001Len--
-		zb0001Mask |= 0x2000
-	}
-	if z.MetaUser == nil {
-		zb0001Len--
-		zb0001Mask |= 0x4000
-	}
-	// variable map header, size zb0001Len
-	o = append(o, 0x80|uint8(zb0001Len))
-	if zb0001Len == 0 {
-		return
-	}
-	// string "id"
-	o = append(o, 0xa2, 0x69, 0x64)
-	o = msgp.AppendUint64(o, z.VersionID)
-	// string "dd"
-	o = append(o, 0xa2, 0x64, 0x64)
-	o = msgp.AppendUint64(o, z.DataDir)
-	// string "ealgo"
-	o = append(o, 0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
-	o = msgp.AppendUint8(o, uint8(z.DataErasureAlgorithm))
-	// string "m"
-	o = append(o, 0xa1, 0x6d)
-	o = msgp.AppendInt(o, z.DataErasureM)
-	// string "n"
-	o = append(o, 0xa1, 0x6e)
-	o = msgp.AppendInt(o, z.DataErasureN)
-	// string "bsize"
-	o = append(o, 0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
-	o = msgp.AppendInt(o, z.DataErasureBlockSize)
-	// string "index"
-	o = append(o, 0xa5, 0x69, 0x6e, 0x64, 0x65, 0x78)
-	o = msgp.AppendInt(o, z.DataErasureIndex)
-	// string "dist"
-	o = append(o, 0xa4, 0x64, 0x69, 0x73, 0x74)
-	o = msgp.AppendArrayHeader(o, uint32(len(z.DataErasureDistribution)))
-	for za0001 := range z.DataErasureDistribution {
-		o = msgp.AppendUint8(o, z.DataErasureDistribution[za0001])
-	}
-	// string "clago"
-	o = append(o, 0xa5, 0x63, 0x6c, 0x61, 0x67, 0x6f)
-	o = msgp.AppendUint8(o, uint8(z.DataErasureChecksumAlgo))
-	// string "pnum"
-	o = append(o, 0xa4, 0x70, 0x6e, 0x75, 0x6d)
-	o, err = z.DataPartInfoNumbers.MarshalMsg(o)
-	if err != nil {
-		err = msgp.WrapError(err, "DataPartInfoNumbers")
-		return
-	}
-	// string "psz"
-	o = append(o, 0xa3, 0x70, 0x73, 0x7a)
-	o, err = z.DataPartInfoSizes.MarshalMsg(o)
-	if err != nil {
-		err = msgp.WrapError(err, "DataPartInfoSizes")
-		return
-	}
-	// string "size"
-	o = append(o, 0xa4, 0x73, 0x69, 0x7a, 0x65)
-	o = msgp.AppendInt(o, z.StatSize)
-	// string "mtime"
-	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
-	o = msgp.AppendInt64(o, z.StatModTime)
-	if (zb0001Mask & 0x2000) == 0 { // if not empty
-		// string "msys"
-		o = append(o, 0xa4, 0x6d, 0x73, 0x79, 0x73)
-		o = msgp.AppendMapHeader(o, uint32(len(z.MetaSys)))
-		for za0002, za0003 := range z.MetaSys {
-			o = msgp.AppendString(o, za0002)
-			o = msgp.AppendBytes(o, za0003)
-		}
-	}
-	if (zb0001Mask & 0x4000) == 0 { // if not empty
-		// string "muser"
-		o = append(o, 0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
-		o = msgp.AppendMapHeader(o, uint32(len(z.MetaUser)))
-		for za0004, za0005 := range z.MetaUser {
-			o = msgp.AppendString(o, za0004)
-			o = msgp.AppendArrayHeader(o, uint32(len(za0005)))
-			for za0006 := range za0005 {
-				o = msgp.AppendString(o, za0005[za0006])
-			}
-		}
-	}
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2MultipartPart) Msgsize() (s int) {
+	s = 1 + 2 + msgp.IntSize + 5 + msgp.IntSize + 5 + msgp.StringPrefixSize + len(z.ETag) + 6 + msgp.Int64Size
 	return
 }
 
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
+// DecodeMsg implements msgp.Decodable
+func (z *ObjectMetaV2Object) DecodeMsg(dc *msgp.Reader) (err error) {
 	var field []byte
 	_ = field
 	var zb0001 uint32
-	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	zb0001, err = dc.ReadMapHeader()
 	if err != nil {
 		err = msgp.WrapError(err)
 		return
 	}
 	for zb0001 > 0 {
 		zb0001--
-		field, bts, err = msgp.ReadMapKeyZC(bts)
+		field, err = dc.ReadMapKeyPtr()
 		if err != nil {
 			err = msgp.WrapError(err)
 			return
 		}
 		switch msgp.UnsafeString(field) {
 		case "id":
-			z.VersionID, bts, err = msgp.ReadUint64Bytes(bts)
+			z.VersionID, err = dc.ReadUint64()
 			if err != nil {
 				err = msgp.WrapError(err, "VersionID")
 				return
 			}
 		case "dd":
-			z.DataDir, bts, err = msgp.ReadUint64Bytes(bts)
+			z.DataDir, err = dc.ReadUint64()
 			if err != nil {
 				err = msgp.WrapError(err, "DataDir")
 				return
@@ -2269,7 +2730,7 @@ func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
 		case "ealgo":
 			{
 				var zb0002 uint8
-				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				zb0002, err = dc.ReadUint8()
 				if err != nil {
 					err = msgp.WrapError(err, "DataErasureAlgorithm")
 					return
@@ -2277,32 +2738,32 @@ func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				z.DataErasureAlgorithm = ErasureAlgo(zb0002)
 			}
 		case "m":
-			z.DataErasureM, bts, err = msgp.ReadIntBytes(bts)
+			z.DataErasureM, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "DataErasureM")
 				return
 			}
 		case "n":
-			z.DataErasureN, bts, err = msgp.ReadIntBytes(bts)
+			z.DataErasureN, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "DataErasureN")
 				return
 			}
 		case "bsize":
-			z.DataErasureBlockSize, bts, err = msgp.ReadIntBytes(bts)
+			z.DataErasureBlockSize, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "DataErasureBlockSize")
 				return
 			}
 		case "index":
-			z.DataErasureIndex, bts, err = msgp.ReadIntBytes(bts)
+			z.DataErasureIndex, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "DataErasureIndex")
 				return
 			}
 		case "dist":
 			var zb0003 uint32
-			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			zb0003, err = dc.ReadArrayHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "DataErasureDistribution")
 				return
@@ -2313,7 +2774,7 @@ func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				z.DataErasureDistribution = make([]uint8, zb0003)
 			}
 			for za0001 := range z.DataErasureDistribution {
-				z.DataErasureDistribution[za0001], bts, err = msgp.ReadUint8Bytes(bts)
+				z.DataErasureDistribution[za0001], err = dc.ReadUint8()
 				if err != nil {
 					err = msgp.WrapError(err, "DataErasureDistribution", za0001)
 					return
@@ -2322,7 +2783,7 @@ func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
 		case "clago":
 			{
 				var zb0004 uint8
-				zb0004, bts, err = msgp.ReadUint8Bytes(bts)
+				zb0004, err = dc.ReadUint8()
 				if err != nil {
 					err = msgp.WrapError(err, "DataErasureChecksumAlgo")
 					return
@@ -2330,132 +2791,2077 @@ func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				z.DataErasureChecksumAlgo = ChecksumAlgo(zb0004)
 			}
 		case "pnum":
-			bts, err = z.DataPartInfoNumbers.UnmarshalMsg(bts)
+			err = z.DataPartInfoNumbers.DecodeMsg(dc)
 			if err != nil {
 				err = msgp.WrapError(err, "DataPartInfoNumbers")
 				return
 			}
 		case "psz":
-			bts, err = z.DataPartInfoSizes.UnmarshalMsg(bts)
+			err = z.DataPartInfoSizes.DecodeMsg(dc)
 			if err != nil {
 				err = msgp.WrapError(err, "DataPartInfoSizes")
 				return
 			}
+		case "pcsum":
+			var zb0005 uint32
+			zb0005, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoChecksums")
+				return
+			}
+			if cap(z.DataPartInfoChecksums) >= int(zb0005) {
+				z.DataPartInfoChecksums = (z.DataPartInfoChecksums)[:zb0005]
+			} else {
+				z.DataPartInfoChecksums = make([][]byte, zb0005)
+			}
+			for za0002 := range z.DataPartInfoChecksums {
+				z.DataPartInfoChecksums[za0002], err = dc.ReadBytes(z.DataPartInfoChecksums[za0002])
+				if err != nil {
+					err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+					return
+				}
+			}
+		case "inline":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+				z.Inline = nil
+			} else {
+				if z.Inline == nil {
+					z.Inline = new(InlineData)
+				}
+				err = z.Inline.DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+			}
+		case "rmode":
+			{
+				var zb0006 uint8
+				zb0006, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "RetentionMode")
+					return
+				}
+				z.RetentionMode = RetentionMode(zb0006)
+			}
+		case "runtil":
+			z.RetainUntil, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "RetainUntil")
+				return
+			}
+		case "lhold":
+			z.LegalHold, err = dc.ReadBool()
+			if err != nil {
+				err = msgp.WrapError(err, "LegalHold")
+				return
+			}
+		case "repl":
+			var zb0007 uint32
+			zb0007, err = dc.ReadArrayHeader()
+			if err != nil {
+				err = msgp.WrapError(err, "Replication")
+				return
+			}
+			if cap(z.Replication) >= int(zb0007) {
+				z.Replication = (z.Replication)[:zb0007]
+			} else {
+				z.Replication = make([]ObjectMetaV2Replication, zb0007)
+			}
+			for za0003 := range z.Replication {
+				err = z.Replication[za0003].DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "Replication", za0003)
+					return
+				}
+			}
+		case "sse":
+			if dc.IsNil() {
+				err = dc.ReadNil()
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+				z.SSE = nil
+			} else {
+				if z.SSE == nil {
+					z.SSE = new(ObjectMetaV2SSE)
+				}
+				err = z.SSE.DecodeMsg(dc)
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+			}
+		case "sc":
+			z.StorageClass, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "StorageClass")
+				return
+			}
+		case "tst":
+			{
+				var zb0008 uint8
+				zb0008, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "TransitionStatus")
+					return
+				}
+				z.TransitionStatus = TransitionStatus(zb0008)
+			}
+		case "ttier":
+			z.TransitionTier, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionTier")
+				return
+			}
+		case "tobj":
+			z.TransitionedObject, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedObject")
+				return
+			}
+		case "tvid":
+			z.TransitionedVersionID, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedVersionID")
+				return
+			}
+		case "rexp":
+			z.RestoreExpires, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "RestoreExpires")
+				return
+			}
 		case "size":
-			z.StatSize, bts, err = msgp.ReadIntBytes(bts)
+			z.StatSize, err = dc.ReadInt()
 			if err != nil {
 				err = msgp.WrapError(err, "StatSize")
 				return
 			}
 		case "mtime":
-			z.StatModTime, bts, err = msgp.ReadInt64Bytes(bts)
+			z.StatModTime, err = dc.ReadInt64()
 			if err != nil {
 				err = msgp.WrapError(err, "StatModTime")
 				return
 			}
 		case "msys":
-			var zb0005 uint32
-			zb0005, bts, err = msgp.ReadMapHeaderBytes(bts)
+			var zb0009 uint32
+			zb0009, err = dc.ReadMapHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "MetaSys")
 				return
 			}
 			if z.MetaSys == nil {
-				z.MetaSys = make(map[string][]byte, zb0005)
+				z.MetaSys = make(map[string][]byte, zb0009)
 			} else if len(z.MetaSys) > 0 {
 				for key := range z.MetaSys {
 					delete(z.MetaSys, key)
 				}
 			}
-			for zb0005 > 0 {
-				var za0002 string
-				var za0003 []byte
-				zb0005--
-				za0002, bts, err = msgp.ReadStringBytes(bts)
+			for zb0009 > 0 {
+				zb0009--
+				var za0004 string
+				var za0005 []byte
+				za0004, err = dc.ReadString()
 				if err != nil {
 					err = msgp.WrapError(err, "MetaSys")
 					return
 				}
-				za0003, bts, err = msgp.ReadBytesBytes(bts, za0003)
+				za0005, err = dc.ReadBytes(za0005)
 				if err != nil {
-					err = msgp.WrapError(err, "MetaSys", za0002)
+					err = msgp.WrapError(err, "MetaSys", za0004)
 					return
 				}
-				z.MetaSys[za0002] = za0003
+				z.MetaSys[za0004] = za0005
 			}
 		case "muser":
-			var zb0006 uint32
-			zb0006, bts, err = msgp.ReadMapHeaderBytes(bts)
+			var zb0010 uint32
+			zb0010, err = dc.ReadMapHeader()
 			if err != nil {
 				err = msgp.WrapError(err, "MetaUser")
 				return
 			}
 			if z.MetaUser == nil {
-				z.MetaUser = make(map[string][]string, zb0006)
+				z.MetaUser = make(map[string][]string, zb0010)
 			} else if len(z.MetaUser) > 0 {
 				for key := range z.MetaUser {
 					delete(z.MetaUser, key)
 				}
 			}
-			for zb0006 > 0 {
-				var za0004 string
-				var za0005 []string
-				zb0006--
-				za0004, bts, err = msgp.ReadStringBytes(bts)
+			for zb0010 > 0 {
+				zb0010--
+				var za0006 string
+				var za0007 []string
+				za0006, err = dc.ReadString()
 				if err != nil {
 					err = msgp.WrapError(err, "MetaUser")
 					return
 				}
-				var zb0007 uint32
-				zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				var zb0011 uint32
+				zb0011, err = dc.ReadArrayHeader()
 				if err != nil {
-					err = msgp.WrapError(err, "MetaUser", za0004)
+					err = msgp.WrapError(err, "MetaUser", za0006)
 					return
 				}
-				if cap(za0005) >= int(zb0007) {
-					za0005 = (za0005)[:zb0007]
+				if cap(za0007) >= int(zb0011) {
+					za0007 = (za0007)[:zb0011]
 				} else {
-					za0005 = make([]string, zb0007)
+					za0007 = make([]string, zb0011)
 				}
-				for za0006 := range za0005 {
-					za0005[za0006], bts, err = msgp.ReadStringBytes(bts)
+				for za0008 := range za0007 {
+					za0007[za0008], err = dc.ReadString()
 					if err != nil {
-						err = msgp.WrapError(err, "MetaUser", za0004, za0006)
+						err = msgp.WrapError(err, "MetaUser", za0006, za0008)
 						return
 					}
 				}
-				z.MetaUser[za0004] = za0005
+				z.MetaUser[za0006] = za0007
 			}
 		default:
-			bts, err = msgp.Skip(bts)
+			err = dc.Skip()
 			if err != nil {
 				err = msgp.WrapError(err)
 				return
 			}
 		}
 	}
-	o = bts
 	return
 }
 
-// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *ObjectMetaV2Object) Msgsize() (s int) {
-	s = 1 + 3 + msgp.Uint64Size + 3 + msgp.Uint64Size + 6 + msgp.Uint8Size + 2 + msgp.IntSize + 2 + msgp.IntSize + 6 + msgp.IntSize + 6 + msgp.IntSize + 5 + msgp.ArrayHeaderSize + (len(z.DataErasureDistribution) * (msgp.Uint8Size)) + 6 + msgp.Uint8Size + 5 + z.DataPartInfoNumbers.Msgsize() + 4 + z.DataPartInfoSizes.Msgsize() + 5 + msgp.IntSize + 6 + msgp.Int64Size + 5 + msgp.MapHeaderSize
-	if z.MetaSys != nil {
-		for za0002, za0003 := range z.MetaSys {
-			_ = za0003
-			s += msgp.StringPrefixSize + len(za0002) + msgp.BytesPrefixSize + len(za0003)
+// EncodeMsg implements msgp.Encodable
+func (z *ObjectMetaV2Object) EncodeMsg(en *msgp.Writer) (err error) {
+	// omitempty: check for empty values
+	zb0001Len := uint32(28)
+	var zb0001Mask uint32 /* 28 bits */
+	if z.DataPartInfoChecksums == nil {
+		zb0001Len--
+		zb0001Mask |= 0x800
+	}
+	if z.Inline == nil {
+		zb0001Len--
+		zb0001Mask |= 0x1000
+	}
+	if z.RetentionMode == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x2000
+	}
+	if z.RetainUntil == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x4000
+	}
+	if z.LegalHold == false {
+		zb0001Len--
+		zb0001Mask |= 0x8000
+	}
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10000
+	}
+	if z.SSE == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20000
+	}
+	if z.StorageClass == "" {
+		zb0001Len--
+		zb0001Mask |= 0x40000
+	}
+	if z.TransitionStatus == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x80000
+	}
+	if z.TransitionTier == "" {
+		zb0001Len--
+		zb0001Mask |= 0x100000
+	}
+	if z.TransitionedObject == "" {
+		zb0001Len--
+		zb0001Mask |= 0x200000
+	}
+	if z.TransitionedVersionID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x400000
+	}
+	if z.RestoreExpires == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x800000
+	}
+	if z.MetaSys == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4000000
+	}
+	if z.MetaUser == nil {
+		zb0001Len--
+		zb0001Mask |= 0x8000000
+	}
+	// variable map header, size zb0001Len
+	err = en.WriteMapHeader(zb0001Len)
+	if err != nil {
+		return
+	}
+	if zb0001Len == 0 {
+		return
+	}
+	// write "id"
+	err = en.Append(0xa2, 0x69, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.VersionID)
+	if err != nil {
+		err = msgp.WrapError(err, "VersionID")
+		return
+	}
+	// write "dd"
+	err = en.Append(0xa2, 0x64, 0x64)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint64(z.DataDir)
+	if err != nil {
+		err = msgp.WrapError(err, "DataDir")
+		return
+	}
+	// write "ealgo"
+	err = en.Append(0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint8(uint8(z.DataErasureAlgorithm))
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureAlgorithm")
+		return
+	}
+	// write "m"
+	err = en.Append(0xa1, 0x6d)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.DataErasureM)
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureM")
+		return
+	}
+	// write "n"
+	err = en.Append(0xa1, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.DataErasureN)
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureN")
+		return
+	}
+	// write "bsize"
+	err = en.Append(0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.DataErasureBlockSize)
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureBlockSize")
+		return
+	}
+	// write "index"
+	err = en.Append(0xa5, 0x69, 0x6e, 0x64, 0x65, 0x78)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.DataErasureIndex)
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureIndex")
+		return
+	}
+	// write "dist"
+	err = en.Append(0xa4, 0x64, 0x69, 0x73, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteArrayHeader(uint32(len(z.DataErasureDistribution)))
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureDistribution")
+		return
+	}
+	for za0001 := range z.DataErasureDistribution {
+		err = en.WriteUint8(z.DataErasureDistribution[za0001])
+		if err != nil {
+			err = msgp.WrapError(err, "DataErasureDistribution", za0001)
+			return
 		}
 	}
-	s += 6 + msgp.MapHeaderSize
-	if z.MetaUser != nil {
-		for za0004, za0005 := range z.MetaUser {
-			_ = za0005
-			s += msgp.StringPrefixSize + len(za0004) + msgp.ArrayHeaderSize
-			for za0006 := range za0005 {
-				s += msgp.StringPrefixSize + len(za0005[za0006])
+	// write "clago"
+	err = en.Append(0xa5, 0x63, 0x6c, 0x61, 0x67, 0x6f)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint8(uint8(z.DataErasureChecksumAlgo))
+	if err != nil {
+		err = msgp.WrapError(err, "DataErasureChecksumAlgo")
+		return
+	}
+	// write "pnum"
+	err = en.Append(0xa4, 0x70, 0x6e, 0x75, 0x6d)
+	if err != nil {
+		return
+	}
+	err = z.DataPartInfoNumbers.EncodeMsg(en)
+	if err != nil {
+		err = msgp.WrapError(err, "DataPartInfoNumbers")
+		return
+	}
+	// write "psz"
+	err = en.Append(0xa3, 0x70, 0x73, 0x7a)
+	if err != nil {
+		return
+	}
+	err = z.DataPartInfoSizes.EncodeMsg(en)
+	if err != nil {
+		err = msgp.WrapError(err, "DataPartInfoSizes")
+		return
+	}
+	if (zb0001Mask & 0x800) == 0 { // if not empty
+		// write "pcsum"
+		err = en.Append(0xa5, 0x70, 0x63, 0x73, 0x75, 0x6d)
+		if err != nil {
+			return
+		}
+		err = en.WriteArrayHeader(uint32(len(z.DataPartInfoChecksums)))
+		if err != nil {
+			err = msgp.WrapError(err, "DataPartInfoChecksums")
+			return
+		}
+		for za0002 := range z.DataPartInfoChecksums {
+			err = en.WriteBytes(z.DataPartInfoChecksums[za0002])
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x1000) == 0 { // if not empty
+		// write "inline"
+		err = en.Append(0xa6, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65)
+		if err != nil {
+			return
+		}
+		if z.Inline == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			err = z.Inline.EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "Inline")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x2000) == 0 { // if not empty
+		// write "rmode"
+		err = en.Append(0xa5, 0x72, 0x6d, 0x6f, 0x64, 0x65)
+		if err != nil {
+			return
+		}
+		err = en.WriteUint8(uint8(z.RetentionMode))
+		if err != nil {
+			err = msgp.WrapError(err, "RetentionMode")
+			return
+		}
+	}
+	if (zb0001Mask & 0x4000) == 0 { // if not empty
+		// write "runtil"
+		err = en.Append(0xa6, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6c)
+		if err != nil {
+			return
+		}
+		err = en.WriteInt64(z.RetainUntil)
+		if err != nil {
+			err = msgp.WrapError(err, "RetainUntil")
+			return
+		}
+	}
+	if (zb0001Mask & 0x8000) == 0 { // if not empty
+		// write "lhold"
+		err = en.Append(0xa5, 0x6c, 0x68, 0x6f, 0x6c, 0x64)
+		if err != nil {
+			return
+		}
+		err = en.WriteBool(z.LegalHold)
+		if err != nil {
+			err = msgp.WrapError(err, "LegalHold")
+			return
+		}
+	}
+	if (zb0001Mask & 0x10000) == 0 { // if not empty
+		// write "repl"
+		err = en.Append(0xa4, 0x72, 0x65, 0x70, 0x6c)
+		if err != nil {
+			return
+		}
+		err = en.WriteArrayHeader(uint32(len(z.Replication)))
+		if err != nil {
+			err = msgp.WrapError(err, "Replication")
+			return
+		}
+		for za0003 := range z.Replication {
+			err = z.Replication[za0003].EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication", za0003)
+				return
 			}
 		}
 	}
+	if (zb0001Mask & 0x20000) == 0 { // if not empty
+		// write "sse"
+		err = en.Append(0xa3, 0x73, 0x73, 0x65)
+		if err != nil {
+			return
+		}
+		if z.SSE == nil {
+			err = en.WriteNil()
+			if err != nil {
+				return
+			}
+		} else {
+			err = z.SSE.EncodeMsg(en)
+			if err != nil {
+				err = msgp.WrapError(err, "SSE")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x40000) == 0 { // if not empty
+		// write "sc"
+		err = en.Append(0xa2, 0x73, 0x63)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.StorageClass)
+		if err != nil {
+			err = msgp.WrapError(err, "StorageClass")
+			return
+		}
+	}
+	if (zb0001Mask & 0x80000) == 0 { // if not empty
+		// write "tst"
+		err = en.Append(0xa3, 0x74, 0x73, 0x74)
+		if err != nil {
+			return
+		}
+		err = en.WriteUint8(uint8(z.TransitionStatus))
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionStatus")
+			return
+		}
+	}
+	if (zb0001Mask & 0x100000) == 0 { // if not empty
+		// write "ttier"
+		err = en.Append(0xa5, 0x74, 0x74, 0x69, 0x65, 0x72)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionTier)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionTier")
+			return
+		}
+	}
+	if (zb0001Mask & 0x200000) == 0 { // if not empty
+		// write "tobj"
+		err = en.Append(0xa4, 0x74, 0x6f, 0x62, 0x6a)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionedObject)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionedObject")
+			return
+		}
+	}
+	if (zb0001Mask & 0x400000) == 0 { // if not empty
+		// write "tvid"
+		err = en.Append(0xa4, 0x74, 0x76, 0x69, 0x64)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.TransitionedVersionID)
+		if err != nil {
+			err = msgp.WrapError(err, "TransitionedVersionID")
+			return
+		}
+	}
+	if (zb0001Mask & 0x800000) == 0 { // if not empty
+		// write "rexp"
+		err = en.Append(0xa4, 0x72, 0x65, 0x78, 0x70)
+		if err != nil {
+			return
+		}
+		err = en.WriteInt64(z.RestoreExpires)
+		if err != nil {
+			err = msgp.WrapError(err, "RestoreExpires")
+			return
+		}
+	}
+	// write "size"
+	err = en.Append(0xa4, 0x73, 0x69, 0x7a, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt(z.StatSize)
+	if err != nil {
+		err = msgp.WrapError(err, "StatSize")
+		return
+	}
+	// write "mtime"
+	err = en.Append(0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.StatModTime)
+	if err != nil {
+		err = msgp.WrapError(err, "StatModTime")
+		return
+	}
+	if (zb0001Mask & 0x4000000) == 0 { // if not empty
+		// write "msys"
+		err = en.Append(0xa4, 0x6d, 0x73, 0x79, 0x73)
+		if err != nil {
+			return
+		}
+		err = en.WriteMapHeader(uint32(len(z.MetaSys)))
+		if err != nil {
+			err = msgp.WrapError(err, "MetaSys")
+			return
+		}
+		for za0004, za0005 := range z.MetaSys {
+			err = en.WriteString(za0004)
+			if err != nil {
+				err = msgp.WrapError(err, "MetaSys")
+				return
+			}
+			err = en.WriteBytes(za0005)
+			if err != nil {
+				err = msgp.WrapError(err, "MetaSys", za0004)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x8000000) == 0 { // if not empty
+		// write "muser"
+		err = en.Append(0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
+		if err != nil {
+			return
+		}
+		err = en.WriteMapHeader(uint32(len(z.MetaUser)))
+		if err != nil {
+			err = msgp.WrapError(err, "MetaUser")
+			return
+		}
+		for za0006, za0007 := range z.MetaUser {
+			err = en.WriteString(za0006)
+			if err != nil {
+				err = msgp.WrapError(err, "MetaUser")
+				return
+			}
+			err = en.WriteArrayHeader(uint32(len(za0007)))
+			if err != nil {
+				err = msgp.WrapError(err, "MetaUser", za0006)
+				return
+			}
+			for za0008 := range za0007 {
+				err = en.WriteString(za0007[za0008])
+				if err != nil {
+					err = msgp.WrapError(err, "MetaUser", za0006, za0008)
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2Object) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(28)
+	var zb0001Mask uint32 /* 28 bits */
+	if z.DataPartInfoChecksums == nil {
+		zb0001Len--
+		zb0001Mask |= 0x800
+	}
+	if z.Inline == nil {
+		zb0001Len--
+		zb0001Mask |= 0x1000
+	}
+	if z.RetentionMode == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x2000
+	}
+	if z.RetainUntil == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x4000
+	}
+	if z.LegalHold == false {
+		zb0001Len--
+		zb0001Mask |= 0x8000
+	}
+	if z.Replication == nil {
+		zb0001Len--
+		zb0001Mask |= 0x10000
+	}
+	if z.SSE == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20000
+	}
+	if z.StorageClass == "" {
+		zb0001Len--
+		zb0001Mask |= 0x40000
+	}
+	if z.TransitionStatus == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x80000
+	}
+	if z.TransitionTier == "" {
+		zb0001Len--
+		zb0001Mask |= 0x100000
+	}
+	if z.TransitionedObject == "" {
+		zb0001Len--
+		zb0001Mask |= 0x200000
+	}
+	if z.TransitionedVersionID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x400000
+	}
+	if z.RestoreExpires == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x800000
+	}
+	if z.MetaSys == nil {
+		zb0001Len--
+		zb0001Mask |= 0x4000000
+	}
+	if z.MetaUser == nil {
+		zb0001Len--
+		zb0001Mask |= 0x8000000
+	}
+	// variable map header, size zb0001Len
+	o = msgp.AppendMapHeader(o, zb0001Len)
+	if zb0001Len == 0 {
+		return
+	}
+	// string "id"
+	o = append(o, 0xa2, 0x69, 0x64)
+	o = msgp.AppendUint64(o, z.VersionID)
+	// string "dd"
+	o = append(o, 0xa2, 0x64, 0x64)
+	o = msgp.AppendUint64(o, z.DataDir)
+	// string "ealgo"
+	o = append(o, 0xa5, 0x65, 0x61, 0x6c, 0x67, 0x6f)
+	o = msgp.AppendUint8(o, uint8(z.DataErasureAlgorithm))
+	// string "m"
+	o = append(o, 0xa1, 0x6d)
+	o = msgp.AppendInt(o, z.DataErasureM)
+	// string "n"
+	o = append(o, 0xa1, 0x6e)
+	o = msgp.AppendInt(o, z.DataErasureN)
+	// string "bsize"
+	o = append(o, 0xa5, 0x62, 0x73, 0x69, 0x7a, 0x65)
+	o = msgp.AppendInt(o, z.DataErasureBlockSize)
+	// string "index"
+	o = append(o, 0xa5, 0x69, 0x6e, 0x64, 0x65, 0x78)
+	o = msgp.AppendInt(o, z.DataErasureIndex)
+	// string "dist"
+	o = append(o, 0xa4, 0x64, 0x69, 0x73, 0x74)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.DataErasureDistribution)))
+	for za0001 := range z.DataErasureDistribution {
+		o = msgp.AppendUint8(o, z.DataErasureDistribution[za0001])
+	}
+	// string "clago"
+	o = append(o, 0xa5, 0x63, 0x6c, 0x61, 0x67, 0x6f)
+	o = msgp.AppendUint8(o, uint8(z.DataErasureChecksumAlgo))
+	// string "pnum"
+	o = append(o, 0xa4, 0x70, 0x6e, 0x75, 0x6d)
+	o, err = z.DataPartInfoNumbers.MarshalMsg(o)
+	if err != nil {
+		err = msgp.WrapError(err, "DataPartInfoNumbers")
+		return
+	}
+	// string "psz"
+	o = append(o, 0xa3, 0x70, 0x73, 0x7a)
+	o, err = z.DataPartInfoSizes.MarshalMsg(o)
+	if err != nil {
+		err = msgp.WrapError(err, "DataPartInfoSizes")
+		return
+	}
+	if (zb0001Mask & 0x800) == 0 { // if not empty
+		// string "pcsum"
+		o = append(o, 0xa5, 0x70, 0x63, 0x73, 0x75, 0x6d)
+		o = msgp.AppendArrayHeader(o, uint32(len(z.DataPartInfoChecksums)))
+		for za0002 := range z.DataPartInfoChecksums {
+			o = msgp.AppendBytes(o, z.DataPartInfoChecksums[za0002])
+		}
+	}
+	if (zb0001Mask & 0x1000) == 0 { // if not empty
+		// string "inline"
+		o = append(o, 0xa6, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65)
+		if z.Inline == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			o, err = z.Inline.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Inline")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x2000) == 0 { // if not empty
+		// string "rmode"
+		o = append(o, 0xa5, 0x72, 0x6d, 0x6f, 0x64, 0x65)
+		o = msgp.AppendUint8(o, uint8(z.RetentionMode))
+	}
+	if (zb0001Mask & 0x4000) == 0 { // if not empty
+		// string "runtil"
+		o = append(o, 0xa6, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6c)
+		o = msgp.AppendInt64(o, z.RetainUntil)
+	}
+	if (zb0001Mask & 0x8000) == 0 { // if not empty
+		// string "lhold"
+		o = append(o, 0xa5, 0x6c, 0x68, 0x6f, 0x6c, 0x64)
+		o = msgp.AppendBool(o, z.LegalHold)
+	}
+	if (zb0001Mask & 0x10000) == 0 { // if not empty
+		// string "repl"
+		o = append(o, 0xa4, 0x72, 0x65, 0x70, 0x6c)
+		o = msgp.AppendArrayHeader(o, uint32(len(z.Replication)))
+		for za0003 := range z.Replication {
+			o, err = z.Replication[za0003].MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication", za0003)
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x20000) == 0 { // if not empty
+		// string "sse"
+		o = append(o, 0xa3, 0x73, 0x73, 0x65)
+		if z.SSE == nil {
+			o = msgp.AppendNil(o)
+		} else {
+			o, err = z.SSE.MarshalMsg(o)
+			if err != nil {
+				err = msgp.WrapError(err, "SSE")
+				return
+			}
+		}
+	}
+	if (zb0001Mask & 0x40000) == 0 { // if not empty
+		// string "sc"
+		o = append(o, 0xa2, 0x73, 0x63)
+		o = msgp.AppendString(o, z.StorageClass)
+	}
+	if (zb0001Mask & 0x80000) == 0 { // if not empty
+		// string "tst"
+		o = append(o, 0xa3, 0x74, 0x73, 0x74)
+		o = msgp.AppendUint8(o, uint8(z.TransitionStatus))
+	}
+	if (zb0001Mask & 0x100000) == 0 { // if not empty
+		// string "ttier"
+		o = append(o, 0xa5, 0x74, 0x74, 0x69, 0x65, 0x72)
+		o = msgp.AppendString(o, z.TransitionTier)
+	}
+	if (zb0001Mask & 0x200000) == 0 { // if not empty
+		// string "tobj"
+		o = append(o, 0xa4, 0x74, 0x6f, 0x62, 0x6a)
+		o = msgp.AppendString(o, z.TransitionedObject)
+	}
+	if (zb0001Mask & 0x400000) == 0 { // if not empty
+		// string "tvid"
+		o = append(o, 0xa4, 0x74, 0x76, 0x69, 0x64)
+		o = msgp.AppendString(o, z.TransitionedVersionID)
+	}
+	if (zb0001Mask & 0x800000) == 0 { // if not empty
+		// string "rexp"
+		o = append(o, 0xa4, 0x72, 0x65, 0x78, 0x70)
+		o = msgp.AppendInt64(o, z.RestoreExpires)
+	}
+	// string "size"
+	o = append(o, 0xa4, 0x73, 0x69, 0x7a, 0x65)
+	o = msgp.AppendInt(o, z.StatSize)
+	// string "mtime"
+	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
+	o = msgp.AppendInt64(o, z.StatModTime)
+	if (zb0001Mask & 0x4000000) == 0 { // if not empty
+		// string "msys"
+		o = append(o, 0xa4, 0x6d, 0x73, 0x79, 0x73)
+		o = msgp.AppendMapHeader(o, uint32(len(z.MetaSys)))
+		for za0004, za0005 := range z.MetaSys {
+			o = msgp.AppendString(o, za0004)
+			o = msgp.AppendBytes(o, za0005)
+		}
+	}
+	if (zb0001Mask & 0x8000000) == 0 { // if not empty
+		// string "muser"
+		o = append(o, 0xa5, 0x6d, 0x75, 0x73, 0x65, 0x72)
+		o = msgp.AppendMapHeader(o, uint32(len(z.MetaUser)))
+		for za0006, za0007 := range z.MetaUser {
+			o = msgp.AppendString(o, za0006)
+			o = msgp.AppendArrayHeader(o, uint32(len(za0007)))
+			for za0008 := range za0007 {
+				o = msgp.AppendString(o, za0007[za0008])
+			}
+		}
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ObjectMetaV2Object) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "id":
+			z.VersionID, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "VersionID")
+				return
+			}
+		case "dd":
+			z.DataDir, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataDir")
+				return
+			}
+		case "ealgo":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "DataErasureAlgorithm")
+					return
+				}
+				z.DataErasureAlgorithm = ErasureAlgo(zb0002)
+			}
+		case "m":
+			z.DataErasureM, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataErasureM")
+				return
+			}
+		case "n":
+			z.DataErasureN, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataErasureN")
+				return
+			}
+		case "bsize":
+			z.DataErasureBlockSize, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataErasureBlockSize")
+				return
+			}
+		case "index":
+			z.DataErasureIndex, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataErasureIndex")
+				return
+			}
+		case "dist":
+			var zb0003 uint32
+			zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataErasureDistribution")
+				return
+			}
+			if cap(z.DataErasureDistribution) >= int(zb0003) {
+				z.DataErasureDistribution = (z.DataErasureDistribution)[:zb0003]
+			} else {
+				z.DataErasureDistribution = make([]uint8, zb0003)
+			}
+			for za0001 := range z.DataErasureDistribution {
+				z.DataErasureDistribution[za0001], bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "DataErasureDistribution", za0001)
+					return
+				}
+			}
+		case "clago":
+			{
+				var zb0004 uint8
+				zb0004, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "DataErasureChecksumAlgo")
+					return
+				}
+				z.DataErasureChecksumAlgo = ChecksumAlgo(zb0004)
+			}
+		case "pnum":
+			bts, err = z.DataPartInfoNumbers.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoNumbers")
+				return
+			}
+		case "psz":
+			bts, err = z.DataPartInfoSizes.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoSizes")
+				return
+			}
+		case "pcsum":
+			var zb0005 uint32
+			zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "DataPartInfoChecksums")
+				return
+			}
+			if cap(z.DataPartInfoChecksums) >= int(zb0005) {
+				z.DataPartInfoChecksums = (z.DataPartInfoChecksums)[:zb0005]
+			} else {
+				z.DataPartInfoChecksums = make([][]byte, zb0005)
+			}
+			for za0002 := range z.DataPartInfoChecksums {
+				z.DataPartInfoChecksums[za0002], bts, err = msgp.ReadBytesBytes(bts, z.DataPartInfoChecksums[za0002])
+				if err != nil {
+					err = msgp.WrapError(err, "DataPartInfoChecksums", za0002)
+					return
+				}
+			}
+		case "inline":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.Inline = nil
+			} else {
+				if z.Inline == nil {
+					z.Inline = new(InlineData)
+				}
+				bts, err = z.Inline.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Inline")
+					return
+				}
+			}
+		case "rmode":
+			{
+				var zb0006 uint8
+				zb0006, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "RetentionMode")
+					return
+				}
+				z.RetentionMode = RetentionMode(zb0006)
+			}
+		case "runtil":
+			z.RetainUntil, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RetainUntil")
+				return
+			}
+		case "lhold":
+			z.LegalHold, bts, err = msgp.ReadBoolBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "LegalHold")
+				return
+			}
+		case "repl":
+			var zb0007 uint32
+			zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Replication")
+				return
+			}
+			if cap(z.Replication) >= int(zb0007) {
+				z.Replication = (z.Replication)[:zb0007]
+			} else {
+				z.Replication = make([]ObjectMetaV2Replication, zb0007)
+			}
+			for za0003 := range z.Replication {
+				bts, err = z.Replication[za0003].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Replication", za0003)
+					return
+				}
+			}
+		case "sse":
+			if msgp.IsNil(bts) {
+				bts, err = msgp.ReadNilBytes(bts)
+				if err != nil {
+					return
+				}
+				z.SSE = nil
+			} else {
+				if z.SSE == nil {
+					z.SSE = new(ObjectMetaV2SSE)
+				}
+				bts, err = z.SSE.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "SSE")
+					return
+				}
+			}
+		case "sc":
+			z.StorageClass, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "StorageClass")
+				return
+			}
+		case "tst":
+			{
+				var zb0008 uint8
+				zb0008, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TransitionStatus")
+					return
+				}
+				z.TransitionStatus = TransitionStatus(zb0008)
+			}
+		case "ttier":
+			z.TransitionTier, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionTier")
+				return
+			}
+		case "tobj":
+			z.TransitionedObject, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedObject")
+				return
+			}
+		case "tvid":
+			z.TransitionedVersionID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "TransitionedVersionID")
+				return
+			}
+		case "rexp":
+			z.RestoreExpires, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "RestoreExpires")
+				return
+			}
+		case "size":
+			z.StatSize, bts, err = msgp.ReadIntBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "StatSize")
+				return
+			}
+		case "mtime":
+			z.StatModTime, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "StatModTime")
+				return
+			}
+		case "msys":
+			var zb0009 uint32
+			zb0009, bts, err = msgp.ReadMapHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "MetaSys")
+				return
+			}
+			if z.MetaSys == nil {
+				z.MetaSys = make(map[string][]byte, zb0009)
+			} else if len(z.MetaSys) > 0 {
+				for key := range z.MetaSys {
+					delete(z.MetaSys, key)
+				}
+			}
+			for zb0009 > 0 {
+				var za0004 string
+				var za0005 []byte
+				zb0009--
+				za0004, bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "MetaSys")
+					return
+				}
+				za0005, bts, err = msgp.ReadBytesBytes(bts, za0005)
+				if err != nil {
+					err = msgp.WrapError(err, "MetaSys", za0004)
+					return
+				}
+				z.MetaSys[za0004] = za0005
+			}
+		case "muser":
+			var zb0010 uint32
+			zb0010, bts, err = msgp.ReadMapHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "MetaUser")
+				return
+			}
+			if z.MetaUser == nil {
+				z.MetaUser = make(map[string][]string, zb0010)
+			} else if len(z.MetaUser) > 0 {
+				for key := range z.MetaUser {
+					delete(z.MetaUser, key)
+				}
+			}
+			for zb0010 > 0 {
+				var za0006 string
+				var za0007 []string
+				zb0010--
+				za0006, bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "MetaUser")
+					return
+				}
+				var zb0011 uint32
+				zb0011, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "MetaUser", za0006)
+					return
+				}
+				if cap(za0007) >= int(zb0011) {
+					za0007 = (za0007)[:zb0011]
+				} else {
+					za0007 = make([]string, zb0011)
+				}
+				for za0008 := range za0007 {
+					za0007[za0008], bts, err = msgp.ReadStringBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "MetaUser", za0006, za0008)
+						return
+					}
+				}
+				z.MetaUser[za0006] = za0007
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2Object) Msgsize() (s int) {
+	s = 3 + 3 + msgp.Uint64Size + 3 + msgp.Uint64Size + 6 + msgp.Uint8Size + 2 + msgp.IntSize + 2 + msgp.IntSize + 6 + msgp.IntSize + 6 + msgp.IntSize + 5 + msgp.ArrayHeaderSize + (len(z.DataErasureDistribution) * (msgp.Uint8Size)) + 6 + msgp.Uint8Size + 5 + z.DataPartInfoNumbers.Msgsize() + 4 + z.DataPartInfoSizes.Msgsize() + 6 + msgp.ArrayHeaderSize
+	for za0002 := range z.DataPartInfoChecksums {
+		s += msgp.BytesPrefixSize + len(z.DataPartInfoChecksums[za0002])
+	}
+	s += 7
+	if z.Inline == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.Inline.Msgsize()
+	}
+	s += 6 + msgp.Uint8Size + 7 + msgp.Int64Size + 6 + msgp.BoolSize + 5 + msgp.ArrayHeaderSize
+	for za0003 := range z.Replication {
+		s += z.Replication[za0003].Msgsize()
+	}
+	s += 4
+	if z.SSE == nil {
+		s += msgp.NilSize
+	} else {
+		s += z.SSE.Msgsize()
+	}
+	s += 3 + msgp.StringPrefixSize + len(z.StorageClass) + 4 + msgp.Uint8Size + 6 + msgp.StringPrefixSize + len(z.TransitionTier) + 5 + msgp.StringPrefixSize + len(z.TransitionedObject) + 5 + msgp.StringPrefixSize + len(z.TransitionedVersionID) + 5 + msgp.Int64Size + 5 + msgp.IntSize + 6 + msgp.Int64Size + 5 + msgp.MapHeaderSize
+	if z.MetaSys != nil {
+		for za0004, za0005 := range z.MetaSys {
+			_ = za0005
+			s += msgp.StringPrefixSize + len(za0004) + msgp.BytesPrefixSize + len(za0005)
+		}
+	}
+	s += 6 + msgp.MapHeaderSize
+	if z.MetaUser != nil {
+		for za0006, za0007 := range z.MetaUser {
+			_ = za0007
+			s += msgp.StringPrefixSize + len(za0006) + msgp.ArrayHeaderSize
+			for za0008 := range za0007 {
+				s += msgp.StringPrefixSize + len(za0007[za0008])
+			}
+		}
+	}
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *ObjectMetaV2Replication) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "arn":
+			z.ARN, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "ARN")
+				return
+			}
+		case "st":
+			{
+				var zb0002 uint8
+				zb0002, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "Status")
+					return
+				}
+				z.Status = ReplicationStatus(zb0002)
+			}
+		case "mtime":
+			z.ModTime, err = dc.ReadInt64()
+			if err != nil {
+				err = msgp.WrapError(err, "ModTime")
+				return
+			}
+		case "resync":
+			z.Resync, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Resync")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *ObjectMetaV2Replication) EncodeMsg(en *msgp.Writer) (err error) {
+	// omitempty: check for empty values
+	zb0001Len := uint32(4)
+	var zb0001Mask uint8 /* 4 bits */
+	if z.Resync == "" {
+		zb0001Len--
+		zb0001Mask |= 0x8
+	}
+	// variable map header, size zb0001Len
+	err = en.Append(0x80 | uint8(zb0001Len))
+	if err != nil {
+		return
+	}
+	if zb0001Len == 0 {
+		return
+	}
+	// write "arn"
+	err = en.Append(0xa3, 0x61, 0x72, 0x6e)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.ARN)
+	if err != nil {
+		err = msgp.WrapError(err, "ARN")
+		return
+	}
+	// write "st"
+	err = en.Append(0xa2, 0x73, 0x74)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint8(uint8(z.Status))
+	if err != nil {
+		err = msgp.WrapError(err, "Status")
+		return
+	}
+	// write "mtime"
+	err = en.Append(0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteInt64(z.ModTime)
+	if err != nil {
+		err = msgp.WrapError(err, "ModTime")
+		return
+	}
+	if (zb0001Mask & 0x8) == 0 { // if not empty
+		// write "resync"
+		err = en.Append(0xa6, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.Resync)
+		if err != nil {
+			err = msgp.WrapError(err, "Resync")
+			return
+		}
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2Replication) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(4)
+	var zb0001Mask uint8 /* 4 bits */
+	if z.Resync == "" {
+		zb0001Len--
+		zb0001Mask |= 0x8
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len == 0 {
+		return
+	}
+	// string "arn"
+	o = append(o, 0xa3, 0x61, 0x72, 0x6e)
+	o = msgp.AppendString(o, z.ARN)
+	// string "st"
+	o = append(o, 0xa2, 0x73, 0x74)
+	o = msgp.AppendUint8(o, uint8(z.Status))
+	// string "mtime"
+	o = append(o, 0xa5, 0x6d, 0x74, 0x69, 0x6d, 0x65)
+	o = msgp.AppendInt64(o, z.ModTime)
+	if (zb0001Mask & 0x8) == 0 { // if not empty
+		// string "resync"
+		o = append(o, 0xa6, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63)
+		o = msgp.AppendString(o, z.Resync)
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ObjectMetaV2Replication) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "arn":
+			z.ARN, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ARN")
+				return
+			}
+		case "st":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Status")
+					return
+				}
+				z.Status = ReplicationStatus(zb0002)
+			}
+		case "mtime":
+			z.ModTime, bts, err = msgp.ReadInt64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "ModTime")
+				return
+			}
+		case "resync":
+			z.Resync, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Resync")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2Replication) Msgsize() (s int) {
+	s = 1 + 4 + msgp.StringPrefixSize + len(z.ARN) + 3 + msgp.Uint8Size + 6 + msgp.Int64Size + 7 + msgp.StringPrefixSize + len(z.Resync)
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *ObjectMetaV2SSE) DecodeMsg(dc *msgp.Reader) (err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, err = dc.ReadMapHeader()
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, err = dc.ReadMapKeyPtr()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "type":
+			{
+				var zb0002 uint8
+				zb0002, err = dc.ReadUint8()
+				if err != nil {
+					err = msgp.WrapError(err, "Type")
+					return
+				}
+				z.Type = SSEType(zb0002)
+			}
+		case "algo":
+			z.Algorithm, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "Algorithm")
+				return
+			}
+		case "okey":
+			z.SealedKey, err = dc.ReadBytes(z.SealedKey)
+			if err != nil {
+				err = msgp.WrapError(err, "SealedKey")
+				return
+			}
+		case "iv":
+			z.IV, err = dc.ReadBytes(z.IV)
+			if err != nil {
+				err = msgp.WrapError(err, "IV")
+				return
+			}
+		case "kid":
+			z.KeyID, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "KeyID")
+				return
+			}
+		case "dkey":
+			z.DataKey, err = dc.ReadBytes(z.DataKey)
+			if err != nil {
+				err = msgp.WrapError(err, "DataKey")
+				return
+			}
+		case "ctx":
+			z.Context, err = dc.ReadBytes(z.Context)
+			if err != nil {
+				err = msgp.WrapError(err, "Context")
+				return
+			}
+		case "psz":
+			err = z.PartSizes.DecodeMsg(dc)
+			if err != nil {
+				err = msgp.WrapError(err, "PartSizes")
+				return
+			}
+		default:
+			err = dc.Skip()
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z *ObjectMetaV2SSE) EncodeMsg(en *msgp.Writer) (err error) {
+	// omitempty: check for empty values
+	zb0001Len := uint32(8)
+	var zb0001Mask uint8 /* 8 bits */
+	if z.KeyID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
+	if z.DataKey == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20
+	}
+	if z.Context == nil {
+		zb0001Len--
+		zb0001Mask |= 0x40
+	}
+	// variable map header, size zb0001Len
+	err = en.Append(0x80 | uint8(zb0001Len))
+	if err != nil {
+		return
+	}
+	if zb0001Len == 0 {
+		return
+	}
+	// write "type"
+	err = en.Append(0xa4, 0x74, 0x79, 0x70, 0x65)
+	if err != nil {
+		return
+	}
+	err = en.WriteUint8(uint8(z.Type))
+	if err != nil {
+		err = msgp.WrapError(err, "Type")
+		return
+	}
+	// write "algo"
+	err = en.Append(0xa4, 0x61, 0x6c, 0x67, 0x6f)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.Algorithm)
+	if err != nil {
+		err = msgp.WrapError(err, "Algorithm")
+		return
+	}
+	// write "okey"
+	err = en.Append(0xa4, 0x6f, 0x6b, 0x65, 0x79)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.SealedKey)
+	if err != nil {
+		err = msgp.WrapError(err, "SealedKey")
+		return
+	}
+	// write "iv"
+	err = en.Append(0xa2, 0x69, 0x76)
+	if err != nil {
+		return
+	}
+	err = en.WriteBytes(z.IV)
+	if err != nil {
+		err = msgp.WrapError(err, "IV")
+		return
+	}
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// write "kid"
+		err = en.Append(0xa3, 0x6b, 0x69, 0x64)
+		if err != nil {
+			return
+		}
+		err = en.WriteString(z.KeyID)
+		if err != nil {
+			err = msgp.WrapError(err, "KeyID")
+			return
+		}
+	}
+	if (zb0001Mask & 0x20) == 0 { // if not empty
+		// write "dkey"
+		err = en.Append(0xa4, 0x64, 0x6b, 0x65, 0x79)
+		if err != nil {
+			return
+		}
+		err = en.WriteBytes(z.DataKey)
+		if err != nil {
+			err = msgp.WrapError(err, "DataKey")
+			return
+		}
+	}
+	if (zb0001Mask & 0x40) == 0 { // if not empty
+		// write "ctx"
+		err = en.Append(0xa3, 0x63, 0x74, 0x78)
+		if err != nil {
+			return
+		}
+		err = en.WriteBytes(z.Context)
+		if err != nil {
+			err = msgp.WrapError(err, "Context")
+			return
+		}
+	}
+	// write "psz"
+	err = en.Append(0xa3, 0x70, 0x73, 0x7a)
+	if err != nil {
+		return
+	}
+	err = z.PartSizes.EncodeMsg(en)
+	if err != nil {
+		err = msgp.WrapError(err, "PartSizes")
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *ObjectMetaV2SSE) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(8)
+	var zb0001Mask uint8 /* 8 bits */
+	if z.KeyID == "" {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
+	if z.DataKey == nil {
+		zb0001Len--
+		zb0001Mask |= 0x20
+	}
+	if z.Context == nil {
+		zb0001Len--
+		zb0001Mask |= 0x40
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len == 0 {
+		return
+	}
+	// string "type"
+	o = append(o, 0xa4, 0x74, 0x79, 0x70, 0x65)
+	o = msgp.AppendUint8(o, uint8(z.Type))
+	// string "algo"
+	o = append(o, 0xa4, 0x61, 0x6c, 0x67, 0x6f)
+	o = msgp.AppendString(o, z.Algorithm)
+	// string "okey"
+	o = append(o, 0xa4, 0x6f, 0x6b, 0x65, 0x79)
+	o = msgp.AppendBytes(o, z.SealedKey)
+	// string "iv"
+	o = append(o, 0xa2, 0x69, 0x76)
+	o = msgp.AppendBytes(o, z.IV)
+	if (zb0001Mask & 0x10) == 0 { // if not empty
+		// string "kid"
+		o = append(o, 0xa3, 0x6b, 0x69, 0x64)
+		o = msgp.AppendString(o, z.KeyID)
+	}
+	if (zb0001Mask & 0x20) == 0 { // if not empty
+		// string "dkey"
+		o = append(o, 0xa4, 0x64, 0x6b, 0x65, 0x79)
+		o = msgp.AppendBytes(o, z.DataKey)
+	}
+	if (zb0001Mask & 0x40) == 0 { // if not empty
+		// string "ctx"
+		o = append(o, 0xa3, 0x63, 0x74, 0x78)
+		o = msgp.AppendBytes(o, z.Context)
+	}
+	// string "psz"
+	o = append(o, 0xa3, 0x70, 0x73, 0x7a)
+	o, err = z.PartSizes.MarshalMsg(o)
+	if err != nil {
+		err = msgp.WrapError(err, "PartSizes")
+		return
+	}
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ObjectMetaV2SSE) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 uint32
+	zb0001, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	for zb0001 > 0 {
+		zb0001--
+		field, bts, err = msgp.ReadMapKeyZC(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		switch msgp.UnsafeString(field) {
+		case "type":
+			{
+				var zb0002 uint8
+				zb0002, bts, err = msgp.ReadUint8Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Type")
+					return
+				}
+				z.Type = SSEType(zb0002)
+			}
+		case "algo":
+			z.Algorithm, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Algorithm")
+				return
+			}
+		case "okey":
+			z.SealedKey, bts, err = msgp.ReadBytesBytes(bts, z.SealedKey)
+			if err != nil {
+				err = msgp.WrapError(err, "SealedKey")
+				return
+			}
+		case "iv":
+			z.IV, bts, err = msgp.ReadBytesBytes(bts, z.IV)
+			if err != nil {
+				err = msgp.WrapError(err, "IV")
+				return
+			}
+		case "kid":
+			z.KeyID, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "KeyID")
+				return
+			}
+		case "dkey":
+			z.DataKey, bts, err = msgp.ReadBytesBytes(bts, z.DataKey)
+			if err != nil {
+				err = msgp.WrapError(err, "DataKey")
+				return
+			}
+		case "ctx":
+			z.Context, bts, err = msgp.ReadBytesBytes(bts, z.Context)
+			if err != nil {
+				err = msgp.WrapError(err, "Context")
+				return
+			}
+		case "psz":
+			bts, err = z.PartSizes.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "PartSizes")
+				return
+			}
+		default:
+			bts, err = msgp.Skip(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *ObjectMetaV2SSE) Msgsize() (s int) {
+	s = 1 + 5 + msgp.Uint8Size + 5 + msgp.StringPrefixSize + len(z.Algorithm) + 5 + msgp.BytesPrefixSize + len(z.SealedKey) + 3 + msgp.BytesPrefixSize + len(z.IV) + 4 + msgp.StringPrefixSize + len(z.KeyID) + 5 + msgp.BytesPrefixSize + len(z.DataKey) + 4 + msgp.BytesPrefixSize + len(z.Context) + 4 + z.PartSizes.Msgsize()
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *ReplicationStatus) DecodeMsg(dc *msgp.Reader) (err error) {
+	{
+		var zb0001 uint8
+		zb0001, err = dc.ReadUint8()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = ReplicationStatus(zb0001)
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z ReplicationStatus) EncodeMsg(en *msgp.Writer) (err error) {
+	err = en.WriteUint8(uint8(z))
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z ReplicationStatus) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendUint8(o, uint8(z))
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *ReplicationStatus) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 uint8
+		zb0001, bts, err = msgp.ReadUint8Bytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = ReplicationStatus(zb0001)
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z ReplicationStatus) Msgsize() (s int) {
+	s = msgp.Uint8Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *RetentionMode) DecodeMsg(dc *msgp.Reader) (err error) {
+	{
+		var zb0001 uint8
+		zb0001, err = dc.ReadUint8()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = RetentionMode(zb0001)
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z RetentionMode) EncodeMsg(en *msgp.Writer) (err error) {
+	err = en.WriteUint8(uint8(z))
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z RetentionMode) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendUint8(o, uint8(z))
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *RetentionMode) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 uint8
+		zb0001, bts, err = msgp.ReadUint8Bytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = RetentionMode(zb0001)
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z RetentionMode) Msgsize() (s int) {
+	s = msgp.Uint8Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *SSEType) DecodeMsg(dc *msgp.Reader) (err error) {
+	{
+		var zb0001 uint8
+		zb0001, err = dc.ReadUint8()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = SSEType(zb0001)
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z SSEType) EncodeMsg(en *msgp.Writer) (err error) {
+	err = en.WriteUint8(uint8(z))
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z SSEType) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendUint8(o, uint8(z))
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *SSEType) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 uint8
+		zb0001, bts, err = msgp.ReadUint8Bytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = SSEType(zb0001)
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z SSEType) Msgsize() (s int) {
+	s = msgp.Uint8Size
+	return
+}
+
+// DecodeMsg implements msgp.Decodable
+func (z *TransitionStatus) DecodeMsg(dc *msgp.Reader) (err error) {
+	{
+		var zb0001 uint8
+		zb0001, err = dc.ReadUint8()
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = TransitionStatus(zb0001)
+	}
+	return
+}
+
+// EncodeMsg implements msgp.Encodable
+func (z TransitionStatus) EncodeMsg(en *msgp.Writer) (err error) {
+	err = en.WriteUint8(uint8(z))
+	if err != nil {
+		err = msgp.WrapError(err)
+		return
+	}
+	return
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z TransitionStatus) MarshalMsg(b []byte) (o []byte, err error) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendUint8(o, uint8(z))
+	return
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *TransitionStatus) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 uint8
+		zb0001, bts, err = msgp.ReadUint8Bytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = TransitionStatus(zb0001)
+	}
+	o = bts
+	return
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z TransitionStatus) Msgsize() (s int) {
+	s = msgp.Uint8Size
 	return
 }
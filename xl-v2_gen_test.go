@@ -1,4 +1,4 @@
-package main
+package xlmeta
 
 // Code generated by github.com/tinylib/msgp DO NOT EDIT.
 